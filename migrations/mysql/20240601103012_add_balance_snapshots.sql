@@ -0,0 +1,26 @@
+-- +up
+CREATE TABLE `balance_snapshots`
+(
+    `gid`       BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+
+    `session`   VARCHAR(30)     NOT NULL,
+
+    `exchange`  VARCHAR(30)     NOT NULL,
+
+    `currency`  VARCHAR(12)     NOT NULL,
+
+    `available` DECIMAL(32, 8)  NOT NULL DEFAULT 0.00000000,
+
+    `locked`    DECIMAL(32, 8)  NOT NULL DEFAULT 0.00000000,
+
+    -- valuation is the value of the balance in the valuation currency, e.g. USDT
+    `valuation` DECIMAL(32, 8)  NOT NULL DEFAULT 0.00000000,
+
+    `time`      DATETIME(3)     NOT NULL,
+
+    PRIMARY KEY (`gid`),
+    INDEX idx_balance_snapshots_exchange_time (`exchange`, `time`)
+);
+
+-- +down
+DROP TABLE IF EXISTS `balance_snapshots`;
@@ -103,6 +103,12 @@ func (c *AverageCostCalculator) Calculate(symbol string, trades []types.Trade, c
 	unrealizedProfit := currentPrice.Sub(position.AverageCost).
 		Mul(position.GetBase())
 
+	// the profit is in the quote currency, convert the fee into USD if the quote currency is a stable coin
+	feeUSD = totalProfit.Sub(totalNetProfit)
+	if priceInUSD, ok := types.StableCoinPriceInUSD(c.Market.QuoteCurrency, nil); ok {
+		feeUSD = feeUSD.Mul(priceInUSD)
+	}
+
 	return &AverageCostPnLReport{
 		Symbol:    symbol,
 		Market:    c.Market,
@@ -123,7 +129,7 @@ func (c *AverageCostCalculator) Calculate(symbol string, trades []types.Trade, c
 		GrossLoss:   grossLoss,
 
 		AverageCost:  position.AverageCost,
-		FeeInUSD:     totalProfit.Sub(totalNetProfit),
+		FeeInUSD:     feeUSD,
 		CurrencyFees: currencyFees,
 	}
 }
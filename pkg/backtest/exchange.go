@@ -149,6 +149,7 @@ func (e *Exchange) _addMatchingBook(symbol string, market types.Market) {
 		Market:          market,
 		closedOrders:    make(map[uint64]types.Order),
 		feeModeFunction: getFeeModeFunction(e.config.FeeMode),
+		slippage:        e.config.SlippagePercent,
 	}
 
 	e.matchingBooks[symbol] = matching
@@ -0,0 +1,132 @@
+package backtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func newTestExchange(market types.Market, balances types.BalanceMap) *Exchange {
+	account := &types.Account{AccountType: types.AccountTypeSpot}
+	account.UpdateBalances(balances)
+
+	e := &Exchange{
+		sourceName:       types.ExchangeBinance,
+		markets:          types.MarketMap{market.Symbol: market},
+		config:           &bbgo.Backtest{},
+		account:          account,
+		closedOrders:     make(map[string][]types.Order),
+		trades:           make(map[string][]types.Trade),
+		MarketDataStream: &types.StandardStream{},
+		Src:              &ExchangeDataSource{},
+	}
+	e.resetMatchingBooks()
+	return e
+}
+
+func TestExchange_ReplayKLines(t *testing.T) {
+	market := types.Market{
+		Symbol:          "BTCUSDT",
+		PricePrecision:  2,
+		VolumePrecision: 4,
+		QuoteCurrency:   "USDT",
+		BaseCurrency:    "BTC",
+		MinNotional:     fixedpoint.MustNewFromString("5"),
+		MinQuantity:     fixedpoint.MustNewFromString("0.0001"),
+		StepSize:        fixedpoint.MustNewFromString("0.0001"),
+		TickSize:        fixedpoint.MustNewFromString("0.01"),
+	}
+
+	e := newTestExchange(market, types.BalanceMap{
+		"USDT": {Currency: "USDT", Available: fixedpoint.NewFromFloat(1000.0)},
+	})
+
+	userDataStream := &types.StandardStream{}
+	e.BindUserData(userDataStream)
+
+	var trades []types.Trade
+	userDataStream.OnTradeUpdate(func(trade types.Trade) {
+		trades = append(trades, trade)
+	})
+
+	// a tiny strategy: buy the dip below the first close, then sell above the entry
+	ctx := context.Background()
+	var closedKLines []types.KLine
+	e.MarketDataStream.OnKLineClosed(func(k types.KLine) {
+		closedKLines = append(closedKLines, k)
+
+		var order types.SubmitOrder
+		switch len(closedKLines) {
+		case 1:
+			order = types.SubmitOrder{Side: types.SideTypeBuy, Price: fixedpoint.NewFromFloat(95.0)}
+		case 3:
+			order = types.SubmitOrder{Side: types.SideTypeSell, Price: fixedpoint.NewFromFloat(105.0)}
+		default:
+			return
+		}
+
+		order.Symbol = "BTCUSDT"
+		order.Type = types.OrderTypeLimit
+		order.Quantity = fixedpoint.One
+		order.Market = market
+		order.TimeInForce = types.TimeInForceGTC
+		_, err := e.SubmitOrder(ctx, order)
+		assert.NoError(t, err)
+	})
+
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	bars := []struct{ open, high, low, close float64 }{
+		{100, 101, 99, 100},
+		{100, 101, 96, 98}, // the buy order at 95 is not crossed
+		{98, 99, 94, 97},   // the buy order at 95 is filled
+		{97, 104, 96, 103}, // the sell order at 105 is not crossed
+		{103, 106, 102, 104},
+		{104, 105, 103, 104}, // flush the last bar
+	}
+
+	for i, bar := range bars {
+		e.ConsumeKLine(types.KLine{
+			Exchange:  types.ExchangeBinance,
+			Symbol:    "BTCUSDT",
+			Interval:  types.Interval1m,
+			StartTime: types.Time(startTime.Add(time.Duration(i) * time.Minute)),
+			EndTime:   types.Time(startTime.Add(time.Duration(i+1)*time.Minute - time.Millisecond)),
+			Open:      fixedpoint.NewFromFloat(bar.open),
+			High:      fixedpoint.NewFromFloat(bar.high),
+			Low:       fixedpoint.NewFromFloat(bar.low),
+			Close:     fixedpoint.NewFromFloat(bar.close),
+			Closed:    true,
+		}, types.Interval1m)
+	}
+
+	// the klines are emitted in time order
+	if assert.Len(t, closedKLines, 5) {
+		for i := 1; i < len(closedKLines); i++ {
+			assert.True(t, closedKLines[i].StartTime.After(closedKLines[i-1].StartTime.Time()))
+		}
+	}
+
+	if assert.Len(t, trades, 2) {
+		assert.Equal(t, types.SideTypeBuy, trades[0].Side)
+		assert.Equal(t, "95", trades[0].Price.String())
+		assert.Equal(t, types.SideTypeSell, trades[1].Side)
+		assert.Equal(t, "105", trades[1].Price.String())
+	}
+
+	balances, err := e.QueryAccountBalances(ctx)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "1010", balances["USDT"].Available.String())
+		assert.Equal(t, "0", balances["BTC"].Available.String())
+	}
+
+	openOrders, err := e.QueryOpenOrders(ctx, "BTCUSDT")
+	if assert.NoError(t, err) {
+		assert.Empty(t, openOrders)
+	}
+}
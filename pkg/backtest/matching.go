@@ -66,6 +66,10 @@ type SimplePriceMatching struct {
 
 	feeModeFunction FeeModeFunction
 
+	// slippage is the price slippage ratio applied to the market orders,
+	// the buy market orders are filled at a higher price and the sell market orders are filled at a lower price.
+	slippage fixedpoint.Value
+
 	account *types.Account
 
 	tradeUpdateCallbacks   []func(trade types.Trade)
@@ -142,7 +146,7 @@ func (m *SimplePriceMatching) PlaceOrder(o types.SubmitOrder) (*types.Order, *ty
 
 	switch o.Type {
 	case types.OrderTypeMarket:
-		price = m.Market.TruncatePrice(m.lastPrice)
+		price = m.marketOrderPrice(o.Side)
 
 	case types.OrderTypeStopMarket:
 		// the actual price might be different.
@@ -186,7 +190,7 @@ func (m *SimplePriceMatching) PlaceOrder(o types.SubmitOrder) (*types.Order, *ty
 	if isTaker {
 		var price fixedpoint.Value
 		if order.Type == types.OrderTypeMarket {
-			order.Price = m.Market.TruncatePrice(m.lastPrice)
+			order.Price = m.marketOrderPrice(order.Side)
 			price = order.Price
 		} else if order.Type == types.OrderTypeLimit {
 			// if limit order's price is with the range of next kline
@@ -271,6 +275,22 @@ func (m *SimplePriceMatching) PlaceOrder(o types.SubmitOrder) (*types.Order, *ty
 	return &order, nil, nil
 }
 
+// marketOrderPrice returns the executed price of the market order with the slippage applied
+func (m *SimplePriceMatching) marketOrderPrice(side types.SideType) fixedpoint.Value {
+	price := m.lastPrice
+	if m.slippage.Sign() > 0 {
+		delta := price.Mul(m.slippage)
+		switch side {
+		case types.SideTypeBuy:
+			price = price.Add(delta)
+		case types.SideTypeSell:
+			price = price.Sub(delta)
+		}
+	}
+
+	return m.Market.TruncatePrice(price)
+}
+
 func (m *SimplePriceMatching) executeTrade(trade types.Trade) {
 	var err error
 	// execute trade, update account balances
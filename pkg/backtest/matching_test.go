@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/c9s/bbgo/pkg/bbgo"
 	"github.com/c9s/bbgo/pkg/fixedpoint"
 	"github.com/c9s/bbgo/pkg/types"
 )
@@ -527,3 +528,49 @@ func TestSimplePriceMatching_LimitTakerOrder(t *testing.T) {
 		}
 	}
 }
+
+func TestSimplePriceMatching_MarketOrderSlippage(t *testing.T) {
+	account := getTestAccount()
+	market := getTestMarket()
+	engine := &SimplePriceMatching{
+		account:         account,
+		Market:          market,
+		closedOrders:    make(map[uint64]types.Order),
+		lastPrice:       fixedpoint.NewFromFloat(20000.0),
+		feeModeFunction: getFeeModeFunction(bbgo.BacktestFeeModeQuote),
+		slippage:        fixedpoint.MustNewFromString("0.1%"),
+	}
+
+	createdOrder, trade, err := engine.PlaceOrder(types.SubmitOrder{
+		Symbol:   market.Symbol,
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeMarket,
+		Quantity: fixedpoint.NewFromFloat(0.1),
+	})
+	if assert.NoError(t, err) && assert.NotNil(t, trade) {
+		assert.Equal(t, types.OrderStatusFilled, createdOrder.Status)
+		assert.False(t, trade.IsMaker)
+		assert.Equal(t, "20020", trade.Price.String(), "buy market order should be filled at a higher price")
+		assert.Equal(t, "2002", trade.QuoteQuantity.String())
+		// taker fee rate 0.075%
+		assert.Equal(t, "1.5015", trade.Fee.String())
+		assert.Equal(t, "USDT", trade.FeeCurrency)
+	}
+
+	usdt, ok := account.Balance("USDT")
+	if assert.True(t, ok) {
+		assert.Equal(t, "997996.4985", usdt.Available.String())
+		assert.Equal(t, "0", usdt.Locked.String())
+	}
+
+	_, trade, err = engine.PlaceOrder(types.SubmitOrder{
+		Symbol:   market.Symbol,
+		Side:     types.SideTypeSell,
+		Type:     types.OrderTypeMarket,
+		Quantity: fixedpoint.NewFromFloat(0.1),
+	})
+	if assert.NoError(t, err) && assert.NotNil(t, trade) {
+		assert.Equal(t, "19980", trade.Price.String(), "sell market order should be filled at a lower price")
+		assert.Equal(t, "1.4985", trade.Fee.String())
+	}
+}
@@ -18,6 +18,11 @@ func BootstrapEnvironmentLightweight(ctx context.Context, environ *Environment,
 		environ.SetLogging(userConfig.Logging)
 	}
 
+	if userConfig.Environment != nil {
+		// only apply the startup options here, the other environment options are not applied by the bootstrap
+		environ.SetPingOnStartup(userConfig.Environment.PingOnStartup)
+	}
+
 	if userConfig.Persistence != nil {
 		if err := ConfigurePersistence(ctx, environ, userConfig.Persistence); err != nil {
 			return errors.Wrap(err, "persistence configure error")
@@ -46,6 +51,11 @@ func BootstrapEnvironment(ctx context.Context, environ *Environment, userConfig
 		environ.SetLogging(userConfig.Logging)
 	}
 
+	if userConfig.Environment != nil {
+		// only apply the startup options here, the other environment options are not applied by the bootstrap
+		environ.SetPingOnStartup(userConfig.Environment.PingOnStartup)
+	}
+
 	if userConfig.Persistence != nil {
 		if err := ConfigurePersistence(ctx, environ, userConfig.Persistence); err != nil {
 			return errors.Wrap(err, "persistence configure error")
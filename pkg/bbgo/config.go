@@ -85,10 +85,24 @@ type NotificationSwitches struct {
 	SubmitOrder bool `json:"submitOrder" yaml:"submitOrder"`
 }
 
+// NotificationThrottleConfig configures the notification throttle,
+// similar notifications within the window are coalesced into one summary message
+type NotificationThrottleConfig struct {
+	Window types.Duration `json:"window" yaml:"window"`
+
+	// MaxPerWindow is the maximum number of notifications sent to a channel in the window, 0 means no limit
+	MaxPerWindow int `json:"maxPerWindow,omitempty" yaml:"maxPerWindow,omitempty"`
+
+	// CoalesceByFormat coalesces the string notifications with the same format string even if the arguments differ,
+	// by default they are coalesced only when the rendered messages are the same
+	CoalesceByFormat bool `json:"coalesceByFormat,omitempty" yaml:"coalesceByFormat,omitempty"`
+}
+
 type NotificationConfig struct {
-	Slack    *SlackNotification    `json:"slack,omitempty" yaml:"slack,omitempty"`
-	Telegram *TelegramNotification `json:"telegram,omitempty" yaml:"telegram,omitempty"`
-	Switches *NotificationSwitches `json:"switches" yaml:"switches"`
+	Slack    *SlackNotification          `json:"slack,omitempty" yaml:"slack,omitempty"`
+	Telegram *TelegramNotification       `json:"telegram,omitempty" yaml:"telegram,omitempty"`
+	Switches *NotificationSwitches       `json:"switches" yaml:"switches"`
+	Throttle *NotificationThrottleConfig `json:"throttle,omitempty" yaml:"throttle,omitempty"`
 }
 
 type LoggingConfig struct {
@@ -145,6 +159,9 @@ type Backtest struct {
 
 	FeeMode BacktestFeeMode `json:"feeMode" yaml:"feeMode"`
 
+	// SlippagePercent is the price slippage applied to the market orders, e.g. 0.05% or 0.0005
+	SlippagePercent fixedpoint.Value `json:"slippagePercent,omitempty" yaml:"slippagePercent,omitempty"`
+
 	Accounts map[string]BacktestAccount `json:"accounts" yaml:"accounts"`
 	Symbols  []string                   `json:"symbols" yaml:"symbols"`
 	Sessions []string                   `json:"sessions" yaml:"sessions"`
@@ -346,6 +363,17 @@ type EnvironmentConfig struct {
 	DisableMarketDataStore bool `json:"disableMarketDataStore"`
 
 	MaxSessionTradeBufferSize int `json:"maxSessionTradeBufferSize"`
+
+	// PingOnStartup pings the exchanges that support it before initializing the sessions,
+	// so that the network and the api credential errors are reported before anything else.
+	PingOnStartup bool `json:"pingOnStartup"`
+
+	// BalanceSnapshotInterval is the interval of recording the session balances into the database,
+	// the snapshots are used for the equity curve analysis, zero disables it.
+	BalanceSnapshotInterval types.Duration `json:"balanceSnapshotInterval"`
+
+	// BalanceSnapshotCurrency is the currency of the balance snapshot valuation, defaults to USDT
+	BalanceSnapshotCurrency string `json:"balanceSnapshotCurrency"`
 }
 
 type Config struct {
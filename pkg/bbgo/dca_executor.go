@@ -0,0 +1,288 @@
+package bbgo
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// DCAExecutor places a market buy order with a fixed quote amount on a schedule (dollar-cost averaging),
+// the schedule can be a fixed interval or a cron expression.
+// It tracks the cumulative invested quote amount and the average entry price from the fills of the buy orders.
+type DCAExecutor struct {
+	Symbol string `json:"symbol"`
+
+	// QuoteAmount is the quote amount to invest for each buy
+	QuoteAmount fixedpoint.Value `json:"quoteAmount"`
+
+	// Interval is the fixed interval between each buy
+	Interval types.Duration `json:"interval,omitempty"`
+
+	// Schedule is the cron expression of the buy schedule, e.g. "0 0 * * *", it's used when Interval is not set
+	Schedule string `json:"schedule,omitempty"`
+
+	// TotalInvested is the cumulative filled quote amount
+	TotalInvested fixedpoint.Value `json:"totalInvested,omitempty" persistence:"total_invested"`
+
+	// TotalQuantity is the cumulative filled base quantity
+	TotalQuantity fixedpoint.Value `json:"totalQuantity,omitempty" persistence:"total_quantity"`
+
+	// NextTime is the time of the next buy
+	NextTime time.Time `json:"nextTime,omitempty" persistence:"next_time"`
+
+	// now is the clock used for the schedule, it can be replaced for testing
+	now func() time.Time
+
+	cronSchedule cron.Schedule
+
+	mu            sync.Mutex
+	session       *ExchangeSession
+	orderExecutor OrderExecutorExtended
+
+	// fillMu guards the totals and the fill tracking fields below,
+	// it's separated from mu since the trades can be emitted while the buy order is being submitted
+	fillMu sync.Mutex
+
+	// remainingQuantities is the unfilled quantity of the submitted buy orders, keyed by the order ID
+	remainingQuantities map[uint64]fixedpoint.Value
+
+	// pendingTrades buffers the trades that arrive before the submitted orders are returned
+	pendingTrades []types.Trade
+	submitting    bool
+}
+
+// SetClock replaces the clock used for the schedule
+func (e *DCAExecutor) SetClock(now func() time.Time) {
+	e.now = now
+}
+
+func (e *DCAExecutor) Validate() error {
+	if e.Symbol == "" {
+		return fmt.Errorf("dca: symbol is required")
+	}
+
+	if e.QuoteAmount.Sign() <= 0 {
+		return fmt.Errorf("dca: quoteAmount should be positive")
+	}
+
+	if e.Interval.Duration() <= 0 && e.Schedule == "" {
+		return fmt.Errorf("dca: either interval or schedule is required")
+	}
+
+	if e.Interval.Duration() <= 0 {
+		if _, err := cron.ParseStandard(e.Schedule); err != nil {
+			return fmt.Errorf("dca: invalid schedule %q: %w", e.Schedule, err)
+		}
+	}
+
+	return nil
+}
+
+func (e *DCAExecutor) Bind(session *ExchangeSession, orderExecutor OrderExecutorExtended) error {
+	if err := e.Validate(); err != nil {
+		return err
+	}
+
+	if e.Interval.Duration() <= 0 {
+		schedule, err := cron.ParseStandard(e.Schedule)
+		if err != nil {
+			return err
+		}
+
+		e.cronSchedule = schedule
+	}
+
+	if e.now == nil {
+		e.now = time.Now
+	}
+
+	e.session = session
+	e.orderExecutor = orderExecutor
+	e.remainingQuantities = make(map[uint64]fixedpoint.Value)
+
+	if orderExecutor != nil {
+		orderExecutor.TradeCollector().OnTrade(func(trade types.Trade, _, _ fixedpoint.Value) {
+			e.handleTrade(trade)
+		})
+	}
+
+	if e.NextTime.IsZero() {
+		e.NextTime = e.nextTime(e.now())
+	}
+
+	return nil
+}
+
+// Run checks the schedule every minute until the context is canceled
+func (e *DCAExecutor) Run(ctx context.Context) {
+	types.GoWithContext(ctx, func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case <-ticker.C:
+				e.Tick(ctx)
+			}
+		}
+	})
+}
+
+// AverageEntryPrice returns the volume-weighted average price of the fills
+func (e *DCAExecutor) AverageEntryPrice() fixedpoint.Value {
+	e.fillMu.Lock()
+	defer e.fillMu.Unlock()
+
+	if e.TotalQuantity.IsZero() {
+		return fixedpoint.Zero
+	}
+
+	return e.TotalInvested.Div(e.TotalQuantity)
+}
+
+func (e *DCAExecutor) nextTime(t time.Time) time.Time {
+	if e.cronSchedule != nil {
+		return e.cronSchedule.Next(t)
+	}
+
+	return t.Add(e.Interval.Duration())
+}
+
+// Tick places the buy order when the scheduled time is reached
+func (e *DCAExecutor) Tick(ctx context.Context) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := e.now()
+	if now.Before(e.NextTime) {
+		return
+	}
+
+	// schedule the next buy from the current time, the missed schedules are not compensated
+	e.NextTime = e.nextTime(now)
+
+	if err := e.buy(ctx); err != nil {
+		log.WithError(err).Errorf("[DCAExecutor] %s buy failed", e.Symbol)
+	}
+}
+
+func (e *DCAExecutor) buy(ctx context.Context) error {
+	market, ok := e.session.Market(e.Symbol)
+	if !ok {
+		return fmt.Errorf("market %s not found", e.Symbol)
+	}
+
+	balance, ok := e.session.GetAccount().Balance(market.QuoteCurrency)
+	if !ok || balance.Available.Compare(e.QuoteAmount) < 0 {
+		log.Warnf("[DCAExecutor] insufficient %s balance for buying %s with %s %s, skip",
+			market.QuoteCurrency, e.Symbol, e.QuoteAmount.String(), market.QuoteCurrency)
+		return nil
+	}
+
+	ticker, err := e.session.Exchange.QueryTicker(ctx, e.Symbol)
+	if err != nil {
+		return err
+	}
+
+	// the order quantity is sized by the best ask price, the entry price is taken from the fills
+	price := ticker.Sell
+	if price.IsZero() {
+		price = ticker.Last
+	}
+
+	if price.IsZero() {
+		return fmt.Errorf("invalid %s ticker price", e.Symbol)
+	}
+
+	quantity := market.TruncateQuantity(e.QuoteAmount.Div(price))
+	if market.IsDustQuantity(quantity, price) {
+		return fmt.Errorf("buy quantity %s is too small", quantity.String())
+	}
+
+	e.fillMu.Lock()
+	e.submitting = true
+	e.fillMu.Unlock()
+
+	createdOrders, err := e.orderExecutor.SubmitOrders(ctx, types.SubmitOrder{
+		Symbol:   e.Symbol,
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeMarket,
+		Quantity: quantity,
+		Market:   market,
+		Tag:      "dca",
+	})
+
+	e.fillMu.Lock()
+	defer e.fillMu.Unlock()
+
+	pendingTrades := e.pendingTrades
+	e.pendingTrades = nil
+	e.submitting = false
+
+	if err != nil {
+		return err
+	}
+
+	for _, order := range createdOrders {
+		e.remainingQuantities[order.OrderID] = order.Quantity
+	}
+
+	for _, trade := range pendingTrades {
+		e.addFill(trade)
+	}
+
+	log.Infof("[DCAExecutor] submitted %s %s buy order at the ask price %s",
+		quantity.String(), e.Symbol, price.String())
+	return nil
+}
+
+func (e *DCAExecutor) handleTrade(trade types.Trade) {
+	if trade.Symbol != e.Symbol || trade.Side != types.SideTypeBuy {
+		return
+	}
+
+	e.fillMu.Lock()
+	defer e.fillMu.Unlock()
+
+	if _, ok := e.remainingQuantities[trade.OrderID]; !ok {
+		// the order ID is not returned yet
+		if e.submitting {
+			e.pendingTrades = append(e.pendingTrades, trade)
+		}
+
+		return
+	}
+
+	e.addFill(trade)
+}
+
+// addFill accumulates the trade of the submitted buy orders into the totals, it should be called with fillMu locked
+func (e *DCAExecutor) addFill(trade types.Trade) {
+	remaining, ok := e.remainingQuantities[trade.OrderID]
+	if !ok {
+		return
+	}
+
+	remaining = remaining.Sub(trade.Quantity)
+	if remaining.Sign() > 0 {
+		e.remainingQuantities[trade.OrderID] = remaining
+	} else {
+		delete(e.remainingQuantities, trade.OrderID)
+	}
+
+	e.TotalInvested = e.TotalInvested.Add(trade.QuoteQuantity)
+	e.TotalQuantity = e.TotalQuantity.Add(trade.Quantity)
+
+	log.Infof("[DCAExecutor] filled %s %s at %s, total invested %s, average entry price %s",
+		trade.Quantity.String(), e.Symbol, trade.Price.String(), e.TotalInvested.String(), e.TotalInvested.Div(e.TotalQuantity).String())
+}
@@ -0,0 +1,157 @@
+package bbgo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	bbgomocks "github.com/c9s/bbgo/pkg/bbgo/mocks"
+	"github.com/c9s/bbgo/pkg/core"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/types/mocks"
+)
+
+func TestDCAExecutor(t *testing.T) {
+	market := getTestMarket()
+	market.StepSize = fixedpoint.MustNewFromString("0.00001")
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+
+	session := NewExchangeSession("test", mockEx)
+	session.markets[market.Symbol] = market
+	session.Account.UpdateBalances(types.BalanceMap{
+		"USDT": {Currency: "USDT", Available: fixedpoint.NewFromFloat(1000.0)},
+	})
+
+	orderStore := core.NewOrderStore("BTCUSDT")
+	tradeCollector := core.NewTradeCollector("BTCUSDT", nil, orderStore)
+
+	newTrade := func(id uint64, order types.Order, price, quantity float64) types.Trade {
+		p := fixedpoint.NewFromFloat(price)
+		q := fixedpoint.NewFromFloat(quantity)
+		return types.Trade{
+			ID:            id,
+			OrderID:       order.OrderID,
+			Exchange:      types.ExchangeBinance,
+			Symbol:        "BTCUSDT",
+			Side:          types.SideTypeBuy,
+			IsBuyer:       true,
+			Price:         p,
+			Quantity:      q,
+			QuoteQuantity: p.Mul(q),
+		}
+	}
+
+	// fill simulates the fills that arrive before SubmitOrders returns
+	var fill func(order types.Order)
+	var submitted []types.Order
+	orderExecutor := bbgomocks.NewMockOrderExecutorExtended(mockCtrl)
+	orderExecutor.EXPECT().TradeCollector().Return(tradeCollector).AnyTimes()
+	orderExecutor.EXPECT().SubmitOrders(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, orders ...types.SubmitOrder) (types.OrderSlice, error) {
+			order := types.Order{SubmitOrder: orders[0], OrderID: uint64(len(submitted) + 1), Status: types.OrderStatusNew}
+			orderStore.Add(order)
+			submitted = append(submitted, order)
+			if fill != nil {
+				fill(order)
+			}
+			return types.OrderSlice{order}, nil
+		}).AnyTimes()
+
+	startTime := time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC)
+	now := startTime
+
+	executor := &DCAExecutor{
+		Symbol:      "BTCUSDT",
+		QuoteAmount: fixedpoint.NewFromFloat(100.0),
+		Interval:    types.Duration(time.Hour),
+	}
+	executor.SetClock(func() time.Time { return now })
+	if !assert.NoError(t, executor.Bind(session, orderExecutor)) {
+		return
+	}
+
+	ctx := context.Background()
+
+	// the first buy is scheduled one interval later
+	executor.Tick(ctx)
+	assert.Empty(t, submitted)
+
+	mockEx.EXPECT().QueryTicker(gomock.Any(), "BTCUSDT").Return(&types.Ticker{Sell: fixedpoint.NewFromFloat(20000.0)}, nil)
+	now = startTime.Add(time.Hour)
+	executor.Tick(ctx)
+	if !assert.Len(t, submitted, 1) {
+		return
+	}
+
+	assert.Equal(t, types.SideTypeBuy, submitted[0].Side)
+	assert.Equal(t, types.OrderTypeMarket, submitted[0].Type)
+	assert.Equal(t, "0.005", submitted[0].Quantity.String())
+
+	// nothing is invested before the order is filled
+	assert.True(t, executor.TotalInvested.IsZero())
+	assert.True(t, executor.AverageEntryPrice().IsZero())
+
+	// the market order walks the book, the fills are worse than the ask price
+	tradeCollector.ProcessTrade(newTrade(1, submitted[0], 20010.0, 0.003))
+	tradeCollector.ProcessTrade(newTrade(2, submitted[0], 20050.0, 0.002))
+
+	now = startTime.Add(90 * time.Minute)
+	executor.Tick(ctx)
+	assert.Len(t, submitted, 1)
+
+	// the second order is filled before SubmitOrders returns
+	fill = func(order types.Order) {
+		tradeCollector.ProcessTrade(newTrade(3, order, 25000.0, 0.004))
+	}
+
+	mockEx.EXPECT().QueryTicker(gomock.Any(), "BTCUSDT").Return(&types.Ticker{Sell: fixedpoint.NewFromFloat(25000.0)}, nil)
+	now = startTime.Add(150 * time.Minute)
+	executor.Tick(ctx)
+	if assert.Len(t, submitted, 2) {
+		assert.Equal(t, "0.004", submitted[1].Quantity.String())
+	}
+
+	// trades of the other orders are not counted
+	tradeCollector.ProcessTrade(newTrade(4, types.Order{OrderID: 100}, 30000.0, 1.0))
+
+	// 20010 * 0.003 + 20050 * 0.002 + 25000 * 0.004
+	assert.Equal(t, "200.13", executor.TotalInvested.String())
+	assert.Equal(t, "0.009", executor.TotalQuantity.String())
+	// 200.13 / 0.009
+	assert.InDelta(t, 22236.6666, executor.AverageEntryPrice().Float64(), 0.0001)
+
+	// insufficient balance, the buy should be skipped
+	session.Account.UpdateBalances(types.BalanceMap{
+		"USDT": {Currency: "USDT", Available: fixedpoint.NewFromFloat(50.0)},
+	})
+	now = startTime.Add(210 * time.Minute)
+	executor.Tick(ctx)
+	assert.Len(t, submitted, 2)
+	assert.Equal(t, "200.13", executor.TotalInvested.String())
+}
+
+func TestDCAExecutor_Schedule(t *testing.T) {
+	executor := &DCAExecutor{
+		Symbol:      "BTCUSDT",
+		QuoteAmount: fixedpoint.NewFromFloat(100.0),
+		Schedule:    "0 0 * * *",
+	}
+
+	now := time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC)
+	executor.SetClock(func() time.Time { return now })
+	if assert.NoError(t, executor.Bind(nil, nil)) {
+		assert.Equal(t, time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC), executor.NextTime)
+	}
+
+	assert.Error(t, (&DCAExecutor{Symbol: "BTCUSDT", QuoteAmount: fixedpoint.One, Schedule: "invalid"}).Validate())
+	assert.Error(t, (&DCAExecutor{Symbol: "BTCUSDT", QuoteAmount: fixedpoint.One}).Validate())
+}
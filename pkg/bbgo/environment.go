@@ -90,6 +90,7 @@ type Environment struct {
 	MarginService     *service.MarginService
 	SyncService       *service.SyncService
 	AccountService    *service.AccountService
+	BalanceService    *service.BalanceService
 	WithdrawService   *service.WithdrawService
 	DepositService    *service.DepositService
 	PersistentService *service.PersistenceServiceFacade
@@ -111,7 +112,22 @@ type Environment struct {
 	loggingConfig     *LoggingConfig
 	environmentConfig *EnvironmentConfig
 
+	// pingOnStartup pings the exchanges before initializing the sessions, see EnvironmentConfig.PingOnStartup
+	pingOnStartup bool
+
 	sessions map[string]*ExchangeSession
+
+	// orderRoutePolicy is used by RouteOrder for picking the session
+	orderRoutePolicy OrderRoutePolicy
+
+	// shutdownCancels cancels the contexts derived by withShutdown, they are called by Shutdown
+	shutdownCancels []context.CancelFunc
+	isShutdown      bool
+	shutdownMutex   sync.Mutex
+
+	// goroutines tracks the background goroutines started via Go, including the stream closers,
+	// and the goroutines started by types.GoWithContext with the contexts derived by withShutdown
+	goroutines sync.WaitGroup
 }
 
 func NewEnvironment() *Environment {
@@ -147,6 +163,11 @@ func (environ *Environment) SetLogging(config *LoggingConfig) {
 	environ.loggingConfig = config
 }
 
+// SetPingOnStartup enables pinging the exchanges before initializing the sessions in Init
+func (environ *Environment) SetPingOnStartup(enabled bool) {
+	environ.pingOnStartup = enabled
+}
+
 func (environ *Environment) SelectSessions(names ...string) map[string]*ExchangeSession {
 	if len(names) == 0 {
 		return environ.sessions
@@ -215,6 +236,7 @@ func (environ *Environment) ConfigureDatabaseDriver(ctx context.Context, driver
 	environ.TradeService = &service.TradeService{DB: db}
 	environ.RewardService = &service.RewardService{DB: db}
 	environ.AccountService = &service.AccountService{DB: db}
+	environ.BalanceService = &service.BalanceService{DB: db}
 	environ.ProfitService = &service.ProfitService{DB: db}
 	environ.PositionService = &service.PositionService{DB: db}
 	environ.MarginService = &service.MarginService{DB: db}
@@ -227,6 +249,7 @@ func (environ *Environment) ConfigureDatabaseDriver(ctx context.Context, driver
 		MarginService:   environ.MarginService,
 		WithdrawService: &service.WithdrawService{DB: db},
 		DepositService:  &service.DepositService{DB: db},
+		KLineService:    &service.BacktestService{DB: db},
 	}
 
 	return nil
@@ -300,6 +323,12 @@ func (environ *Environment) IsBackTesting() bool {
 func (environ *Environment) Init(ctx context.Context) (err error) {
 	for n := range environ.sessions {
 		var session = environ.sessions[n]
+		if environ.pingOnStartup && !session.IsInitialized {
+			if err = session.Ping(ctx); err != nil {
+				return err
+			}
+		}
+
 		if err = session.Init(ctx, environ); err != nil {
 			// we can skip initialized sessions
 			if err != ErrSessionAlreadyInitialized {
@@ -409,7 +438,79 @@ func (environ *Environment) BindSync(config *SyncConfig) {
 	}
 }
 
+// withShutdown derives a context from the parent context, which is canceled
+// either by the parent context or by Shutdown. The goroutines started by types.GoWithContext
+// with the derived context (the stream workers and the schedulers) are awaited by Shutdown.
+func (environ *Environment) withShutdown(parent context.Context) context.Context {
+	ctx, cancel := context.WithCancel(types.WithRoutineGroup(parent, &environ.goroutines))
+
+	environ.shutdownMutex.Lock()
+	defer environ.shutdownMutex.Unlock()
+
+	if environ.isShutdown {
+		cancel()
+		return ctx
+	}
+
+	environ.shutdownCancels = append(environ.shutdownCancels, cancel)
+	return ctx
+}
+
+// Go starts a background goroutine with a context derived from the parent context,
+// the context is canceled by the parent or by Shutdown, and Shutdown waits for the goroutine to return.
+func (environ *Environment) Go(parent context.Context, f func(ctx context.Context)) {
+	ctx := environ.withShutdown(parent)
+
+	environ.goroutines.Add(1)
+	go func() {
+		defer environ.goroutines.Done()
+		f(ctx)
+	}()
+}
+
+// closeStreamOnDone closes the stream when the context is canceled,
+// the closer goroutine is tracked so that Shutdown waits for the stream to be closed.
+func (environ *Environment) closeStreamOnDone(ctx context.Context, name string, stream types.Stream) {
+	environ.Go(ctx, func(ctx context.Context) {
+		<-ctx.Done()
+		if err := stream.Close(); err != nil {
+			log.WithError(err).Errorf("[%s] stream close error", name)
+		}
+	})
+}
+
+// Shutdown cancels the contexts derived from the environment, which stops the session streams,
+// the strategies and the background goroutines, then it waits for the tracked goroutines.
+// It returns the context error if the goroutines do not finish before the given context is done.
+func (environ *Environment) Shutdown(ctx context.Context) error {
+	environ.shutdownMutex.Lock()
+	cancels := environ.shutdownCancels
+	environ.shutdownCancels = nil
+	environ.isShutdown = true
+	environ.shutdownMutex.Unlock()
+
+	for _, cancel := range cancels {
+		cancel()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		environ.goroutines.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 func (environ *Environment) Connect(ctx context.Context) error {
+	// the streams and the interaction can be stopped by Shutdown
+	ctx = environ.withShutdown(ctx)
+
 	log.Debugf("starting interaction...")
 	if err := interact.Start(ctx); err != nil {
 		return err
@@ -434,12 +535,14 @@ func (environ *Environment) Connect(ctx context.Context) error {
 		if err := session.MarketDataStream.Connect(ctx); err != nil {
 			return err
 		}
+		environ.closeStreamOnDone(ctx, n, session.MarketDataStream)
 
 		if !session.PublicOnly {
 			logger.Infof("connecting %s user data stream...", session.Name)
 			if err := session.UserDataStream.Connect(ctx); err != nil {
 				return err
 			}
+			environ.closeStreamOnDone(ctx, n, session.UserDataStream)
 		}
 	}
 
@@ -570,6 +673,76 @@ func (environ *Environment) RecordAsset(t time.Time, session *ExchangeSession, a
 	}
 }
 
+// StartBalanceSnapshots records the balance snapshots of all the sessions periodically with the
+// BalanceSnapshotInterval of the environment config, it's a no-op if the interval or the database is not configured.
+func (environ *Environment) StartBalanceSnapshots(ctx context.Context, config *EnvironmentConfig) {
+	// skip for back-test
+	if environ.BacktestService != nil || environ.BalanceService == nil {
+		return
+	}
+
+	if config == nil || config.BalanceSnapshotInterval <= 0 {
+		return
+	}
+
+	interval := config.BalanceSnapshotInterval.Duration()
+	valuationCurrency := config.BalanceSnapshotCurrency
+	environ.Go(ctx, func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case now := <-ticker.C:
+				environ.RecordBalanceSnapshots(now, valuationCurrency)
+			}
+		}
+	})
+}
+
+// RecordBalanceSnapshots inserts the current balances of all the sessions with their valuation,
+// the valuation is zero if the price of the currency in the valuation currency is not found.
+// empty valuation currency means USDT.
+func (environ *Environment) RecordBalanceSnapshots(t time.Time, valuationCurrency string) {
+	// skip for back-test
+	if environ.BacktestService != nil || environ.BalanceService == nil {
+		return
+	}
+
+	if valuationCurrency == "" {
+		valuationCurrency = "USDT"
+	}
+
+	for _, session := range environ.sessions {
+		prices := types.PriceMap(session.AllLastPrices())
+
+		var snapshots []service.BalanceSnapshot
+		for currency, balance := range session.GetAccount().Balances() {
+			valuation := fixedpoint.Zero
+			if price, ok := prices.ConvertPrice(currency, valuationCurrency); ok {
+				valuation = balance.Net().Mul(price)
+			}
+
+			snapshots = append(snapshots, service.BalanceSnapshot{
+				Session:   session.Name,
+				Exchange:  session.ExchangeName,
+				Currency:  currency,
+				Available: balance.Available,
+				Locked:    balance.Locked,
+				Valuation: valuation,
+				Time:      types.Time(t),
+			})
+		}
+
+		if err := environ.BalanceService.Insert(snapshots...); err != nil {
+			log.WithError(err).Errorf("can not insert the balance snapshots of session %s", session.Name)
+		}
+	}
+}
+
 func (environ *Environment) RecordPosition(position *types.Position, trade types.Trade, profit *types.Profit) {
 	// skip for back-test
 	if environ.BacktestService != nil {
@@ -691,6 +864,11 @@ func (environ *Environment) ConfigureNotificationSystem(ctx context.Context, use
 }
 
 func (environ *Environment) ConfigureNotification(config *NotificationConfig) error {
+	if config.Throttle != nil {
+		Notification.SetThrottle(config.Throttle.Window.Duration(), config.Throttle.MaxPerWindow)
+		Notification.SetThrottleCoalesceByFormat(config.Throttle.CoalesceByFormat)
+	}
+
 	if config.Switches != nil {
 		if config.Switches.Trade {
 			tradeHandler := func(trade types.Trade) {
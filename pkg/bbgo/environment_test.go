@@ -0,0 +1,296 @@
+package bbgo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/websocket"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/service"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/types/mocks"
+)
+
+// testWorkerStream simulates the reader goroutine of the websocket stream,
+// done is closed when the reader goroutine returns, and Close waits for the reader like StandardStream does
+type testWorkerStream struct {
+	*types.StandardStream
+
+	done chan struct{}
+}
+
+func (s *testWorkerStream) Connect(ctx context.Context) error {
+	go func() {
+		defer close(s.done)
+		<-s.CloseC
+	}()
+	return nil
+}
+
+func (s *testWorkerStream) Close() error {
+	close(s.CloseC)
+	<-s.done
+	return nil
+}
+
+func newTestWorkerStream() *testWorkerStream {
+	stream := types.NewStandardStream()
+	return &testWorkerStream{StandardStream: &stream, done: make(chan struct{})}
+}
+
+// testPingExchange is an exchange that supports ping
+type testPingExchange struct {
+	*mocks.MockExchange
+
+	pingErr error
+}
+
+func (e *testPingExchange) Ping(ctx context.Context) error {
+	return e.pingErr
+}
+
+func TestEnvironment_Init_PingOnStartup(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+	mockEx.EXPECT().Name().Return(types.ExchangeOKEx).AnyTimes()
+
+	authErr := errors.New("Invalid OK-ACCESS-KEY")
+	session := NewExchangeSession("okex", &testPingExchange{MockExchange: mockEx, pingErr: authErr})
+	session.ExchangeName = types.ExchangeOKEx
+
+	environ := NewEnvironment()
+	environ.AddExchangeSession("okex", session)
+	environ.SetPingOnStartup(true)
+
+	// the markets are not queried since the ping fails first
+	err := environ.Init(context.Background())
+	if assert.Error(t, err) {
+		assert.True(t, errors.Is(err, authErr))
+		assert.Contains(t, err.Error(), "ping failed")
+	}
+	assert.False(t, session.IsInitialized)
+}
+
+// isDone returns true if the channel is closed
+func isDone(c <-chan struct{}) bool {
+	select {
+	case <-c:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestEnvironment_Shutdown(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+
+	marketDataStream := newTestWorkerStream()
+	userDataStream := newTestWorkerStream()
+
+	session := NewExchangeSession("test", mockEx)
+	session.MarketDataStream = marketDataStream
+	session.UserDataStream = userDataStream
+
+	environ := NewEnvironment()
+	environ.AddExchangeSession("test", session)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	assert.NoError(t, environ.Connect(ctx))
+
+	// heartbeat-like workers
+	var workerDones []chan struct{}
+	for i := 0; i < 3; i++ {
+		done := make(chan struct{})
+		workerDones = append(workerDones, done)
+		environ.Go(ctx, func(ctx context.Context) {
+			defer close(done)
+
+			ticker := time.NewTicker(time.Millisecond)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		})
+	}
+
+	assert.False(t, isDone(marketDataStream.done))
+	assert.False(t, isDone(userDataStream.done))
+	for _, done := range workerDones {
+		assert.False(t, isDone(done))
+	}
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), time.Second)
+	defer cancelShutdown()
+	assert.NoError(t, environ.Shutdown(shutdownCtx))
+
+	// Shutdown returns after the streams are closed and the workers return
+	assert.True(t, isDone(marketDataStream.done), "market data stream is not closed")
+	assert.True(t, isDone(userDataStream.done), "user data stream is not closed")
+	for i, done := range workerDones {
+		assert.True(t, isDone(done), "worker %d is not stopped", i)
+	}
+
+	// the goroutines started after the shutdown get a canceled context
+	started := make(chan error, 1)
+	environ.Go(ctx, func(ctx context.Context) {
+		started <- ctx.Err()
+	})
+	assert.Error(t, <-started)
+}
+
+func TestEnvironment_Shutdown_NoGoroutineLeak(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		// read until the client closes the connection, the close frame is echoed by the default close handler
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	endpoint := "ws" + strings.TrimPrefix(server.URL, "http")
+	newStream := func() *types.StandardStream {
+		stream := types.NewStandardStream()
+		stream.SetEndpointCreator(func(ctx context.Context) (string, error) {
+			return endpoint, nil
+		})
+		return &stream
+	}
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+
+	session := NewExchangeSession("test", mockEx)
+	session.MarketDataStream = newStream()
+	session.UserDataStream = newStream()
+
+	environ := NewEnvironment()
+	environ.AddExchangeSession("test", session)
+
+	before := runtime.NumGoroutine()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	assert.NoError(t, environ.Connect(ctx))
+
+	// the schedulers started with the strategy context
+	strategyCtx := environ.withShutdown(ctx)
+	(&DCAExecutor{}).Run(strategyCtx)
+
+	// the stream readers, pingers, reconnectors and the scheduler are running
+	assert.Greater(t, runtime.NumGoroutine(), before)
+
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelShutdown()
+	assert.NoError(t, environ.Shutdown(shutdownCtx))
+
+	// the runtime may need a moment to reap the returned goroutines,
+	// poll in the test goroutine because assert.Eventually runs the condition in another goroutine
+	after := runtime.NumGoroutine()
+	for deadline := time.Now().Add(time.Second); after > before && time.Now().Before(deadline); after = runtime.NumGoroutine() {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.LessOrEqual(t, after, before, "goroutines leaked after shutdown")
+}
+
+func TestEnvironment_Go_ParentContext(t *testing.T) {
+	environ := NewEnvironment()
+
+	parent, cancelParent := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	environ.Go(parent, func(ctx context.Context) {
+		defer close(done)
+		<-ctx.Done()
+	})
+
+	// the worker honors the parent context without shutting down the environment
+	cancelParent()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the worker is not stopped by the parent context")
+	}
+
+	// another worker with a live parent is still running until the shutdown
+	other := make(chan struct{})
+	environ.Go(context.Background(), func(ctx context.Context) {
+		defer close(other)
+		<-ctx.Done()
+	})
+	assert.False(t, isDone(other))
+
+	assert.NoError(t, environ.Shutdown(context.Background()))
+	assert.True(t, isDone(other))
+}
+
+func TestEnvironment_RecordBalanceSnapshots(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+
+	session := NewExchangeSession("binance", mockEx)
+	session.ExchangeName = types.ExchangeBinance
+	session.lastPrices["BTCUSDT"] = fixedpoint.NewFromFloat(20000.0)
+	session.Account.UpdateBalances(types.BalanceMap{
+		"BTC": {Currency: "BTC", Available: fixedpoint.NewFromFloat(0.5), Locked: fixedpoint.NewFromFloat(0.1)},
+	})
+
+	db, mock, err := sqlmock.New()
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer db.Close()
+
+	environ := NewEnvironment()
+	environ.AddExchangeSession("binance", session)
+	environ.BalanceService = &service.BalanceService{DB: sqlx.NewDb(db, "mysql")}
+
+	now := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	// (0.5 + 0.1) * 20000
+	mock.ExpectExec(`INSERT INTO balance_snapshots`).
+		WithArgs("binance", types.ExchangeBinance, "BTC", fixedpoint.NewFromFloat(0.5), fixedpoint.NewFromFloat(0.1), fixedpoint.NewFromFloat(12000.0), types.Time(now)).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	environ.RecordBalanceSnapshots(now, "")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
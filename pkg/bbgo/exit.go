@@ -37,12 +37,34 @@ func (s *ExitMethodSet) Bind(session *ExchangeSession, orderExecutor *GeneralOrd
 	}
 }
 
+// Validate validates the exit methods which have the Validate method
+func (s *ExitMethodSet) Validate() error {
+	for _, method := range *s {
+		if method.ProtectiveStopLoss != nil {
+			if err := method.ProtectiveStopLoss.Validate(); err != nil {
+				return err
+			}
+		}
+
+		if method.PartialTakeProfitTrailingStop != nil {
+			if err := method.PartialTakeProfitTrailingStop.Validate(); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 type ExitMethod struct {
 	RoiStopLoss            *RoiStopLoss            `json:"roiStopLoss"`
 	ProtectiveStopLoss     *ProtectiveStopLoss     `json:"protectiveStopLoss"`
 	RoiTakeProfit          *RoiTakeProfit          `json:"roiTakeProfit"`
 	TrailingStop           *TrailingStop2          `json:"trailingStop"`
 	HigherHighLowerLowStop *HigherHighLowerLowStop `json:"higherHighLowerLowStopLoss"`
+	MaxHoldTime            *MaxHoldTime            `json:"maxHoldTime"`
+
+	PartialTakeProfitTrailingStop *PartialTakeProfitTrailingStop `json:"partialTakeProfitTrailingStop"`
 
 	// Exit methods for short positions
 	// =================================================
@@ -93,6 +115,16 @@ func (e ExitMethod) String() string {
 		buf.WriteString("hhllStop: " + string(b) + ", ")
 	}
 
+	if e.MaxHoldTime != nil {
+		b, _ := json.Marshal(e.MaxHoldTime)
+		buf.WriteString("maxHoldTime: " + string(b) + ", ")
+	}
+
+	if e.PartialTakeProfitTrailingStop != nil {
+		b, _ := json.Marshal(e.PartialTakeProfitTrailingStop)
+		buf.WriteString("partialTakeProfitTrailingStop: " + string(b) + ", ")
+	}
+
 	return buf.String()
 }
 
@@ -154,4 +186,12 @@ func (m *ExitMethod) Bind(session *ExchangeSession, orderExecutor *GeneralOrderE
 	if m.HigherHighLowerLowStop != nil {
 		m.HigherHighLowerLowStop.Bind(session, orderExecutor)
 	}
+
+	if m.MaxHoldTime != nil {
+		m.MaxHoldTime.Bind(session, orderExecutor)
+	}
+
+	if m.PartialTakeProfitTrailingStop != nil {
+		m.PartialTakeProfitTrailingStop.Bind(session, orderExecutor)
+	}
 }
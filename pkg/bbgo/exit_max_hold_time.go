@@ -0,0 +1,120 @@
+package bbgo
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// MaxHoldTime closes the position when it has been held longer than the given duration,
+// the hold time starts from the first fill of the position and resets when the position is closed.
+//
+//go:generate callbackgen -type MaxHoldTime
+type MaxHoldTime struct {
+	Symbol             string         `json:"symbol"`
+	Duration           types.Duration `json:"duration"`
+	CancelActiveOrders bool           `json:"cancelActiveOrders"`
+
+	// openedAt is the time of the first fill of the current position
+	openedAt time.Time
+
+	// now is the clock used for checking the hold time, it can be replaced for testing
+	now func() time.Time
+
+	timeoutCallbacks []func(position *types.Position, holdTime time.Duration)
+
+	mu            sync.Mutex
+	session       *ExchangeSession
+	orderExecutor *GeneralOrderExecutor
+}
+
+func (s *MaxHoldTime) Subscribe(session *ExchangeSession) {
+	// use 1m kline to check the hold time
+	session.Subscribe(types.KLineChannel, s.Symbol, types.SubscribeOptions{Interval: types.Interval1m})
+}
+
+// SetClock replaces the clock used for checking the hold time
+func (s *MaxHoldTime) SetClock(now func() time.Time) {
+	s.now = now
+}
+
+// OpenedAt returns the time of the first fill of the current position
+func (s *MaxHoldTime) OpenedAt() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.openedAt
+}
+
+func (s *MaxHoldTime) Bind(session *ExchangeSession, orderExecutor *GeneralOrderExecutor) {
+	s.session = session
+	s.orderExecutor = orderExecutor
+
+	if s.now == nil {
+		s.now = time.Now
+	}
+
+	position := orderExecutor.Position()
+	orderExecutor.TradeCollector().OnTrade(func(trade types.Trade, _, _ fixedpoint.Value) {
+		s.handleTrade(trade, position)
+	})
+
+	session.MarketDataStream.OnKLineClosed(types.KLineWith(s.Symbol, types.Interval1m, func(kline types.KLine) {
+		now := s.now()
+		if IsBackTesting {
+			now = kline.EndTime.Time()
+		}
+
+		s.checkHoldTime(now, position)
+	}))
+}
+
+func (s *MaxHoldTime) handleTrade(trade types.Trade, position *types.Position) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if position.IsClosed() {
+		s.openedAt = time.Time{}
+		return
+	}
+
+	if s.openedAt.IsZero() {
+		s.openedAt = trade.Time.Time()
+	}
+}
+
+func (s *MaxHoldTime) checkHoldTime(now time.Time, position *types.Position) {
+	if s.Duration <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	openedAt := s.openedAt
+	s.mu.Unlock()
+
+	if openedAt.IsZero() || position.IsClosed() || position.IsClosing() {
+		return
+	}
+
+	holdTime := now.Sub(openedAt)
+	if holdTime < s.Duration.Duration() {
+		return
+	}
+
+	Notify("[MaxHoldTime] %s position has been held for %s, exceeding the max hold time %s, closing position", position.Symbol, holdTime, s.Duration.Duration())
+
+	s.EmitTimeout(position, holdTime)
+
+	ctx := context.Background()
+	if s.CancelActiveOrders {
+		_ = s.orderExecutor.GracefulCancel(ctx)
+	}
+
+	if err := s.orderExecutor.ClosePosition(ctx, fixedpoint.One, "maxHoldTime"); err != nil {
+		log.WithError(err).Errorf("[MaxHoldTime] unable to close the %s position", position.Symbol)
+	}
+}
@@ -0,0 +1,93 @@
+package bbgo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/types/mocks"
+)
+
+func TestMaxHoldTime(t *testing.T) {
+	market := getTestMarket()
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+
+	session := NewExchangeSession("test", mockEx)
+	session.markets[market.Symbol] = market
+	session.Account.UpdateBalances(types.BalanceMap{
+		"BTC": {Currency: "BTC", Available: fixedpoint.One},
+	})
+
+	position := types.NewPositionFromMarket(market)
+	orderExecutor := NewGeneralOrderExecutor(session, "BTCUSDT", "test", "test-01", position)
+
+	openedAt := time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC)
+	now := openedAt
+
+	maxHoldTime := &MaxHoldTime{
+		Symbol:   "BTCUSDT",
+		Duration: types.Duration(time.Hour),
+	}
+	maxHoldTime.SetClock(func() time.Time { return now })
+	maxHoldTime.Bind(session, orderExecutor)
+
+	var timeoutHoldTime time.Duration
+	maxHoldTime.OnTimeout(func(position *types.Position, holdTime time.Duration) {
+		timeoutHoldTime = holdTime
+	})
+
+	trade := types.Trade{
+		Symbol:   "BTCUSDT",
+		Side:     types.SideTypeBuy,
+		Price:    fixedpoint.NewFromFloat(20000.0),
+		Quantity: fixedpoint.One,
+		Time:     types.Time(openedAt),
+	}
+	position.AddTrade(trade)
+	maxHoldTime.handleTrade(trade, position)
+	assert.Equal(t, openedAt, maxHoldTime.OpenedAt())
+
+	// adding to the position should not move the opened time
+	now = openedAt.Add(30 * time.Minute)
+	trade.Time = types.Time(now)
+	maxHoldTime.handleTrade(trade, position)
+	assert.Equal(t, openedAt, maxHoldTime.OpenedAt())
+
+	now = openedAt.Add(59 * time.Minute)
+	maxHoldTime.checkHoldTime(now, position)
+	assert.Zero(t, timeoutHoldTime, "position should be kept before the hold time elapses")
+
+	mockEx.EXPECT().SubmitOrder(gomock.Any(), types.SubmitOrder{
+		Symbol:           "BTCUSDT",
+		Side:             types.SideTypeSell,
+		Type:             types.OrderTypeMarket,
+		Market:           market,
+		Quantity:         fixedpoint.One,
+		MarginSideEffect: types.SideEffectTypeAutoRepay,
+		Tag:              "maxHoldTime",
+	}).Return(&types.Order{}, nil)
+
+	now = openedAt.Add(time.Hour)
+	maxHoldTime.checkHoldTime(now, position)
+	assert.Equal(t, time.Hour, timeoutHoldTime)
+
+	// the opened time resets when the position is closed
+	position.AddTrade(types.Trade{
+		Symbol:   "BTCUSDT",
+		Side:     types.SideTypeSell,
+		Price:    fixedpoint.NewFromFloat(20100.0),
+		Quantity: fixedpoint.One,
+		Time:     types.Time(now),
+	})
+	maxHoldTime.handleTrade(trade, position)
+	assert.True(t, maxHoldTime.OpenedAt().IsZero())
+}
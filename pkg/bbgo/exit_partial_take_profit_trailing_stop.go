@@ -0,0 +1,169 @@
+package bbgo
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const partialTakeProfitTag = "partialTakeProfit"
+
+// PartialTakeProfitTrailingStop takes a partial profit once when the ROI reaches the TakeProfitRatio,
+// and then trails the remaining position with the trailing stop.
+// The trailing stop is armed only after the partial take profit order is filled,
+// if the trailing stop has no activation ratio and min profit, it's activated right after the fill.
+// The fill is detected from the position update, since the trades are applied to the position
+// only after the order is registered in the order store, a fast fill is not missed.
+type PartialTakeProfitTrailingStop struct {
+	Symbol string `json:"symbol"`
+
+	// Interval is the time resolution to check the price, defaults to 1m
+	Interval types.Interval `json:"interval,omitempty"`
+
+	// TakeProfitRatio is the ROI to take the partial profit
+	TakeProfitRatio fixedpoint.Value `json:"takeProfitRatio"`
+
+	// TakeProfitPercentage is the percentage of the position to close at the partial take profit
+	TakeProfitPercentage fixedpoint.Value `json:"takeProfitPercentage"`
+
+	// TrailingStop is the trailing stop for the remaining position
+	TrailingStop TrailingStop2 `json:"trailingStop"`
+
+	mu                  sync.Mutex
+	takeProfitSubmitted bool
+	takeProfitFilled    bool
+
+	// takeProfitBase is the absolute position base when the partial take profit is submitted
+	takeProfitBase fixedpoint.Value
+
+	session       *ExchangeSession
+	orderExecutor *GeneralOrderExecutor
+}
+
+func (s *PartialTakeProfitTrailingStop) Validate() error {
+	if s.TakeProfitRatio.Sign() <= 0 {
+		return fmt.Errorf("partialTakeProfitTrailingStop: takeProfitRatio should be positive")
+	}
+
+	if s.TakeProfitPercentage.Sign() <= 0 || s.TakeProfitPercentage.Compare(fixedpoint.One) >= 0 {
+		return fmt.Errorf("partialTakeProfitTrailingStop: takeProfitPercentage %s should be within (0, 1)", s.TakeProfitPercentage.String())
+	}
+
+	if s.TrailingStop.CallbackRate.Sign() <= 0 {
+		return fmt.Errorf("partialTakeProfitTrailingStop: trailingStop.callbackRate should be positive")
+	}
+
+	return nil
+}
+
+func (s *PartialTakeProfitTrailingStop) Subscribe(session *ExchangeSession) {
+	if s.Interval == "" {
+		s.Interval = types.Interval1m
+	}
+
+	session.Subscribe(types.KLineChannel, s.Symbol, types.SubscribeOptions{Interval: s.Interval})
+}
+
+func (s *PartialTakeProfitTrailingStop) Bind(session *ExchangeSession, orderExecutor *GeneralOrderExecutor) {
+	if s.Interval == "" {
+		s.Interval = types.Interval1m
+	}
+
+	s.session = session
+	s.orderExecutor = orderExecutor
+
+	// the trailing stop shares the position and the order executor, its price is fed by this exit method
+	s.TrailingStop.Symbol = s.Symbol
+	s.TrailingStop.BindOrderExecutor(session, orderExecutor)
+
+	position := orderExecutor.Position()
+
+	orderExecutor.TradeCollector().OnPositionUpdate(func(position *types.Position) {
+		if position.IsClosed() {
+			s.reset()
+			return
+		}
+
+		s.mu.Lock()
+		reduced := s.takeProfitSubmitted && !s.takeProfitFilled && position.GetBase().Abs().Compare(s.takeProfitBase) < 0
+		if reduced {
+			s.takeProfitFilled = true
+		}
+		s.mu.Unlock()
+
+		if reduced {
+			s.armTrailingStop()
+		}
+	})
+
+	f := func(kline types.KLine) {
+		if err := s.checkPrice(kline.Close, position); err != nil {
+			log.WithError(err).Errorf("[PartialTakeProfitTrailingStop] check price error")
+		}
+	}
+
+	session.MarketDataStream.OnKLineClosed(types.KLineWith(s.Symbol, s.Interval, f))
+	session.MarketDataStream.OnKLine(types.KLineWith(s.Symbol, s.Interval, f))
+}
+
+func (s *PartialTakeProfitTrailingStop) reset() {
+	s.mu.Lock()
+	s.takeProfitSubmitted = false
+	s.takeProfitFilled = false
+	s.mu.Unlock()
+
+	s.TrailingStop.Reset()
+}
+
+func (s *PartialTakeProfitTrailingStop) armTrailingStop() {
+	s.TrailingStop.Arm()
+
+	Notify("[PartialTakeProfitTrailingStop] %s partial take profit is filled, trailing stop is armed with callback rate %s",
+		s.Symbol, s.TrailingStop.CallbackRate.Percentage())
+}
+
+func (s *PartialTakeProfitTrailingStop) checkPrice(price fixedpoint.Value, position *types.Position) error {
+	if position.IsClosed() || position.IsDust(price) || position.IsClosing() {
+		return nil
+	}
+
+	s.mu.Lock()
+	submitted, filled := s.takeProfitSubmitted, s.takeProfitFilled
+	s.mu.Unlock()
+
+	if !submitted {
+		roi := position.ROI(price)
+		if roi.Compare(s.TakeProfitRatio) < 0 {
+			return nil
+		}
+
+		Notify("[PartialTakeProfitTrailingStop] %s partial take profit (%s of the position) is triggered by ROI %s/%s, price: %f",
+			s.Symbol, s.TakeProfitPercentage.Percentage(), roi.Percentage(), s.TakeProfitRatio.Percentage(), price.Float64())
+
+		s.mu.Lock()
+		s.takeProfitSubmitted = true
+		s.takeProfitBase = position.GetBase().Abs()
+		s.mu.Unlock()
+
+		if err := s.orderExecutor.ClosePosition(context.Background(), s.TakeProfitPercentage, partialTakeProfitTag); err != nil {
+			s.mu.Lock()
+			s.takeProfitSubmitted = false
+			s.mu.Unlock()
+			return err
+		}
+
+		return nil
+	}
+
+	// the trailing stop is armed only after the partial take profit is filled
+	if !filled {
+		return nil
+	}
+
+	return s.TrailingStop.checkStopPrice(price, position)
+}
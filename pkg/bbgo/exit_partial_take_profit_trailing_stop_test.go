@@ -0,0 +1,102 @@
+package bbgo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/types/mocks"
+)
+
+func TestPartialTakeProfitTrailingStop(t *testing.T) {
+	market := getTestMarket()
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+
+	session := NewExchangeSession("test", mockEx)
+	session.markets[market.Symbol] = market
+	session.Account.UpdateBalances(types.BalanceMap{
+		"BTC": {Currency: "BTC", Available: fixedpoint.One},
+	})
+
+	position := types.NewPositionFromMarket(market)
+	position.AverageCost = fixedpoint.NewFromFloat(20000.0)
+	position.Base = fixedpoint.One
+
+	orderExecutor := NewGeneralOrderExecutor(session, "BTCUSDT", "test", "test-01", position)
+
+	exit := &PartialTakeProfitTrailingStop{
+		Symbol:               "BTCUSDT",
+		TakeProfitRatio:      fixedpoint.MustNewFromString("0.02"),
+		TakeProfitPercentage: fixedpoint.MustNewFromString("0.5"),
+		TrailingStop: TrailingStop2{
+			CallbackRate: fixedpoint.MustNewFromString("0.01"),
+		},
+	}
+	if !assert.NoError(t, exit.Validate()) {
+		return
+	}
+	exit.Bind(session, orderExecutor)
+
+	// the take profit order is filled before the exchange returns the created order,
+	// the trade is pushed to the trade collector before the order is registered in the order store
+	var submitted []types.SubmitOrder
+	mockEx.EXPECT().SubmitOrder(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, order types.SubmitOrder) (*types.Order, error) {
+			submitted = append(submitted, order)
+			orderID := uint64(len(submitted))
+			if order.Tag == partialTakeProfitTag {
+				orderExecutor.TradeCollector().ProcessTrade(types.Trade{
+					ID:            orderID,
+					OrderID:       orderID,
+					Symbol:        "BTCUSDT",
+					Side:          order.Side,
+					Price:         fixedpoint.NewFromFloat(20400.0),
+					Quantity:      order.Quantity,
+					QuoteQuantity: order.Quantity.Mul(fixedpoint.NewFromFloat(20400.0)),
+				})
+				assert.False(t, exit.TrailingStop.IsActivated(), "the trade should wait for the order")
+			}
+
+			return &types.Order{SubmitOrder: order, OrderID: orderID, Status: types.OrderStatusNew}, nil
+		}).Times(2)
+
+	// ROI 1%, no take profit yet
+	assert.NoError(t, exit.checkPrice(fixedpoint.NewFromFloat(20200.0), position))
+	assert.Empty(t, submitted)
+
+	// ROI 2%, the partial take profit is triggered and filled right away
+	assert.NoError(t, exit.checkPrice(fixedpoint.NewFromFloat(20400.0), position))
+	if assert.Len(t, submitted, 1) {
+		assert.Equal(t, types.SideTypeSell, submitted[0].Side)
+		assert.Equal(t, "0.5", submitted[0].Quantity.String())
+		assert.Equal(t, partialTakeProfitTag, submitted[0].Tag)
+	}
+
+	assert.Equal(t, "0.5", position.GetBase().String())
+	assert.True(t, exit.TrailingStop.IsActivated())
+
+	session.Account.UpdateBalances(types.BalanceMap{
+		"BTC": {Currency: "BTC", Available: fixedpoint.MustNewFromString("0.5")},
+	})
+
+	// the trailing stop follows the new high
+	assert.NoError(t, exit.checkPrice(fixedpoint.NewFromFloat(20600.0), position))
+	assert.NoError(t, exit.checkPrice(fixedpoint.NewFromFloat(20800.0), position))
+	assert.Len(t, submitted, 1)
+
+	// 20800 - 1% = 20592, the trailing stop is triggered for the remaining position
+	assert.NoError(t, exit.checkPrice(fixedpoint.NewFromFloat(20590.0), position))
+	if assert.Len(t, submitted, 2) {
+		assert.Equal(t, types.SideTypeSell, submitted[1].Side)
+		assert.Equal(t, "0.5", submitted[1].Quantity.String())
+	}
+}
@@ -2,6 +2,7 @@ package bbgo
 
 import (
 	"context"
+	"fmt"
 
 	log "github.com/sirupsen/logrus"
 
@@ -15,6 +16,8 @@ import (
 // If you set StopLossRatio to 0.1% and ActivationRatio to 1%,
 // when the price goes away from your average cost by 1% and then goes back to below your (average_cost * (1 - 0.1%))
 // The stop will trigger.
+//
+//go:generate callbackgen -type ProtectiveStopLoss
 type ProtectiveStopLoss struct {
 	Symbol string `json:"symbol"`
 
@@ -30,10 +33,40 @@ type ProtectiveStopLoss struct {
 	// PlaceStopOrder places the stop order on exchange and lock the balance
 	PlaceStopOrder bool `json:"placeStopOrder"`
 
+	// MinPositionQuantity is the minimal position quantity to evaluate the stop loss,
+	// positions with the quantity less than or equal to this threshold (dust positions) are ignored.
+	MinPositionQuantity fixedpoint.Value `json:"minPositionQuantity,omitempty"`
+
+	// ClosePercentage is the percentage of the position to close when the stop is triggered,
+	// it should be within (0, 1], defaults to 1.0 (close the whole position).
+	ClosePercentage fixedpoint.Value `json:"closePercentage,omitempty"`
+
 	session       *ExchangeSession
 	orderExecutor *GeneralOrderExecutor
 	stopLossPrice fixedpoint.Value
 	stopLossOrder *types.Order
+
+	// activatedCallbacks is called with the stop loss price when the protective stop loss is activated
+	activatedCallbacks []func(price fixedpoint.Value)
+
+	// triggeredCallbacks is called with the trigger price when the protective stop loss is triggered
+	triggeredCallbacks []func(price fixedpoint.Value)
+}
+
+func (s *ProtectiveStopLoss) Validate() error {
+	if s.ClosePercentage.Sign() < 0 || s.ClosePercentage.Compare(one) > 0 {
+		return fmt.Errorf("protectiveStopLoss: closePercentage %s should be within (0, 1]", s.ClosePercentage.String())
+	}
+
+	return nil
+}
+
+func (s *ProtectiveStopLoss) closePercentage() fixedpoint.Value {
+	if s.ClosePercentage.IsZero() {
+		return one
+	}
+
+	return s.ClosePercentage
 }
 
 func (s *ProtectiveStopLoss) Subscribe(session *ExchangeSession) {
@@ -57,19 +90,37 @@ func (s *ProtectiveStopLoss) shouldActivate(position *types.Position, closePrice
 }
 
 func (s *ProtectiveStopLoss) placeStopOrder(ctx context.Context, position *types.Position, orderExecutor OrderExecutor) error {
+	// released is the base quantity locked by the canceled stop order,
+	// the local balance is not updated by the cancellation yet, so it's still counted as locked.
+	released := fixedpoint.Zero
 	if s.stopLossOrder != nil {
 		if err := orderExecutor.CancelOrders(ctx, *s.stopLossOrder); err != nil {
 			log.WithError(err).Errorf("failed to cancel stop limit order: %+v", s.stopLossOrder)
+		} else {
+			released = s.stopLossOrder.Quantity.Sub(s.stopLossOrder.ExecutedQuantity)
 		}
 		s.stopLossOrder = nil
 	}
 
+	// re-compute the quantity from the live position, the position might be partially reduced since the last activation
+	quantity := s.stopOrderQuantity(position, released)
+	if quantity.IsZero() {
+		return nil
+	}
+
+	side := types.SideTypeSell
+	slippage := one.Sub(fixedpoint.NewFromFloat(0.005)) // -0.5% from the trigger price, slippage protection
+	if position.IsShort() {
+		side = types.SideTypeBuy
+		slippage = one.Add(fixedpoint.NewFromFloat(0.005)) // +0.5% from the trigger price, slippage protection
+	}
+
 	createdOrders, err := orderExecutor.SubmitOrders(ctx, types.SubmitOrder{
 		Symbol:           position.Symbol,
-		Side:             types.SideTypeBuy,
+		Side:             side,
 		Type:             types.OrderTypeStopLimit,
-		Quantity:         position.GetQuantity(),
-		Price:            s.stopLossPrice.Mul(one.Add(fixedpoint.NewFromFloat(0.005))), // +0.5% from the trigger price, slippage protection
+		Quantity:         quantity,
+		Price:            s.stopLossPrice.Mul(slippage),
 		StopPrice:        s.stopLossPrice,
 		Market:           position.Market,
 		Tag:              "protectiveStopLoss",
@@ -82,6 +133,45 @@ func (s *ProtectiveStopLoss) placeStopOrder(ctx context.Context, position *types
 	return err
 }
 
+// stopOrderQuantity returns the quantity of the current position to close with ClosePercentage,
+// for spot long positions, the quantity is capped to the available base balance plus the released quantity
+// of the canceled stop order to avoid the insufficient balance error.
+func (s *ProtectiveStopLoss) stopOrderQuantity(position *types.Position, released fixedpoint.Value) fixedpoint.Value {
+	quantity := position.GetQuantity()
+	if closePercentage := s.closePercentage(); closePercentage.Compare(one) < 0 {
+		quantity = position.Market.TruncateQuantity(quantity.Mul(closePercentage))
+	}
+
+	if s.session == nil || s.session.Futures || s.session.Margin || !position.IsLong() {
+		return quantity
+	}
+
+	if baseBalance, ok := s.session.GetAccount().Balance(position.Market.BaseCurrency); ok {
+		quantity = fixedpoint.Min(quantity, baseBalance.Available.Add(released))
+	}
+
+	return quantity
+}
+
+// hasEnoughPosition checks if the position quantity exceeds the MinPositionQuantity threshold
+func (s *ProtectiveStopLoss) hasEnoughPosition(position *types.Position) bool {
+	if s.MinPositionQuantity.IsZero() {
+		return true
+	}
+
+	return position.GetQuantity().Compare(s.MinPositionQuantity) > 0
+}
+
+func (s *ProtectiveStopLoss) calculateStopLossPrice(position *types.Position) fixedpoint.Value {
+	if position.IsShort() {
+		return position.AverageCost.Mul(one.Sub(s.StopLossRatio))
+	} else if position.IsLong() {
+		return position.AverageCost.Mul(one.Add(s.StopLossRatio))
+	}
+
+	return fixedpoint.Zero
+}
+
 func (s *ProtectiveStopLoss) shouldStop(closePrice fixedpoint.Value, position *types.Position) bool {
 	if s.stopLossPrice.IsZero() {
 		return false
@@ -144,32 +234,40 @@ func (s *ProtectiveStopLoss) Bind(session *ExchangeSession, orderExecutor *Gener
 				return
 			}
 
+			if !s.hasEnoughPosition(position) {
+				return
+			}
+
 			s.checkStopPrice(trade.Price, position)
 		})
 	}
 }
 
-func (s *ProtectiveStopLoss) handleChange(ctx context.Context, position *types.Position, closePrice fixedpoint.Value, orderExecutor *GeneralOrderExecutor) {
+func (s *ProtectiveStopLoss) handleChange(ctx context.Context, position *types.Position, closePrice fixedpoint.Value, orderExecutor OrderExecutor) {
+	// skip the dust positions
+	if !s.hasEnoughPosition(position) {
+		return
+	}
+
 	if s.stopLossOrder != nil {
-		// use RESTful to query the order status
-		// orderQuery := orderExecutor.Session().Exchange.(types.ExchangeOrderQueryService)
-		// order, err := orderQuery.QueryOrder(ctx, types.OrderQuery{
-		// 	Symbol:  s.stopLossOrder.Symbol,
-		// 	OrderID: strconv.FormatUint(s.stopLossOrder.OrderID, 10),
-		// })
-		// if err != nil {
-		// 	log.WithError(err).Errorf("query order failed")
-		// }
+		// the stop order is already resting on the exchange,
+		// only replace it when the trigger price is changed, e.g., the average cost is updated by the new trades
+		stopLossPrice := s.calculateStopLossPrice(position)
+		if stopLossPrice.IsZero() || stopLossPrice.Compare(s.stopLossPrice) == 0 {
+			return
+		}
+
+		s.stopLossPrice = stopLossPrice
+		if err := s.placeStopOrder(ctx, position, orderExecutor); err != nil {
+			log.WithError(err).Errorf("failed to update stop limit order")
+		}
+		return
 	}
 
 	if s.stopLossPrice.IsZero() {
 		if s.shouldActivate(position, closePrice) {
 			// calculate stop loss price
-			if position.IsShort() {
-				s.stopLossPrice = position.AverageCost.Mul(one.Sub(s.StopLossRatio))
-			} else if position.IsLong() {
-				s.stopLossPrice = position.AverageCost.Mul(one.Add(s.StopLossRatio))
-			}
+			s.stopLossPrice = s.calculateStopLossPrice(position)
 
 			Notify("[ProtectiveStopLoss] %s protection (%s) stop loss activated, SL = %f, currentPrice = %f, averageCost = %f",
 				position.Symbol,
@@ -178,6 +276,8 @@ func (s *ProtectiveStopLoss) handleChange(ctx context.Context, position *types.P
 				closePrice.Float64(),
 				position.AverageCost.Float64())
 
+			s.EmitActivated(s.stopLossPrice)
+
 			if s.PlaceStopOrder {
 				if err := s.placeStopOrder(ctx, position, orderExecutor); err != nil {
 					log.WithError(err).Errorf("failed to place stop limit order")
@@ -205,8 +305,16 @@ func (s *ProtectiveStopLoss) checkStopPrice(closePrice fixedpoint.Value, positio
 			s.StopLossRatio.Percentage(),
 			closePrice.Float64(),
 			position)
-		if err := s.orderExecutor.ClosePosition(context.Background(), one, "protectiveStopLoss"); err != nil {
+
+		s.EmitTriggered(closePrice)
+
+		if err := s.orderExecutor.ClosePosition(context.Background(), s.closePercentage(), "protectiveStopLoss"); err != nil {
 			log.WithError(err).Errorf("failed to close position")
+			return
 		}
+
+		// the stop is one-shot, the remaining position of a partial close needs to be activated again,
+		// otherwise every following price below the stop price closes another part of the position.
+		s.stopLossPrice = fixedpoint.Zero
 	}
 }
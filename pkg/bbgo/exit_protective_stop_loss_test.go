@@ -0,0 +1,325 @@
+package bbgo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	bbgomocks "github.com/c9s/bbgo/pkg/bbgo/mocks"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/types/mocks"
+)
+
+func TestProtectiveStopLoss_placeStopOrder_PositionReduced(t *testing.T) {
+	market := getTestMarket()
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+
+	session := NewExchangeSession("test", mockEx)
+	session.Account = types.NewAccount()
+	session.Account.UpdateBalances(types.BalanceMap{
+		"BTC": {Currency: "BTC", Available: fixedpoint.NewFromFloat(1.0)},
+	})
+
+	position := types.NewPositionFromMarket(market)
+	position.AverageCost = fixedpoint.NewFromFloat(20000.0)
+	position.Base = fixedpoint.NewFromFloat(1.0)
+
+	stopLoss := &ProtectiveStopLoss{
+		Symbol:         "BTCUSDT",
+		PlaceStopOrder: true,
+		session:        session,
+		stopLossPrice:  fixedpoint.NewFromFloat(20020.0),
+	}
+
+	var submitted []types.SubmitOrder
+	orderExecutor := bbgomocks.NewMockOrderExecutorExtended(mockCtrl)
+	orderExecutor.EXPECT().SubmitOrders(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, orders ...types.SubmitOrder) (types.OrderSlice, error) {
+			submitted = append(submitted, orders...)
+			return types.OrderSlice{{SubmitOrder: orders[0], OrderID: uint64(len(submitted))}}, nil
+		}).Times(3)
+	orderExecutor.EXPECT().CancelOrders(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+	ctx := context.Background()
+	if assert.NoError(t, stopLoss.placeStopOrder(ctx, position, orderExecutor)) {
+		assert.Equal(t, types.SideTypeSell, submitted[0].Side)
+		assert.Equal(t, "1", submitted[0].Quantity.String())
+	}
+
+	// the position is partially reduced, the stop order should follow the live position,
+	// the base locked by the canceled stop order is not released in the local balance yet
+	position.Base = fixedpoint.MustNewFromString("0.4")
+	session.Account.UpdateBalances(types.BalanceMap{
+		"BTC": {Currency: "BTC", Available: fixedpoint.Zero, Locked: fixedpoint.MustNewFromString("0.4")},
+	})
+	if assert.NoError(t, stopLoss.placeStopOrder(ctx, position, orderExecutor)) {
+		assert.Equal(t, "0.4", submitted[1].Quantity.String())
+	}
+
+	// the quantity is capped to the available base balance and the released quantity of the canceled stop order
+	session.Account.UpdateBalances(types.BalanceMap{
+		"BTC": {Currency: "BTC", Available: fixedpoint.Zero, Locked: fixedpoint.MustNewFromString("0.4")},
+	})
+	stopLoss.stopLossOrder.ExecutedQuantity = fixedpoint.MustNewFromString("0.1")
+	if assert.NoError(t, stopLoss.placeStopOrder(ctx, position, orderExecutor)) {
+		assert.Equal(t, "0.3", submitted[2].Quantity.String())
+	}
+}
+
+func TestProtectiveStopLoss_placeStopOrder_AvailableBalance(t *testing.T) {
+	market := getTestMarket()
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+
+	session := NewExchangeSession("test", mockEx)
+	session.Account = types.NewAccount()
+	session.Account.UpdateBalances(types.BalanceMap{
+		"BTC": {Currency: "BTC", Available: fixedpoint.MustNewFromString("0.3")},
+	})
+
+	position := types.NewPositionFromMarket(market)
+	position.AverageCost = fixedpoint.NewFromFloat(20000.0)
+	position.Base = fixedpoint.MustNewFromString("0.4")
+
+	stopLoss := &ProtectiveStopLoss{
+		Symbol:         "BTCUSDT",
+		PlaceStopOrder: true,
+		session:        session,
+		stopLossPrice:  fixedpoint.NewFromFloat(20020.0),
+	}
+
+	var submitted []types.SubmitOrder
+	orderExecutor := bbgomocks.NewMockOrderExecutorExtended(mockCtrl)
+	orderExecutor.EXPECT().SubmitOrders(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, orders ...types.SubmitOrder) (types.OrderSlice, error) {
+			submitted = append(submitted, orders...)
+			return types.OrderSlice{{SubmitOrder: orders[0], OrderID: 1}}, nil
+		})
+
+	// without a resting stop order, the quantity is capped to the available base balance
+	if assert.NoError(t, stopLoss.placeStopOrder(context.Background(), position, orderExecutor)) && assert.Len(t, submitted, 1) {
+		assert.Equal(t, "0.3", submitted[0].Quantity.String())
+	}
+}
+
+func TestProtectiveStopLoss_handleChange_MinPositionQuantity(t *testing.T) {
+	market := getTestMarket()
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+
+	session := NewExchangeSession("test", mockEx)
+	session.Futures = true
+
+	position := types.NewPositionFromMarket(market)
+	position.AverageCost = fixedpoint.NewFromFloat(20000.0)
+	position.Base = fixedpoint.MustNewFromString("-0.0005")
+
+	stopLoss := &ProtectiveStopLoss{
+		Symbol:              "BTCUSDT",
+		ActivationRatio:     fixedpoint.MustNewFromString("0.01"),
+		StopLossRatio:       fixedpoint.MustNewFromString("0.001"),
+		PlaceStopOrder:      true,
+		MinPositionQuantity: fixedpoint.MustNewFromString("0.001"),
+		session:             session,
+	}
+
+	var submitted []types.SubmitOrder
+	orderExecutor := bbgomocks.NewMockOrderExecutorExtended(mockCtrl)
+	orderExecutor.EXPECT().SubmitOrders(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, orders ...types.SubmitOrder) (types.OrderSlice, error) {
+			submitted = append(submitted, orders...)
+			return types.OrderSlice{{SubmitOrder: orders[0], OrderID: uint64(len(submitted))}}, nil
+		}).Times(2)
+	orderExecutor.EXPECT().CancelOrders(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	ctx := context.Background()
+
+	// the dust position should not activate the stop
+	stopLoss.handleChange(ctx, position, fixedpoint.NewFromFloat(19000.0), orderExecutor)
+	assert.Empty(t, submitted)
+	assert.True(t, stopLoss.stopLossPrice.IsZero())
+
+	position.Base = fixedpoint.NewFromFloat(-1.0)
+	stopLoss.handleChange(ctx, position, fixedpoint.NewFromFloat(19000.0), orderExecutor)
+	if assert.Len(t, submitted, 1) {
+		assert.Equal(t, types.SideTypeBuy, submitted[0].Side)
+		assert.Equal(t, "19980", submitted[0].StopPrice.String())
+	}
+
+	// the stop order is resting and the trigger price is unchanged, nothing should be replaced
+	stopLoss.handleChange(ctx, position, fixedpoint.NewFromFloat(19100.0), orderExecutor)
+	stopLoss.handleChange(ctx, position, fixedpoint.NewFromFloat(19200.0), orderExecutor)
+	assert.Len(t, submitted, 1)
+
+	// the average cost is changed, the stop order should be replaced with the new trigger price
+	position.AverageCost = fixedpoint.NewFromFloat(19500.0)
+	stopLoss.handleChange(ctx, position, fixedpoint.NewFromFloat(19200.0), orderExecutor)
+	if assert.Len(t, submitted, 2) {
+		assert.Equal(t, "19480.5", submitted[1].StopPrice.String())
+	}
+}
+
+func TestProtectiveStopLoss_Validate(t *testing.T) {
+	assert.NoError(t, (&ProtectiveStopLoss{}).Validate())
+	assert.NoError(t, (&ProtectiveStopLoss{ClosePercentage: fixedpoint.MustNewFromString("0.5")}).Validate())
+	assert.NoError(t, (&ProtectiveStopLoss{ClosePercentage: fixedpoint.One}).Validate())
+	assert.Error(t, (&ProtectiveStopLoss{ClosePercentage: fixedpoint.MustNewFromString("1.1")}).Validate())
+	assert.Error(t, (&ProtectiveStopLoss{ClosePercentage: fixedpoint.MustNewFromString("-0.5")}).Validate())
+}
+
+func TestProtectiveStopLoss_checkStopPrice_ClosePercentage(t *testing.T) {
+	market := getTestMarket()
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+
+	session := NewExchangeSession("test", mockEx)
+	session.markets[market.Symbol] = market
+	session.Account.UpdateBalances(types.BalanceMap{
+		"BTC": {Currency: "BTC", Available: fixedpoint.One},
+	})
+
+	position := types.NewPositionFromMarket(market)
+	position.AverageCost = fixedpoint.NewFromFloat(20000.0)
+	position.Base = fixedpoint.One
+
+	orderExecutor := NewGeneralOrderExecutor(session, "BTCUSDT", "test", "test-01", position)
+
+	stopLoss := &ProtectiveStopLoss{
+		Symbol:          "BTCUSDT",
+		ActivationRatio: fixedpoint.MustNewFromString("0.01"),
+		StopLossRatio:   fixedpoint.MustNewFromString("0.001"),
+		ClosePercentage: fixedpoint.MustNewFromString("0.5"),
+		session:         session,
+		orderExecutor:   orderExecutor,
+		stopLossPrice:   fixedpoint.NewFromFloat(20020.0),
+	}
+
+	// only half of the position should be closed, and only once
+	mockEx.EXPECT().SubmitOrder(gomock.Any(), types.SubmitOrder{
+		Symbol:           "BTCUSDT",
+		Side:             types.SideTypeSell,
+		Type:             types.OrderTypeMarket,
+		Market:           market,
+		Quantity:         fixedpoint.MustNewFromString("0.5"),
+		MarginSideEffect: types.SideEffectTypeAutoRepay,
+		Tag:              "protectiveStopLoss",
+	}).Return(&types.Order{}, nil).Times(1)
+
+	ctx := context.Background()
+	stopLoss.handleChange(ctx, position, fixedpoint.NewFromFloat(20010.0), orderExecutor)
+	assert.True(t, stopLoss.stopLossPrice.IsZero(), "the stop should be re-armed after the partial close")
+
+	// the next kline below the stop price should not close the position again
+	stopLoss.handleChange(ctx, position, fixedpoint.NewFromFloat(20000.0), orderExecutor)
+}
+
+func TestProtectiveStopLoss_placeStopOrder_ClosePercentage(t *testing.T) {
+	market := getTestMarket()
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+
+	session := NewExchangeSession("test", mockEx)
+	session.Account = types.NewAccount()
+	session.Account.UpdateBalances(types.BalanceMap{
+		"BTC": {Currency: "BTC", Available: fixedpoint.One},
+	})
+
+	position := types.NewPositionFromMarket(market)
+	position.AverageCost = fixedpoint.NewFromFloat(20000.0)
+	position.Base = fixedpoint.One
+
+	stopLoss := &ProtectiveStopLoss{
+		Symbol:          "BTCUSDT",
+		PlaceStopOrder:  true,
+		ClosePercentage: fixedpoint.MustNewFromString("0.5"),
+		session:         session,
+		stopLossPrice:   fixedpoint.NewFromFloat(20020.0),
+	}
+
+	var submitted []types.SubmitOrder
+	orderExecutor := bbgomocks.NewMockOrderExecutorExtended(mockCtrl)
+	orderExecutor.EXPECT().SubmitOrders(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, orders ...types.SubmitOrder) (types.OrderSlice, error) {
+			submitted = append(submitted, orders...)
+			return types.OrderSlice{{SubmitOrder: orders[0], OrderID: 1}}, nil
+		})
+
+	if assert.NoError(t, stopLoss.placeStopOrder(context.Background(), position, orderExecutor)) && assert.Len(t, submitted, 1) {
+		assert.Equal(t, "0.5", submitted[0].Quantity.String())
+	}
+}
+
+func TestProtectiveStopLoss_Callbacks(t *testing.T) {
+	market := getTestMarket()
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+
+	session := NewExchangeSession("test", mockEx)
+	session.markets[market.Symbol] = market
+	session.Account.UpdateBalances(types.BalanceMap{
+		"BTC": {Currency: "BTC", Available: fixedpoint.One},
+	})
+
+	position := types.NewPositionFromMarket(market)
+	position.AverageCost = fixedpoint.NewFromFloat(20000.0)
+	position.Base = fixedpoint.One
+
+	orderExecutor := NewGeneralOrderExecutor(session, "BTCUSDT", "test", "test-01", position)
+
+	stopLoss := &ProtectiveStopLoss{
+		Symbol:          "BTCUSDT",
+		ActivationRatio: fixedpoint.MustNewFromString("0.01"),
+		StopLossRatio:   fixedpoint.MustNewFromString("0.001"),
+		session:         session,
+		orderExecutor:   orderExecutor,
+	}
+
+	var activatedPrice, triggeredPrice fixedpoint.Value
+	stopLoss.OnActivated(func(price fixedpoint.Value) {
+		activatedPrice = price
+	})
+	stopLoss.OnTriggered(func(price fixedpoint.Value) {
+		triggeredPrice = price
+	})
+
+	ctx := context.Background()
+
+	stopLoss.handleChange(ctx, position, fixedpoint.NewFromFloat(20100.0), orderExecutor)
+	assert.True(t, activatedPrice.IsZero(), "should not be activated before the activation price")
+
+	stopLoss.handleChange(ctx, position, fixedpoint.NewFromFloat(20300.0), orderExecutor)
+	assert.InDelta(t, 20020.0, activatedPrice.Float64(), 0.0001)
+	assert.True(t, triggeredPrice.IsZero())
+
+	mockEx.EXPECT().SubmitOrder(gomock.Any(), gomock.Any()).Return(&types.Order{}, nil)
+	stopLoss.handleChange(ctx, position, fixedpoint.NewFromFloat(20010.0), orderExecutor)
+	assert.Equal(t, "20010", triggeredPrice.String())
+}
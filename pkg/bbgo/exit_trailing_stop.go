@@ -47,9 +47,7 @@ func (s *TrailingStop2) Subscribe(session *ExchangeSession) {
 }
 
 func (s *TrailingStop2) Bind(session *ExchangeSession, orderExecutor *GeneralOrderExecutor) {
-	s.session = session
-	s.orderExecutor = orderExecutor
-	s.latestHigh = fixedpoint.Zero
+	s.BindOrderExecutor(session, orderExecutor)
 
 	position := orderExecutor.Position()
 	f := func(kline types.KLine) {
@@ -70,6 +68,34 @@ func (s *TrailingStop2) Bind(session *ExchangeSession, orderExecutor *GeneralOrd
 	}
 }
 
+// BindOrderExecutor binds the session and the order executor without subscribing the price updates,
+// the price is fed by the caller, e.g., an exit method that arms the trailing stop after its own exit.
+func (s *TrailingStop2) BindOrderExecutor(session *ExchangeSession, orderExecutor *GeneralOrderExecutor) {
+	s.session = session
+	s.orderExecutor = orderExecutor
+	s.latestHigh = fixedpoint.Zero
+}
+
+// Arm restarts the trailing from the next price, the trailing stop is activated right away
+// if it has no activation ratio and no min profit, otherwise it's activated by the price.
+func (s *TrailingStop2) Arm() {
+	s.latestHigh = fixedpoint.Zero
+	if s.ActivationRatio.IsZero() && s.MinProfit.IsZero() {
+		s.activated = true
+	}
+}
+
+// Reset deactivates the trailing stop and clears the latest high
+func (s *TrailingStop2) Reset() {
+	s.activated = false
+	s.latestHigh = fixedpoint.Zero
+}
+
+// IsActivated returns true if the trailing stop is activated
+func (s *TrailingStop2) IsActivated() bool {
+	return s.activated
+}
+
 // getRatio returns the ratio between the price and the average cost of the position
 func (s *TrailingStop2) getRatio(price fixedpoint.Value, position *types.Position) (fixedpoint.Value, error) {
 	switch s.Side {
@@ -0,0 +1,201 @@
+package bbgo
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// DefaultKLineCloseGracePeriod is the grace period used when KLineCloseGracePeriod is not set
+const DefaultKLineCloseGracePeriod = 5 * time.Second
+
+// klineCloseGrace waits for the confirmed closed kline from the websocket within the grace period,
+// once the provisional kline passed the interval boundary. When the grace period is over,
+// the closed kline is queried via REST. Only the closed klines are passed to the callback.
+type klineCloseGrace struct {
+	session     *ExchangeSession
+	symbol      string
+	interval    types.Interval
+	gracePeriod time.Duration
+	callback    func(k types.KLine)
+
+	// now and afterFunc are the clock of the grace period, they can be replaced for testing
+	now       func() time.Time
+	afterFunc AfterFunc
+
+	mu sync.Mutex
+
+	// forming is the last provisional kline
+	forming types.KLine
+
+	// lastClosed is the start time of the last emitted closed kline
+	lastClosed time.Time
+
+	// pending is the start time of the kline waiting for the confirmation
+	pending time.Time
+}
+
+// OnConfirmedKLineClosed registers the callback of the confirmed closed kline of the symbol and interval.
+// The provisional kline passing the interval boundary is never used, the callback waits for the confirmed kline
+// or the kline queried via REST after KLineCloseGracePeriod (DefaultKLineCloseGracePeriod if it's not set).
+// A negative KLineCloseGracePeriod disables the REST fallback.
+func (session *ExchangeSession) OnConfirmedKLineClosed(symbol string, interval types.Interval, cb func(k types.KLine)) {
+	gracePeriod := session.KLineCloseGracePeriod.Duration()
+	if gracePeriod < 0 {
+		session.MarketDataStream.OnKLineClosed(types.KLineWith(symbol, interval, cb))
+		return
+	}
+
+	if gracePeriod == 0 {
+		gracePeriod = DefaultKLineCloseGracePeriod
+	}
+
+	g := newKLineCloseGrace(session, symbol, interval, gracePeriod, cb)
+	g.Bind(session.MarketDataStream)
+}
+
+func newKLineCloseGrace(session *ExchangeSession, symbol string, interval types.Interval, gracePeriod time.Duration, cb func(k types.KLine)) *klineCloseGrace {
+	return &klineCloseGrace{
+		session:     session,
+		symbol:      symbol,
+		interval:    interval,
+		gracePeriod: gracePeriod,
+		callback:    cb,
+		now:         time.Now,
+		afterFunc: func(d time.Duration, f func()) {
+			time.AfterFunc(d, f)
+		},
+	}
+}
+
+func (g *klineCloseGrace) Bind(stream types.Stream) {
+	stream.OnKLine(types.KLineWith(g.symbol, g.interval, g.handleKLine))
+	stream.OnKLineClosed(types.KLineWith(g.symbol, g.interval, g.handleKLineClosed))
+}
+
+func (g *klineCloseGrace) handleKLine(k types.KLine) {
+	if k.Closed {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	// a new bar is forming while the previous bar is not confirmed yet
+	if !g.forming.StartTime.Time().IsZero() && k.StartTime.Time().After(g.forming.StartTime.Time()) {
+		g.waitConfirmation(g.forming)
+	}
+
+	g.forming = k
+
+	// the provisional bar passed the interval boundary
+	if g.now().After(k.EndTime.Time()) {
+		g.waitConfirmation(k)
+	}
+}
+
+func (g *klineCloseGrace) handleKLineClosed(k types.KLine) {
+	if !k.Closed {
+		return
+	}
+
+	startTime := k.StartTime.Time()
+
+	g.mu.Lock()
+	if !startTime.After(g.lastClosed) {
+		// the closed kline was already emitted from the REST query
+		g.mu.Unlock()
+		return
+	}
+
+	g.lastClosed = startTime
+	g.mu.Unlock()
+
+	g.callback(k)
+}
+
+// waitConfirmation starts the grace period timer of the provisional kline, the caller must hold the lock
+func (g *klineCloseGrace) waitConfirmation(k types.KLine) {
+	startTime := k.StartTime.Time()
+	if !startTime.After(g.lastClosed) || g.pending.Equal(startTime) {
+		return
+	}
+
+	g.pending = startTime
+	g.afterFunc(g.gracePeriod, func() {
+		g.handleTimeout(k)
+	})
+}
+
+func (g *klineCloseGrace) isEmitted(startTime time.Time) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return !startTime.After(g.lastClosed)
+}
+
+func (g *klineCloseGrace) handleTimeout(provisional types.KLine) {
+	startTime := provisional.StartTime.Time()
+	if g.isEmitted(startTime) {
+		return
+	}
+
+	log.Warnf("%s %s kline %s is not confirmed within %s, querying the kline via REST",
+		g.symbol, g.interval, startTime, g.gracePeriod)
+
+	closed, ok := g.queryKLine(provisional)
+	if !ok {
+		// never use the provisional kline, keep waiting for the confirmation and retry after another grace period
+		g.mu.Lock()
+		if startTime.After(g.lastClosed) {
+			g.afterFunc(g.gracePeriod, func() {
+				g.handleTimeout(provisional)
+			})
+		}
+		g.mu.Unlock()
+		return
+	}
+
+	g.mu.Lock()
+	if !startTime.After(g.lastClosed) {
+		g.mu.Unlock()
+		return
+	}
+
+	g.lastClosed = startTime
+	g.mu.Unlock()
+
+	g.callback(closed)
+}
+
+// queryKLine queries the closed kline of the provisional kline via REST
+func (g *klineCloseGrace) queryKLine(provisional types.KLine) (types.KLine, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	startTime := provisional.StartTime.Time()
+	endTime := provisional.EndTime.Time()
+	kLines, err := g.session.Exchange.QueryKLines(ctx, g.symbol, g.interval, types.KLineQueryOptions{
+		StartTime: &startTime,
+		EndTime:   &endTime,
+		Limit:     1,
+	})
+	if err != nil {
+		log.WithError(err).Errorf("unable to query %s %s kline", g.symbol, g.interval)
+		return types.KLine{}, false
+	}
+
+	for _, k := range kLines {
+		if k.StartTime.Time().Equal(startTime) {
+			// the kline queried after the interval boundary and the grace period is closed
+			k.Closed = true
+			return k, true
+		}
+	}
+
+	log.Warnf("%s %s kline %s not found", g.symbol, g.interval, startTime)
+	return types.KLine{}, false
+}
@@ -0,0 +1,113 @@
+package bbgo
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/types/mocks"
+)
+
+func TestKLineCloseGrace(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+
+	session := NewExchangeSession("test", mockEx)
+	stream := session.MarketDataStream.(*types.StandardStream)
+
+	var closed []types.KLine
+	g := newKLineCloseGrace(session, "BTCUSDT", types.Interval1m, 5*time.Second, func(k types.KLine) {
+		closed = append(closed, k)
+	})
+
+	clock := newFakeThrottleClock()
+	g.now = clock.Now
+	g.afterFunc = clock.AfterFunc
+	g.Bind(stream)
+
+	newKLine := func(startTime time.Time, close float64, isClosed bool) types.KLine {
+		return types.KLine{
+			Symbol:    "BTCUSDT",
+			Interval:  types.Interval1m,
+			StartTime: types.Time(startTime),
+			EndTime:   types.Time(startTime.Add(time.Minute - time.Millisecond)),
+			Close:     number(close),
+			Closed:    isClosed,
+		}
+	}
+
+	// the provisional bar passed the boundary, the confirmed bar arrives within the grace period
+	startTime := clock.Now().Add(-10 * time.Minute)
+	stream.EmitKLine(newKLine(startTime, 19000.0, false))
+	assert.Empty(t, closed, "the provisional bar should not be used")
+
+	stream.EmitKLineClosed(newKLine(startTime, 19010.0, true))
+	clock.Advance(5 * time.Second)
+	if assert.Len(t, closed, 1) {
+		assert.Equal(t, number(19010.0), closed[0].Close)
+	}
+
+	// the confirmed bar is late, the kline is queried via REST after the grace period
+	startTime = startTime.Add(time.Minute)
+	stream.EmitKLine(newKLine(startTime, 19020.0, false))
+	assert.Len(t, closed, 1)
+
+	mockEx.EXPECT().QueryKLines(gomock.Any(), "BTCUSDT", types.Interval1m, gomock.Any()).
+		Return([]types.KLine{newKLine(startTime, 19030.0, false)}, nil)
+	clock.Advance(5 * time.Second)
+	if assert.Len(t, closed, 2) {
+		assert.Equal(t, number(19030.0), closed[1].Close)
+		assert.True(t, closed[1].Closed)
+	}
+
+	// the late confirmation is ignored
+	stream.EmitKLineClosed(newKLine(startTime, 19030.0, true))
+	assert.Len(t, closed, 2)
+
+	// the REST query fails, the provisional bar is never used and the query is retried
+	startTime = startTime.Add(time.Minute)
+	stream.EmitKLine(newKLine(startTime, 19040.0, false))
+
+	mockEx.EXPECT().QueryKLines(gomock.Any(), "BTCUSDT", types.Interval1m, gomock.Any()).
+		Return(nil, errors.New("service unavailable"))
+	clock.Advance(5 * time.Second)
+	assert.Len(t, closed, 2)
+
+	mockEx.EXPECT().QueryKLines(gomock.Any(), "BTCUSDT", types.Interval1m, gomock.Any()).
+		Return([]types.KLine{newKLine(startTime, 19050.0, false)}, nil)
+	clock.Advance(5 * time.Second)
+	if assert.Len(t, closed, 3) {
+		assert.Equal(t, number(19050.0), closed[2].Close)
+		assert.True(t, closed[2].Closed)
+	}
+}
+
+func TestExchangeSession_OnConfirmedKLineClosed_Disabled(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+
+	session := NewExchangeSession("test", mockEx)
+	session.KLineCloseGracePeriod = types.Duration(-1)
+
+	var closed []types.KLine
+	session.OnConfirmedKLineClosed("BTCUSDT", types.Interval1m, func(k types.KLine) {
+		closed = append(closed, k)
+	})
+
+	stream := session.MarketDataStream.(*types.StandardStream)
+	stream.EmitKLine(types.KLine{Symbol: "BTCUSDT", Interval: types.Interval1m, Close: number(19000.0)})
+	assert.Empty(t, closed)
+
+	stream.EmitKLineClosed(types.KLine{Symbol: "BTCUSDT", Interval: types.Interval1m, Close: number(19010.0), Closed: true})
+	assert.Len(t, closed, 1)
+}
@@ -0,0 +1,35 @@
+package bbgo
+
+import (
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// EstimateMarketImpact walks the order book levels of the opposite side to estimate the average fill price
+// of a market order with the given quantity, e.g., a buy order consumes the asks from the best ask price.
+// It returns the volume-weighted average price and the filled quantity,
+// the filled quantity is less than the given quantity when the book is too thin to fill the whole order.
+func EstimateMarketImpact(book types.SliceOrderBook, side types.SideType, quantity fixedpoint.Value) (avgPrice fixedpoint.Value, filled fixedpoint.Value) {
+	if quantity.Sign() <= 0 {
+		return fixedpoint.Zero, fixedpoint.Zero
+	}
+
+	var quoteAmount = fixedpoint.Zero
+	var remaining = quantity
+	for _, pv := range book.SideBook(side.Reverse()) {
+		if remaining.Sign() <= 0 {
+			break
+		}
+
+		volume := fixedpoint.Min(pv.Volume, remaining)
+		quoteAmount = quoteAmount.Add(pv.Price.Mul(volume))
+		filled = filled.Add(volume)
+		remaining = remaining.Sub(volume)
+	}
+
+	if filled.IsZero() {
+		return fixedpoint.Zero, fixedpoint.Zero
+	}
+
+	return quoteAmount.Div(filled), filled
+}
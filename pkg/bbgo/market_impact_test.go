@@ -0,0 +1,52 @@
+package bbgo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestEstimateMarketImpact(t *testing.T) {
+	book := types.SliceOrderBook{
+		Symbol: "BTCUSDT",
+		Bids: types.PriceVolumeSlice{
+			{Price: fixedpoint.NewFromFloat(19990.0), Volume: fixedpoint.NewFromFloat(1.0)},
+			{Price: fixedpoint.NewFromFloat(19980.0), Volume: fixedpoint.NewFromFloat(2.0)},
+		},
+		Asks: types.PriceVolumeSlice{
+			{Price: fixedpoint.NewFromFloat(20000.0), Volume: fixedpoint.NewFromFloat(1.0)},
+			{Price: fixedpoint.NewFromFloat(20010.0), Volume: fixedpoint.NewFromFloat(1.0)},
+			{Price: fixedpoint.NewFromFloat(20020.0), Volume: fixedpoint.NewFromFloat(2.0)},
+		},
+	}
+
+	t.Run("buy spanning several levels", func(t *testing.T) {
+		// (20000 * 1 + 20010 * 1 + 20020 * 2) / 4
+		avgPrice, filled := EstimateMarketImpact(book, types.SideTypeBuy, fixedpoint.NewFromFloat(4.0))
+		assert.Equal(t, "20012.5", avgPrice.String())
+		assert.Equal(t, "4", filled.String())
+	})
+
+	t.Run("sell partially consumes a level", func(t *testing.T) {
+		// (19990 * 1 + 19980 * 1) / 2
+		avgPrice, filled := EstimateMarketImpact(book, types.SideTypeSell, fixedpoint.NewFromFloat(2.0))
+		assert.Equal(t, "19985", avgPrice.String())
+		assert.Equal(t, "2", filled.String())
+	})
+
+	t.Run("thin book", func(t *testing.T) {
+		// (19990 * 1 + 19980 * 2) / 3
+		avgPrice, filled := EstimateMarketImpact(book, types.SideTypeSell, fixedpoint.NewFromFloat(5.0))
+		assert.Equal(t, "19983.33", avgPrice.Round(2, fixedpoint.HalfUp).String())
+		assert.Equal(t, "3", filled.String())
+	})
+
+	t.Run("empty book", func(t *testing.T) {
+		avgPrice, filled := EstimateMarketImpact(types.SliceOrderBook{}, types.SideTypeBuy, fixedpoint.NewFromFloat(1.0))
+		assert.True(t, avgPrice.IsZero())
+		assert.True(t, filled.IsZero())
+	})
+}
@@ -1,6 +1,12 @@
 package bbgo
 
-import "github.com/c9s/bbgo/pkg/types"
+import (
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
 
 const MaxNumOfKLines = 5_000
 const MaxNumOfKLinesTruncate = 100
@@ -13,8 +19,21 @@ type MarketDataStore struct {
 	// KLineWindows stores all loaded klines per interval
 	KLineWindows map[types.Interval]*types.KLineWindow `json:"-"`
 
+	// mu protects the kline windows, the stream adds the klines while the strategies read them
+	mu sync.RWMutex
+
+	// maxNumOfKLines is the max number of klines to keep per interval, the oldest klines are dropped.
+	// if it's zero, the window is truncated by MaxNumOfKLines and MaxNumOfKLinesTruncate
+	maxNumOfKLines int
+
+	// repairKLines repairs the klines with inconsistent OHLC values before adding them
+	repairKLines bool
+
 	kLineWindowUpdateCallbacks []func(interval types.Interval, klines types.KLineWindow)
 	kLineClosedCallbacks       []func(k types.KLine)
+
+	// kLineUpdateCallbacks are called with the forming (unclosed) kline, the kline windows are not updated
+	kLineUpdateCallbacks []func(k types.KLine)
 }
 
 func NewMarketDataStore(symbol string) *MarketDataStore {
@@ -26,18 +45,90 @@ func NewMarketDataStore(symbol string) *MarketDataStore {
 	}
 }
 
+// SetRepairKLines enables repairing the klines with inconsistent OHLC values (high < max(open, close) or low > min(open, close)),
+// a warning is logged when a kline is repaired.
+func (store *MarketDataStore) SetRepairKLines(enabled bool) {
+	store.repairKLines = enabled
+}
+
+// SetMaxNumOfKLines sets the max number of klines to keep per interval,
+// once a window exceeds the limit, only the latest n klines are kept.
+func (store *MarketDataStore) SetMaxNumOfKLines(n int) {
+	store.mu.Lock()
+	store.maxNumOfKLines = n
+	store.mu.Unlock()
+}
+
+// SetKLineWindows replaces the kline windows, the windows exceeding the max number of klines are truncated
 func (store *MarketDataStore) SetKLineWindows(windows map[types.Interval]*types.KLineWindow) {
+	store.mu.Lock()
+	for _, window := range windows {
+		store.truncate(window)
+	}
+
 	store.KLineWindows = windows
+	store.mu.Unlock()
 }
 
-// KLinesOfInterval returns the kline window of the given interval
+// truncate drops the oldest klines of the window, the caller must hold the lock
+func (store *MarketDataStore) truncate(window *types.KLineWindow) {
+	if store.maxNumOfKLines > 0 {
+		if len(*window) > store.maxNumOfKLines {
+			*window = (*window)[len(*window)-store.maxNumOfKLines:]
+		}
+	} else if len(*window) > MaxNumOfKLines {
+		*window = (*window)[MaxNumOfKLinesTruncate-1:]
+	}
+}
+
+// KLinesOfInterval returns the kline window of the given interval,
+// the window is shared with the store, use LastKLines to get a copy that is safe to read concurrently.
 func (store *MarketDataStore) KLinesOfInterval(interval types.Interval) (kLines *types.KLineWindow, ok bool) {
+	store.mu.RLock()
 	kLines, ok = store.KLineWindows[interval]
+	store.mu.RUnlock()
 	return kLines, ok
 }
 
+// LastKLines returns a copy of the last n klines of the given interval
+func (store *MarketDataStore) LastKLines(interval types.Interval, n int) (types.KLineWindow, bool) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	window, ok := store.KLineWindows[interval]
+	if !ok || len(*window) == 0 {
+		return nil, false
+	}
+
+	return window.Tail(n), true
+}
+
+// LastKLine returns the last closed kline of the given interval
+func (store *MarketDataStore) LastKLine(interval types.Interval) (types.KLine, bool) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	window, ok := store.KLineWindows[interval]
+	if !ok || len(*window) == 0 {
+		return types.KLine{}, false
+	}
+
+	return window.Last(), true
+}
+
 func (store *MarketDataStore) BindStream(stream types.Stream) {
 	stream.OnKLineClosed(store.handleKLineClosed)
+	stream.OnKLine(store.handleKLine)
+}
+
+// handleKLine emits the forming kline, the closed kline is only added to the window by handleKLineClosed,
+// so that the subscribers of the window updates won't count the same bar twice.
+func (store *MarketDataStore) handleKLine(kline types.KLine) {
+	if kline.Symbol != store.Symbol || kline.Closed {
+		return
+	}
+
+	store.EmitKLineUpdate(kline)
 }
 
 func (store *MarketDataStore) handleKLineClosed(kline types.KLine) {
@@ -49,6 +140,14 @@ func (store *MarketDataStore) handleKLineClosed(kline types.KLine) {
 }
 
 func (store *MarketDataStore) AddKLine(k types.KLine) {
+	if store.repairKLines {
+		if err := k.Validate(); err != nil {
+			log.WithError(err).Warnf("%s: repairing inconsistent kline", store.Symbol)
+			k.Repair()
+		}
+	}
+
+	store.mu.Lock()
 	window, ok := store.KLineWindows[k.Interval]
 	if !ok {
 		var tmp = make(types.KLineWindow, 0, 1000)
@@ -56,11 +155,10 @@ func (store *MarketDataStore) AddKLine(k types.KLine) {
 		window = &tmp
 	}
 	window.Add(k)
-
-	if len(*window) > MaxNumOfKLines {
-		*window = (*window)[MaxNumOfKLinesTruncate-1:]
-	}
+	store.truncate(window)
+	klines := *window
+	store.mu.Unlock()
 
 	store.EmitKLineClosed(k)
-	store.EmitKLineWindowUpdate(k.Interval, *window)
+	store.EmitKLineWindowUpdate(k.Interval, klines)
 }
@@ -25,3 +25,13 @@ func (store *MarketDataStore) EmitKLineClosed(k types.KLine) {
 		cb(k)
 	}
 }
+
+func (store *MarketDataStore) OnKLineUpdate(cb func(k types.KLine)) {
+	store.kLineUpdateCallbacks = append(store.kLineUpdateCallbacks, cb)
+}
+
+func (store *MarketDataStore) EmitKLineUpdate(k types.KLine) {
+	for _, cb := range store.kLineUpdateCallbacks {
+		cb(k)
+	}
+}
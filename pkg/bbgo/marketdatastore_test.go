@@ -0,0 +1,253 @@
+package bbgo
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/types/mocks"
+)
+
+func TestMarketDataStore_AddKLine_Repair(t *testing.T) {
+	hook := test.NewGlobal()
+	defer hook.Reset()
+
+	store := NewMarketDataStore("BTCUSDT")
+	store.SetRepairKLines(true)
+
+	var closed types.KLine
+	store.OnKLineClosed(func(k types.KLine) {
+		closed = k
+	})
+
+	store.AddKLine(types.KLine{
+		Symbol:   "BTCUSDT",
+		Interval: types.Interval1m,
+		Open:     number(19000.0),
+		Close:    number(19100.0),
+		High:     number(19050.0),
+		Low:      number(19020.0),
+	})
+
+	assert.NoError(t, closed.Validate())
+	assert.Equal(t, number(19100.0), closed.High)
+	assert.Equal(t, number(19000.0), closed.Low)
+
+	window, ok := store.KLinesOfInterval(types.Interval1m)
+	if assert.True(t, ok) && assert.Len(t, *window, 1) {
+		assert.NoError(t, (*window)[0].Validate())
+	}
+
+	if entry := hook.LastEntry(); assert.NotNil(t, entry) {
+		assert.Equal(t, logrus.WarnLevel, entry.Level)
+		assert.Contains(t, entry.Message, "repairing inconsistent kline")
+	}
+}
+
+func TestMarketDataStore_OnKLineUpdate(t *testing.T) {
+	stream := types.NewStandardStream()
+	store := NewMarketDataStore("BTCUSDT")
+	store.BindStream(&stream)
+
+	var updates []types.KLine
+	store.OnKLineUpdate(func(k types.KLine) {
+		updates = append(updates, k)
+	})
+
+	var windowUpdates int
+	store.OnKLineWindowUpdate(func(interval types.Interval, klines types.KLineWindow) {
+		windowUpdates++
+	})
+
+	kline := types.KLine{
+		Symbol:   "BTCUSDT",
+		Interval: types.Interval1m,
+		Open:     number(19000.0),
+		Close:    number(19000.0),
+		High:     number(19000.0),
+		Low:      number(19000.0),
+	}
+
+	for _, price := range []float64{19010.0, 19020.0, 19005.0} {
+		kline.Close = number(price)
+		kline.High = fixedpoint.Max(kline.High, kline.Close)
+		stream.EmitKLine(kline)
+	}
+
+	// the forming bar of the other symbols are ignored
+	stream.EmitKLine(types.KLine{Symbol: "ETHUSDT", Interval: types.Interval1m})
+
+	assert.Len(t, updates, 3)
+	assert.Equal(t, number(19005.0), updates[2].Close)
+	assert.Equal(t, 0, windowUpdates)
+
+	_, ok := store.KLinesOfInterval(types.Interval1m)
+	assert.False(t, ok, "the window should not be updated by the forming bar")
+
+	kline.Closed = true
+	stream.EmitKLine(kline)
+	stream.EmitKLineClosed(kline)
+
+	assert.Len(t, updates, 3, "the closed bar should not be emitted as an update")
+	assert.Equal(t, 1, windowUpdates)
+
+	window, ok := store.KLinesOfInterval(types.Interval1m)
+	if assert.True(t, ok) && assert.Len(t, *window, 1) {
+		assert.Equal(t, number(19005.0), (*window)[0].Close)
+		assert.Equal(t, number(19020.0), (*window)[0].High)
+	}
+}
+
+func TestMarketDataStore_LastKLines(t *testing.T) {
+	store := NewMarketDataStore("BTCUSDT")
+
+	_, ok := store.LastKLine(types.Interval1m)
+	assert.False(t, ok)
+
+	startTime := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		store.AddKLine(types.KLine{
+			Symbol:    "BTCUSDT",
+			Interval:  types.Interval1m,
+			StartTime: types.Time(startTime.Add(time.Duration(i) * time.Minute)),
+			Close:     number(19000.0 + float64(i)),
+		})
+	}
+
+	for i := 0; i < 2; i++ {
+		store.AddKLine(types.KLine{
+			Symbol:    "BTCUSDT",
+			Interval:  types.Interval1h,
+			StartTime: types.Time(startTime.Add(time.Duration(i) * time.Hour)),
+			Close:     number(20000.0 + float64(i)),
+		})
+	}
+
+	if kline, ok := store.LastKLine(types.Interval1m); assert.True(t, ok) {
+		assert.Equal(t, number(19004.0), kline.Close)
+	}
+
+	if kline, ok := store.LastKLine(types.Interval1h); assert.True(t, ok) {
+		assert.Equal(t, number(20001.0), kline.Close)
+	}
+
+	if klines, ok := store.LastKLines(types.Interval1m, 3); assert.True(t, ok) && assert.Len(t, klines, 3) {
+		assert.Equal(t, number(19002.0), klines[0].Close)
+		assert.Equal(t, number(19004.0), klines[2].Close)
+
+		// the returned klines are a copy of the window
+		klines[2].Close = number(0.0)
+		kline, _ := store.LastKLine(types.Interval1m)
+		assert.Equal(t, number(19004.0), kline.Close)
+	}
+
+	if klines, ok := store.LastKLines(types.Interval1h, 10); assert.True(t, ok) {
+		assert.Len(t, klines, 2)
+	}
+
+	_, ok = store.LastKLines(types.Interval5m, 10)
+	assert.False(t, ok)
+}
+
+func TestMarketDataStore_ConcurrentAccess(t *testing.T) {
+	store := NewMarketDataStore("BTCUSDT")
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			store.AddKLine(types.KLine{Symbol: "BTCUSDT", Interval: types.Interval1m, Close: number(float64(i))})
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			store.LastKLine(types.Interval1m)
+			store.LastKLines(types.Interval1m, 10)
+		}
+	}()
+	wg.Wait()
+
+	if kline, ok := store.LastKLine(types.Interval1m); assert.True(t, ok) {
+		assert.Equal(t, number(99.0), kline.Close)
+	}
+}
+
+func TestMarketDataStore_MaxNumOfKLines(t *testing.T) {
+	store := NewMarketDataStore("BTCUSDT")
+	store.SetMaxNumOfKLines(10)
+
+	var lastWindow types.KLineWindow
+	store.OnKLineWindowUpdate(func(interval types.Interval, klines types.KLineWindow) {
+		lastWindow = klines
+	})
+
+	for i := 0; i < 25; i++ {
+		store.AddKLine(types.KLine{Symbol: "BTCUSDT", Interval: types.Interval1m, Close: number(float64(i))})
+	}
+
+	window, ok := store.KLinesOfInterval(types.Interval1m)
+	if assert.True(t, ok) && assert.Len(t, *window, 10) {
+		// the oldest klines are dropped
+		assert.Equal(t, number(15.0), window.First().Close)
+		assert.Equal(t, number(24.0), window.Last().Close)
+	}
+
+	if assert.Len(t, lastWindow, 10) {
+		assert.Equal(t, number(24.0), lastWindow.Last().Close)
+	}
+}
+
+func TestMarketDataStore_SetKLineWindows_MaxNumOfKLines(t *testing.T) {
+	store := NewMarketDataStore("BTCUSDT")
+	store.SetMaxNumOfKLines(10)
+
+	var klines types.KLineWindow
+	for i := 0; i < 25; i++ {
+		klines.Add(types.KLine{Symbol: "BTCUSDT", Interval: types.Interval1m, Close: number(float64(i))})
+	}
+
+	store.SetKLineWindows(map[types.Interval]*types.KLineWindow{
+		types.Interval1m: &klines,
+	})
+
+	window, ok := store.KLinesOfInterval(types.Interval1m)
+	if assert.True(t, ok) && assert.Len(t, *window, 10) {
+		assert.Equal(t, number(15.0), window.First().Close)
+		assert.Equal(t, number(24.0), window.Last().Close)
+	}
+}
+
+func TestExchangeSession_MarketDataStore_MaxNumOfKLines(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+
+	session := NewExchangeSession("test", mockEx)
+	session.MaxNumOfKLines = 10
+
+	store, ok := session.MarketDataStore("BTCUSDT")
+	if !assert.True(t, ok) {
+		return
+	}
+
+	for i := 0; i < 25; i++ {
+		store.AddKLine(types.KLine{Symbol: "BTCUSDT", Interval: types.Interval1m, Close: number(float64(i))})
+	}
+
+	window, ok := store.KLinesOfInterval(types.Interval1m)
+	if assert.True(t, ok) {
+		assert.Len(t, *window, 10)
+	}
+}
@@ -2,6 +2,7 @@ package bbgo
 
 import (
 	"bytes"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
@@ -52,6 +53,38 @@ type Notifiability struct {
 	SessionChannelRouter *PatternChannelRouter `json:"-"`
 	SymbolChannelRouter  *PatternChannelRouter `json:"-"`
 	ObjectChannelRouter  *ObjectChannelRouter  `json:"-"`
+
+	throttle *NotificationThrottle
+}
+
+// SetThrottle enables the notification throttle, the similar notifications within the window are coalesced
+// into one summary message, and each channel can only receive maxPerWindow notifications in the window.
+// maxPerWindow = 0 disables the rate limit.
+func (m *Notifiability) SetThrottle(window time.Duration, maxPerWindow int) {
+	if window <= 0 {
+		m.throttle = nil
+		return
+	}
+
+	m.throttle = NewNotificationThrottle(window, maxPerWindow, m.notifySummary)
+}
+
+// SetThrottleCoalesceByFormat coalesces the string notifications with the same format string,
+// see NotificationThrottle.SetCoalesceByFormat. It takes no effect if the throttle is not enabled by SetThrottle.
+func (m *Notifiability) SetThrottleCoalesceByFormat(enabled bool) {
+	if m.throttle != nil {
+		m.throttle.SetCoalesceByFormat(enabled)
+	}
+}
+
+func (m *Notifiability) notifySummary(channel, message string) {
+	for _, n := range m.notifiers {
+		if len(channel) == 0 {
+			n.Notify("%s", message)
+		} else {
+			n.NotifyTo(channel, "%s", message)
+		}
+	}
 }
 
 // RouteSymbol routes symbol name to channel
@@ -89,12 +122,20 @@ func (m *Notifiability) Notify(obj interface{}, args ...interface{}) {
 		logrus.Infof(str, simpleArgs...)
 	}
 
+	if m.throttle != nil && !m.throttle.Allow("", obj, args...) {
+		return
+	}
+
 	for _, n := range m.notifiers {
 		n.Notify(obj, args...)
 	}
 }
 
 func (m *Notifiability) NotifyTo(channel string, obj interface{}, args ...interface{}) {
+	if m.throttle != nil && !m.throttle.Allow(channel, obj, args...) {
+		return
+	}
+
 	for _, n := range m.notifiers {
 		n.NotifyTo(channel, obj, args...)
 	}
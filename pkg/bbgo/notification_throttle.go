@@ -0,0 +1,205 @@
+package bbgo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// NotificationKeyer can be implemented by the notification objects to decide which notifications are similar,
+// the notifications with the same key are coalesced by the NotificationThrottle.
+// types.Trade, types.Order, types.Position, types.Profit and types.ProfitStats are keyed by the type and the symbol.
+type NotificationKeyer interface {
+	NotificationKey() string
+}
+
+// AfterFunc schedules f to be called after the duration d, it's time.AfterFunc by default
+type AfterFunc func(d time.Duration, f func())
+
+// NotificationThrottle coalesces the similar notifications sent within the window,
+// and limits the number of notifications sent to each channel in the window.
+//
+// The first notification of a kind is sent immediately, the following similar notifications
+// in the same window are suppressed, and one summary message per channel is sent when the window ends.
+// Two notifications sent to the same channel are similar when they have the same rendered message,
+// the same NotificationKey, or the same plain text of the object.
+// The string notifications are keyed by the format string instead only if SetCoalesceByFormat is enabled.
+type NotificationThrottle struct {
+	window       time.Duration
+	maxPerWindow int
+
+	mu         sync.Mutex
+	suppressed map[string]*suppressedNotification
+	flushing   map[string]bool
+	limiters   map[string]*rate.Limiter
+
+	summary func(channel, message string)
+
+	// coalesceByFormat keys the string notifications by the format string instead of the rendered message
+	coalesceByFormat bool
+
+	// now and afterFunc are the clock used for the rate limit and the summary, they can be replaced for testing
+	now       func() time.Time
+	afterFunc AfterFunc
+}
+
+type suppressedNotification struct {
+	channel string
+	kind    string
+	count   int
+}
+
+func NewNotificationThrottle(window time.Duration, maxPerWindow int, summary func(channel, message string)) *NotificationThrottle {
+	return &NotificationThrottle{
+		window:       window,
+		maxPerWindow: maxPerWindow,
+		suppressed:   make(map[string]*suppressedNotification),
+		flushing:     make(map[string]bool),
+		limiters:     make(map[string]*rate.Limiter),
+		summary:      summary,
+		now:          time.Now,
+		afterFunc: func(d time.Duration, f func()) {
+			time.AfterFunc(d, f)
+		},
+	}
+}
+
+// SetClock replaces the clock used for the rate limit and the summary schedule
+func (t *NotificationThrottle) SetClock(now func() time.Time, afterFunc AfterFunc) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.now = now
+	t.afterFunc = afterFunc
+}
+
+// SetCoalesceByFormat keys the string notifications by the format string, so that the messages
+// rendered from the same format string with different arguments are coalesced.
+// It's disabled by default because a generic format string like "%s" would suppress the distinct messages.
+func (t *NotificationThrottle) SetCoalesceByFormat(enabled bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.coalesceByFormat = enabled
+}
+
+// Allow returns true if the notification should be sent to the channel.
+// the default channel is an empty string.
+func (t *NotificationThrottle) Allow(channel string, obj interface{}, args ...interface{}) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	kind := notificationKind(obj, args, t.coalesceByFormat)
+	key := channel + ":" + kind
+
+	if s, ok := t.suppressed[key]; ok {
+		s.count++
+		return false
+	}
+
+	s := &suppressedNotification{channel: channel, kind: kind}
+	t.suppressed[key] = s
+	t.scheduleFlush(channel)
+
+	if !t.limiter(channel).AllowN(t.now(), 1) {
+		s.count++
+		return false
+	}
+
+	return true
+}
+
+func (t *NotificationThrottle) limiter(channel string) *rate.Limiter {
+	limiter, ok := t.limiters[channel]
+	if ok {
+		return limiter
+	}
+
+	if t.maxPerWindow <= 0 {
+		limiter = rate.NewLimiter(rate.Inf, 0)
+	} else {
+		limiter = rate.NewLimiter(rate.Every(t.window/time.Duration(t.maxPerWindow)), t.maxPerWindow)
+	}
+
+	t.limiters[channel] = limiter
+	return limiter
+}
+
+// scheduleFlush schedules the summary of the channel at the end of the window, the caller should hold the lock
+func (t *NotificationThrottle) scheduleFlush(channel string) {
+	if t.flushing[channel] {
+		return
+	}
+
+	t.flushing[channel] = true
+	t.afterFunc(t.window, func() {
+		t.flush(channel)
+	})
+}
+
+// flush sends one summary of the suppressed notifications of the channel,
+// the summary is also rate limited, the counts are carried over to the next window if the channel is still flooded.
+func (t *NotificationThrottle) flush(channel string) {
+	t.mu.Lock()
+	delete(t.flushing, channel)
+
+	var total int
+	var kinds []string
+	for key, s := range t.suppressed {
+		if s.channel != channel {
+			continue
+		}
+
+		if s.count == 0 {
+			delete(t.suppressed, key)
+			continue
+		}
+
+		total += s.count
+		kinds = append(kinds, fmt.Sprintf("%s (%d)", s.kind, s.count))
+	}
+
+	if total == 0 || t.summary == nil {
+		t.mu.Unlock()
+		return
+	}
+
+	if !t.limiter(channel).AllowN(t.now(), 1) {
+		t.scheduleFlush(channel)
+		t.mu.Unlock()
+		return
+	}
+
+	for key, s := range t.suppressed {
+		if s.channel == channel {
+			delete(t.suppressed, key)
+		}
+	}
+	t.mu.Unlock()
+
+	sort.Strings(kinds)
+	t.summary(channel, fmt.Sprintf("%d similar notifications were suppressed in the last %s: %s", total, t.window, strings.Join(kinds, ", ")))
+}
+
+func notificationKind(obj interface{}, args []interface{}, byFormat bool) string {
+	switch o := obj.(type) {
+	case string:
+		if byFormat || len(args) == 0 {
+			return o
+		}
+
+		return fmt.Sprintf(o, args...)
+	case NotificationKeyer:
+		return o.NotificationKey()
+	case types.PlainText:
+		return fmt.Sprintf("%T %s", obj, o.PlainText())
+	}
+
+	return fmt.Sprintf("%T", obj)
+}
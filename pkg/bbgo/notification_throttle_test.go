@@ -0,0 +1,256 @@
+package bbgo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type recordNotifier struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (n *recordNotifier) record(channel string, obj interface{}, args ...interface{}) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if str, ok := obj.(string); ok {
+		n.messages = append(n.messages, channel+":"+fmt.Sprintf(str, args...))
+	} else {
+		n.messages = append(n.messages, channel+":"+fmt.Sprintf("%T", obj))
+	}
+}
+
+func (n *recordNotifier) Messages() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]string(nil), n.messages...)
+}
+
+func (n *recordNotifier) NotifyTo(channel string, obj interface{}, args ...interface{}) {
+	n.record(channel, obj, args...)
+}
+
+func (n *recordNotifier) Notify(obj interface{}, args ...interface{}) {
+	n.record("", obj, args...)
+}
+
+func (n *recordNotifier) SendPhotoTo(channel string, buffer *bytes.Buffer) {}
+
+func (n *recordNotifier) SendPhoto(buffer *bytes.Buffer) {}
+
+// fakeThrottleClock is a manual clock, the scheduled functions are called by Advance
+type fakeThrottleClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []func()
+}
+
+func newFakeThrottleClock() *fakeThrottleClock {
+	return &fakeThrottleClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeThrottleClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeThrottleClock) AfterFunc(d time.Duration, f func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.timers = append(c.timers, f)
+}
+
+// Advance moves the clock forward and calls the functions scheduled before
+func (c *fakeThrottleClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	timers := c.timers
+	c.timers = nil
+	c.mu.Unlock()
+
+	for _, f := range timers {
+		f()
+	}
+}
+
+func newThrottledNotifiability(window time.Duration, maxPerWindow int) (*Notifiability, *recordNotifier, *fakeThrottleClock) {
+	notifier := &recordNotifier{}
+	m := &Notifiability{}
+	m.AddNotifier(notifier)
+	m.SetThrottle(window, maxPerWindow)
+
+	clock := newFakeThrottleClock()
+	m.throttle.SetClock(clock.Now, clock.AfterFunc)
+	return m, notifier, clock
+}
+
+func TestNotifiability_Throttle(t *testing.T) {
+	t.Run("coalesce", func(t *testing.T) {
+		m, notifier, clock := newThrottledNotifiability(time.Minute, 0)
+
+		for i := 0; i < 10; i++ {
+			m.Notify(types.Trade{Symbol: "BTCUSDT", ID: uint64(i), Price: fixedpoint.NewFromInt(int64(20000 + i))})
+		}
+		m.NotifyTo("#trades", "order %d filled", 1)
+		m.NotifyTo("#trades", "order %d filled", 1)
+
+		assert.Equal(t, []string{":types.Trade", "#trades:order 1 filled"}, notifier.Messages())
+
+		clock.Advance(time.Minute)
+
+		messages := notifier.Messages()
+		assert.Len(t, messages, 4)
+		assert.Contains(t, messages, ":9 similar notifications were suppressed in the last 1m0s: trade BTCUSDT (9)")
+		assert.Contains(t, messages, "#trades:1 similar notifications were suppressed in the last 1m0s: order 1 filled (1)")
+
+		// the window is reset after the summary
+		m.Notify(types.Trade{Symbol: "BTCUSDT"})
+		assert.Len(t, notifier.Messages(), 5)
+	})
+
+	t.Run("keyed by the type and the symbol", func(t *testing.T) {
+		m, notifier, _ := newThrottledNotifiability(time.Minute, 0)
+
+		m.Notify(types.Trade{Symbol: "BTCUSDT", ID: 1})
+		m.Notify(types.Trade{Symbol: "BTCUSDT", ID: 2})
+		m.Notify(types.Trade{Symbol: "ETHUSDT", ID: 3})
+		m.Notify(types.Order{SubmitOrder: types.SubmitOrder{Symbol: "BTCUSDT"}, OrderID: 1})
+		m.Notify(types.Order{SubmitOrder: types.SubmitOrder{Symbol: "BTCUSDT"}, OrderID: 2})
+		m.Notify(&types.Position{Symbol: "BTCUSDT"})
+		m.Notify(&types.Position{Symbol: "ETHUSDT"})
+		m.Notify(&types.Profit{Symbol: "BTCUSDT", Profit: fixedpoint.One})
+		m.Notify(&types.Profit{Symbol: "BTCUSDT", Profit: fixedpoint.NewFromInt(2)})
+		m.Notify(&types.ProfitStats{Symbol: "BTCUSDT"})
+
+		assert.Equal(t, []string{
+			":types.Trade",
+			":types.Trade",
+			":types.Order",
+			":*types.Position",
+			":*types.Position",
+			":*types.Profit",
+			":*types.ProfitStats",
+		}, notifier.Messages())
+	})
+
+	t.Run("same format with different arguments", func(t *testing.T) {
+		m, notifier, _ := newThrottledNotifiability(time.Minute, 0)
+
+		m.Notify("%s", errors.New("order submit error: insufficient balance"))
+		m.Notify("%s", errors.New("stream disconnected"))
+		m.NotifyTo("#alerts", "%s: %v", "binance", errors.New("rate limit exceeded"))
+		m.NotifyTo("#alerts", "%s: %v", "max", errors.New("rate limit exceeded"))
+
+		assert.Equal(t, []string{
+			":order submit error: insufficient balance",
+			":stream disconnected",
+			"#alerts:binance: rate limit exceeded",
+			"#alerts:max: rate limit exceeded",
+		}, notifier.Messages())
+	})
+
+	t.Run("coalesce by format", func(t *testing.T) {
+		m, notifier, clock := newThrottledNotifiability(time.Minute, 0)
+		m.SetThrottleCoalesceByFormat(true)
+
+		m.NotifyTo("#trades", "order %d filled", 1)
+		m.NotifyTo("#trades", "order %d filled", 2)
+		assert.Equal(t, []string{"#trades:order 1 filled"}, notifier.Messages())
+
+		clock.Advance(time.Minute)
+		assert.Contains(t, notifier.Messages(), "#trades:1 similar notifications were suppressed in the last 1m0s: order %d filled (1)")
+	})
+
+	t.Run("rate limit", func(t *testing.T) {
+		m, notifier, _ := newThrottledNotifiability(time.Minute, 2)
+
+		for i := 0; i < 5; i++ {
+			m.NotifyTo("#alerts", fmt.Sprintf("alert %d", i))
+		}
+
+		assert.Equal(t, []string{"#alerts:alert 0", "#alerts:alert 1"}, notifier.Messages())
+
+		// other channels are not affected
+		m.NotifyTo("#errors", "error")
+		assert.Len(t, notifier.Messages(), 3)
+	})
+
+	t.Run("unrelated notifications", func(t *testing.T) {
+		m, notifier, _ := newThrottledNotifiability(time.Minute, 0)
+
+		m.Notify("order %d filled", 1)
+		m.Notify("position %s closed", "BTCUSDT")
+		m.Notify(types.Trade{Symbol: "BTCUSDT", ID: 1})
+		m.Notify(types.Trade{Symbol: "ETHUSDT", ID: 2})
+
+		assert.Len(t, notifier.Messages(), 4)
+	})
+
+	t.Run("one summary per channel", func(t *testing.T) {
+		var summaries []string
+		throttle := NewNotificationThrottle(time.Minute, 0, func(channel, message string) {
+			summaries = append(summaries, channel+":"+message)
+		})
+
+		clock := newFakeThrottleClock()
+		throttle.SetClock(clock.Now, clock.AfterFunc)
+
+		for i := 0; i < 10; i++ {
+			throttle.Allow("#alerts", fmt.Sprintf("alert %d", i))
+			throttle.Allow("#alerts", fmt.Sprintf("alert %d", i))
+		}
+
+		clock.Advance(time.Minute)
+
+		if assert.Len(t, summaries, 1) {
+			assert.Contains(t, summaries[0], "#alerts:10 similar notifications were suppressed")
+		}
+	})
+
+	t.Run("rate limited summary", func(t *testing.T) {
+		var summaries []string
+		throttle := NewNotificationThrottle(time.Minute, 1, func(channel, message string) {
+			summaries = append(summaries, channel+":"+message)
+		})
+
+		clock := newFakeThrottleClock()
+		throttle.SetClock(clock.Now, clock.AfterFunc)
+
+		assert.True(t, throttle.Allow("#alerts", "alert"))
+		assert.False(t, throttle.Allow("#alerts", "alert"))
+		assert.False(t, throttle.Allow("#alerts", "another alert"))
+
+		// the token is not refilled yet, the summary is carried over to the next window
+		clock.Advance(30 * time.Second)
+		assert.Empty(t, summaries)
+
+		// the summary takes the token of the next window
+		clock.Advance(time.Minute)
+		if assert.Len(t, summaries, 1) {
+			assert.Equal(t, "#alerts:2 similar notifications were suppressed in the last 1m0s: alert (1), another alert (1)", summaries[0])
+		}
+	})
+
+	t.Run("disabled by default", func(t *testing.T) {
+		notifier := &recordNotifier{}
+		m := &Notifiability{}
+		m.AddNotifier(notifier)
+
+		for i := 0; i < 5; i++ {
+			m.Notify(types.Trade{})
+		}
+
+		assert.Len(t, notifier.Messages(), 5)
+	})
+}
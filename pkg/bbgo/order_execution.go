@@ -311,14 +311,26 @@ type OrderCallback func(order types.Order)
 
 // BatchPlaceOrder
 func BatchPlaceOrder(ctx context.Context, exchange types.Exchange, orderCallback OrderCallback, submitOrders ...types.SubmitOrder) (types.OrderSlice, []int, error) {
-	var createdOrders types.OrderSlice
+	createdOrders, errIndexes, orderErrs := batchPlaceOrder(ctx, exchange, orderCallback, submitOrders...)
+
 	var err error
+	for _, idx := range errIndexes {
+		err = multierr.Append(err, orderErrs[idx])
+	}
+
+	return createdOrders, errIndexes, err
+}
+
+// batchPlaceOrder places the orders and returns the submit error of each failed order, keyed by the order index
+func batchPlaceOrder(ctx context.Context, exchange types.Exchange, orderCallback OrderCallback, submitOrders ...types.SubmitOrder) (types.OrderSlice, []int, map[int]error) {
+	var createdOrders types.OrderSlice
+	var orderErrs = make(map[int]error)
 
 	var errIndexes []int
 	for i, submitOrder := range submitOrders {
 		createdOrder, err2 := exchange.SubmitOrder(ctx, submitOrder)
 		if err2 != nil {
-			err = multierr.Append(err, err2)
+			orderErrs[i] = err2
 			errIndexes = append(errIndexes, i)
 		} else if createdOrder != nil {
 			createdOrder.Tag = submitOrder.Tag
@@ -331,27 +343,46 @@ func BatchPlaceOrder(ctx context.Context, exchange types.Exchange, orderCallback
 		}
 	}
 
-	return createdOrders, errIndexes, err
+	return createdOrders, errIndexes, orderErrs
 }
 
 // BatchRetryPlaceOrder places the orders and retries the failed orders
 func BatchRetryPlaceOrder(ctx context.Context, exchange types.Exchange, errIdx []int, orderCallback OrderCallback, logger log.FieldLogger, submitOrders ...types.SubmitOrder) (types.OrderSlice, []int, error) {
+	createdOrders, errIdx, _, werr := batchRetryPlaceOrder(ctx, exchange, errIdx, orderCallback, logger, submitOrders...)
+	return createdOrders, errIdx, werr
+}
+
+// submitOrderClientOrderID returns the client order id carried by the submit order error
+func submitOrderClientOrderID(err error) (string, bool) {
+	var submitOrderErr *types.SubmitOrderError
+	if err != nil && errors.As(err, &submitOrderErr) && submitOrderErr.ClientOrderID() != "" {
+		return submitOrderErr.ClientOrderID(), true
+	}
+
+	return "", false
+}
+
+// batchRetryPlaceOrder places the orders and retries the failed orders,
+// it returns the last submit error of each failed order as well, keyed by the order index
+func batchRetryPlaceOrder(ctx context.Context, exchange types.Exchange, errIdx []int, orderCallback OrderCallback, logger log.FieldLogger, submitOrders ...types.SubmitOrder) (types.OrderSlice, []int, map[int]error, error) {
 	if logger == nil {
 		logger = log.StandardLogger()
 	}
 
 	var createdOrders types.OrderSlice
 	var werr error
+	var orderErrs = make(map[int]error)
 
 	// if the errIdx is nil, then we should iterate all the submit orders
 	// allocate a variable for new error index
 	if len(errIdx) == 0 {
-		var err2 error
-		createdOrders, errIdx, err2 = BatchPlaceOrder(ctx, exchange, orderCallback, submitOrders...)
-		if err2 != nil {
-			werr = multierr.Append(werr, err2)
-		} else {
-			return createdOrders, nil, nil
+		createdOrders, errIdx, orderErrs = batchPlaceOrder(ctx, exchange, orderCallback, submitOrders...)
+		if len(errIdx) == 0 {
+			return createdOrders, nil, nil, nil
+		}
+
+		for _, idx := range errIdx {
+			werr = multierr.Append(werr, orderErrs[idx])
 		}
 	}
 
@@ -378,11 +409,21 @@ batchRetryOrder:
 		for _, idx := range errIdx {
 			submitOrder := submitOrders[idx]
 
+			// reuse the client order id of the failed submission, so that the order is not placed twice
+			// if the failed submission actually landed
+			if clientOrderID, ok := submitOrderClientOrderID(orderErrs[idx]); ok && submitOrder.ClientOrderID == "" {
+				submitOrder.ClientOrderID = clientOrderID
+			}
+
 			op := func() error {
 				// can allocate permanent error backoff.Permanent(err) to stop backoff
 				createdOrder, err2 := exchange.SubmitOrder(timeoutCtx, submitOrder)
 				if err2 != nil {
 					logger.WithError(err2).Errorf("submit order error: %s", submitOrder.String())
+
+					if clientOrderID, ok := submitOrderClientOrderID(err2); ok && submitOrder.ClientOrderID == "" {
+						submitOrder.ClientOrderID = clientOrderID
+					}
 				}
 
 				if err2 == nil && createdOrder != nil {
@@ -410,6 +451,9 @@ batchRetryOrder:
 
 				werr = multierr.Append(werr, err2)
 				errIdxNext = append(errIdxNext, idx)
+				orderErrs[idx] = err2
+			} else {
+				delete(orderErrs, idx)
 			}
 		}
 
@@ -417,5 +461,5 @@ batchRetryOrder:
 		errIdx = errIdxNext
 	}
 
-	return createdOrders, errIdx, werr
+	return createdOrders, errIdx, orderErrs, werr
 }
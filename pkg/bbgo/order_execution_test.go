@@ -0,0 +1,49 @@
+package bbgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/types/mocks"
+)
+
+func TestBatchRetryPlaceOrder_ReuseClientOrderID(t *testing.T) {
+	market := getTestMarket()
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	submitOrder := types.SubmitOrder{
+		Symbol:   "BTCUSDT",
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimit,
+		Market:   market,
+		Price:    fixedpoint.NewFromFloat(20000.0),
+		Quantity: fixedpoint.One,
+	}
+
+	retryOrder := submitOrder
+	retryOrder.ClientOrderID = "1700000000000000000"
+
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	gomock.InOrder(
+		mockEx.EXPECT().SubmitOrder(gomock.Any(), submitOrder).
+			Return(nil, types.NewSubmitOrderError(errors.New("request timeout"), "1700000000000000000")),
+		// the retry sends the client order id generated by the first submission
+		mockEx.EXPECT().SubmitOrder(gomock.Any(), retryOrder).
+			Return(&types.Order{SubmitOrder: retryOrder, OrderID: 1}, nil),
+	)
+
+	// the error of the first submission is still returned, the empty error index tells that all the orders are placed
+	createdOrders, errIdx, _ := BatchRetryPlaceOrder(context.Background(), mockEx, nil, nil, nil, submitOrder)
+	assert.Empty(t, errIdx)
+	if assert.Len(t, createdOrders, 1) {
+		assert.Equal(t, "1700000000000000000", createdOrders[0].ClientOrderID)
+	}
+}
@@ -0,0 +1,174 @@
+package bbgo
+
+import (
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// Execution is the execution quality report of a parent order,
+// a parent order is the set of orders of the same side submitted in one SubmitOrders call of the order executor.
+// A batch of both sides (e.g. the bid and ask orders of a grid) is split into one parent order per side,
+// so that the buy and sell fills are not averaged together.
+type Execution struct {
+	// ID is the sequence number of the parent order in the order executor
+	ID uint64 `json:"id"`
+
+	Symbol string         `json:"symbol"`
+	Side   types.SideType `json:"side"`
+
+	// ArrivalPrice is the mid price when the parent order is submitted
+	ArrivalPrice fixedpoint.Value `json:"arrivalPrice"`
+
+	// AveragePrice is the volume-weighted average fill price
+	AveragePrice fixedpoint.Value `json:"averagePrice"`
+
+	FilledQuantity      fixedpoint.Value `json:"filledQuantity"`
+	FilledQuoteQuantity fixedpoint.Value `json:"filledQuoteQuantity"`
+
+	StartTime time.Time `json:"startTime"`
+
+	orderIDs map[uint64]struct{}
+}
+
+// Slippage returns the slippage ratio of the average fill price against the arrival price,
+// a positive slippage means the fills are worse than the arrival price.
+func (e Execution) Slippage() fixedpoint.Value {
+	if e.ArrivalPrice.IsZero() || e.AveragePrice.IsZero() {
+		return fixedpoint.Zero
+	}
+
+	diff := e.AveragePrice.Sub(e.ArrivalPrice)
+	if e.Side == types.SideTypeSell {
+		diff = diff.Neg()
+	}
+
+	return diff.Div(e.ArrivalPrice)
+}
+
+func (e *Execution) addTrade(trade types.Trade) bool {
+	if _, ok := e.orderIDs[trade.OrderID]; !ok {
+		return false
+	}
+
+	e.FilledQuantity = e.FilledQuantity.Add(trade.Quantity)
+	e.FilledQuoteQuantity = e.FilledQuoteQuantity.Add(trade.QuoteQuantity)
+	if e.FilledQuantity.Sign() > 0 {
+		e.AveragePrice = e.FilledQuoteQuantity.Div(e.FilledQuantity)
+	}
+
+	return true
+}
+
+// arrivalPrice returns the mid price from the session order book,
+// the last price is used if the order book is not available.
+func (e *GeneralOrderExecutor) arrivalPrice() fixedpoint.Value {
+	if book, ok := e.session.OrderBook(e.symbol); ok {
+		if bid, ask, ok := book.BestBidAndAsk(); ok {
+			return bid.Price.Add(ask.Price).Div(fixedpoint.Two)
+		}
+	}
+
+	price, _ := e.session.LastPrice(e.symbol)
+	return price
+}
+
+// maxTrackedExecutions is the max number of the parent orders tracked by the order executor,
+// the oldest execution is dropped once the limit is exceeded.
+const maxTrackedExecutions = 100
+
+// startExecutions starts one execution for each side of the submit orders
+func (e *GeneralOrderExecutor) startExecutions(submitOrders []types.SubmitOrder) map[types.SideType]*Execution {
+	arrivalPrice := e.arrivalPrice()
+	now := time.Now()
+
+	e.executionMutex.Lock()
+	defer e.executionMutex.Unlock()
+
+	executions := make(map[types.SideType]*Execution, 2)
+	for _, submitOrder := range submitOrders {
+		if _, ok := executions[submitOrder.Side]; ok {
+			continue
+		}
+
+		e.executionID++
+		execution := &Execution{
+			ID:           e.executionID,
+			Symbol:       e.symbol,
+			Side:         submitOrder.Side,
+			ArrivalPrice: arrivalPrice,
+			StartTime:    now,
+			orderIDs:     make(map[uint64]struct{}),
+		}
+		executions[submitOrder.Side] = execution
+
+		e.executions = append(e.executions, execution)
+		if len(e.executions) > maxTrackedExecutions {
+			for orderID := range e.executions[0].orderIDs {
+				delete(e.executionsByOrderID, orderID)
+			}
+
+			e.executions = e.executions[1:]
+		}
+	}
+
+	return executions
+}
+
+func (e *GeneralOrderExecutor) addExecutionOrder(executions map[types.SideType]*Execution, order types.Order) {
+	execution, ok := executions[order.Side]
+	if !ok {
+		return
+	}
+
+	e.executionMutex.Lock()
+	execution.orderIDs[order.OrderID] = struct{}{}
+	e.executionsByOrderID[order.OrderID] = execution
+	e.executionMutex.Unlock()
+}
+
+func (e *GeneralOrderExecutor) updateExecution(trade types.Trade, _, _ fixedpoint.Value) {
+	e.executionMutex.Lock()
+	if execution, ok := e.executionsByOrderID[trade.OrderID]; ok {
+		execution.addTrade(trade)
+	}
+	e.executionMutex.Unlock()
+}
+
+// Execution returns the execution report of the parent order that contains the given order,
+// the order ID can be any of the orders returned by the SubmitOrders call.
+func (e *GeneralOrderExecutor) Execution(orderID uint64) (Execution, bool) {
+	e.executionMutex.Lock()
+	defer e.executionMutex.Unlock()
+
+	execution, ok := e.executionsByOrderID[orderID]
+	if !ok {
+		return Execution{}, false
+	}
+
+	report := *execution
+	report.orderIDs = nil
+	return report, true
+}
+
+// LastExecution returns the execution report of the last parent order that has fills,
+// including the volume-weighted average fill price and the arrival price.
+// The parent orders that are not filled yet are skipped, and an empty report is returned if none is filled.
+// Use Execution to get the report of a specific parent order when the orders are submitted concurrently.
+func (e *GeneralOrderExecutor) LastExecution() Execution {
+	e.executionMutex.Lock()
+	defer e.executionMutex.Unlock()
+
+	for i := len(e.executions) - 1; i >= 0; i-- {
+		if e.executions[i].FilledQuantity.IsZero() {
+			continue
+		}
+
+		execution := *e.executions[i]
+		execution.orderIDs = nil
+		return execution
+	}
+
+	return Execution{}
+}
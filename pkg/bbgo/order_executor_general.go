@@ -3,7 +3,10 @@ package bbgo
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
@@ -20,6 +23,8 @@ import (
 
 var ErrExceededSubmitOrderRetryLimit = errors.New("exceeded submit order retry limit")
 
+var ErrCloseOnly = errors.New("close-only mode, the order would increase the position")
+
 // quantityReduceDelta is used to modify the order to submit, especially for the market order
 var quantityReduceDelta = fixedpoint.NewFromFloat(0.005)
 
@@ -27,6 +32,19 @@ var quantityReduceDelta = fixedpoint.NewFromFloat(0.005)
 // This is for the maximum retries
 const submitOrderRetryLimit = 5
 
+// DryRunOrderIDOffset is the start of the order id range reserved for the simulated orders of the dry run mode,
+// the exchange order ids never reach this range, so the simulated orders can't collide with the real orders.
+const DryRunOrderIDOffset = uint64(1) << 63
+
+// dryRunOrderID is the last simulated order id, it's shared by all the executors
+// since the executors of the same session share the order store.
+var dryRunOrderID = DryRunOrderIDOffset
+
+// IsDryRunOrderID returns true if the order id is in the range of the simulated orders
+func IsDryRunOrderID(orderID uint64) bool {
+	return orderID > DryRunOrderIDOffset
+}
+
 type BaseOrderExecutor struct {
 	session           *ExchangeSession
 	activeMakerOrders *ActiveOrderBook
@@ -66,6 +84,38 @@ type GeneralOrderExecutor struct {
 
 	maxRetries    uint
 	disableNotify bool
+
+	// minProfit is the minimal expected net profit in quote currency for the take-profit close,
+	// the estimated round-trip fee is deducted from the profit before comparing.
+	minProfit fixedpoint.Value
+
+	// tradingWindow rejects the orders submitted outside the window, the orders reducing the position are not affected.
+	tradingWindow *TradingWindow
+
+	// lastKLineTime is the end time of the last closed kline, it's the clock of the trading window in back-test
+	lastKLineTime time.Time
+
+	// closeOnly rejects the orders which would increase the absolute position size
+	closeOnly bool
+
+	// dryRun validates and logs the orders without sending them to the exchange,
+	// the orders are recorded in the active order book with the simulated status
+	dryRun bool
+
+	// trimQuantityOnInsufficientBalance trims the quantity of the orders rejected by the insufficient balance error
+	// down to the available balance and re-submits them once
+	trimQuantityOnInsufficientBalance bool
+
+	// executions tracks the fills of the recent parent orders for the execution quality report,
+	// executionsByOrderID maps the order ID to its parent order
+	executions          []*Execution
+	executionsByOrderID map[uint64]*Execution
+	executionID         uint64
+	executionMutex      sync.Mutex
+
+	// spotBaseOffset is the base balance which is not managed by the position, it's recorded by the first position check
+	spotBaseOffset         *fixedpoint.Value
+	positionDriftCallbacks []func(drift PositionDrift)
 }
 
 // NewGeneralOrderExecutor allocates a GeneralOrderExecutor
@@ -93,8 +143,12 @@ func NewGeneralOrderExecutor(
 		strategyInstanceID: strategyInstanceID,
 		position:           position,
 		tradeCollector:     core.NewTradeCollector(symbol, position, orderStore),
+
+		executionsByOrderID: make(map[uint64]*Execution),
 	}
 
+	executor.tradeCollector.OnTrade(executor.updateExecution)
+
 	if session != nil && session.Margin {
 		executor.startMarginAssetUpdater(context.Background())
 	}
@@ -110,6 +164,51 @@ func (e *GeneralOrderExecutor) SetMaxRetries(maxRetries uint) {
 	e.maxRetries = maxRetries
 }
 
+// SetMinProfit sets the minimal expected net profit (in quote currency) for closing a profitable position.
+// Closing a position at a loss (stop loss) is not affected.
+// It's not synchronized with ClosePosition, so it must be called before Bind.
+func (e *GeneralOrderExecutor) SetMinProfit(minProfit fixedpoint.Value) {
+	e.minProfit = minProfit
+}
+
+// estimateCloseProfit estimates the gross profit and the net profit of closing the given quantity at the given price,
+// the net profit deducts the entry fee and the exit fee with the taker fee rate of the session.
+func (e *GeneralOrderExecutor) estimateCloseProfit(price, quantity fixedpoint.Value) (profit, netProfit fixedpoint.Value) {
+	base, averageCost := e.position.GetBaseAndAverageCost()
+	if base.Sign() > 0 {
+		profit = price.Sub(averageCost).Mul(quantity)
+	} else if base.Sign() < 0 {
+		profit = averageCost.Sub(price).Mul(quantity)
+	}
+
+	fee := averageCost.Add(price).Mul(quantity).Mul(e.session.TakerFeeRate)
+	return profit, profit.Sub(fee)
+}
+
+// checkMinProfit returns ErrMinProfitNotReached if closing the quantity at the last price makes a profit
+// but the estimated net profit is less than the minimal profit.
+func (e *GeneralOrderExecutor) checkMinProfit(quantity fixedpoint.Value) error {
+	if e.minProfit.IsZero() {
+		return nil
+	}
+
+	price, ok := e.session.LastPrice(e.symbol)
+	if !ok || price.IsZero() {
+		return nil
+	}
+
+	profit, netProfit := e.estimateCloseProfit(price, quantity)
+	if profit.Sign() <= 0 {
+		return nil
+	}
+
+	if netProfit.Compare(e.minProfit) < 0 {
+		return errors.Wrapf(ErrMinProfitNotReached, "estimated net profit %s is less than the min profit %s", netProfit.String(), e.minProfit.String())
+	}
+
+	return nil
+}
+
 func (e *GeneralOrderExecutor) startMarginAssetUpdater(ctx context.Context) {
 	marginService, ok := e.session.Exchange.(types.MarginBorrowRepayService)
 	if !ok {
@@ -117,7 +216,9 @@ func (e *GeneralOrderExecutor) startMarginAssetUpdater(ctx context.Context) {
 		return
 	}
 
-	go e.marginAssetMaxBorrowableUpdater(ctx, 30*time.Minute, marginService, e.position.Market)
+	types.GoWithContext(ctx, func() {
+		e.marginAssetMaxBorrowableUpdater(ctx, 30*time.Minute, marginService, e.position.Market)
+	})
 }
 
 func (e *GeneralOrderExecutor) updateMarginAssetMaxBorrowable(
@@ -210,6 +311,11 @@ func (e *GeneralOrderExecutor) Bind() {
 
 // CancelOrders cancels the given order objects directly
 func (e *GeneralOrderExecutor) CancelOrders(ctx context.Context, orders ...types.Order) error {
+	if e.dryRun {
+		e.cancelSimulatedOrders(orders...)
+		return nil
+	}
+
 	err := e.session.Exchange.CancelOrders(ctx, orders...)
 	if err != nil { // Retry once
 		err = e.session.Exchange.CancelOrders(ctx, orders...)
@@ -221,27 +327,344 @@ func (e *GeneralOrderExecutor) SetLogger(logger log.FieldLogger) {
 	e.logger = logger
 }
 
+func (e *GeneralOrderExecutor) fieldLogger() log.FieldLogger {
+	if e.logger != nil {
+		return e.logger
+	}
+
+	return log.WithField("symbol", e.symbol)
+}
+
+// SetTradingWindow sets the trading window, the orders submitted outside the window will be rejected
+// with ErrOutsideTradingWindow, except the orders submitted by ClosePosition and the orders reducing the position,
+// e.g., the reduce-only orders and the protective stop orders.
+func (e *GeneralOrderExecutor) SetTradingWindow(window *TradingWindow) {
+	if e.tradingWindow == nil && window != nil && IsBackTesting && e.session != nil {
+		// the wall clock is meaningless in back-test, use the kline time instead
+		e.session.MarketDataStream.OnKLineClosed(func(kline types.KLine) {
+			if kline.Symbol == e.symbol {
+				e.lastKLineTime = kline.EndTime.Time()
+			}
+		})
+	}
+
+	e.tradingWindow = window
+}
+
+// tradingWindowTime returns the current time for the trading window check
+func (e *GeneralOrderExecutor) tradingWindowTime() time.Time {
+	if IsBackTesting && !e.lastKLineTime.IsZero() {
+		return e.lastKLineTime
+	}
+
+	return time.Now()
+}
+
+// isReducingOrders returns true if all the orders are reduce-only orders, or the orders only reduce the position
+// without flipping its side, the orders are accumulated on the current position base in order.
+func (e *GeneralOrderExecutor) isReducingOrders(submitOrders []types.SubmitOrder) bool {
+	base := e.position.GetBase()
+	for _, submitOrder := range submitOrders {
+		if submitOrder.ReduceOnly || submitOrder.ClosePosition {
+			continue
+		}
+
+		var newBase fixedpoint.Value
+		switch submitOrder.Side {
+		case types.SideTypeBuy:
+			newBase = base.Add(submitOrder.Quantity)
+		case types.SideTypeSell:
+			newBase = base.Sub(submitOrder.Quantity)
+		default:
+			return false
+		}
+
+		if newBase.Abs().Compare(base.Abs()) >= 0 || newBase.Sign()*base.Sign() < 0 {
+			return false
+		}
+
+		base = newBase
+	}
+
+	return true
+}
+
+// SetCloseOnly enables or disables the close-only mode,
+// in the close-only mode, only the orders that reduce the position are allowed.
+func (e *GeneralOrderExecutor) SetCloseOnly(closeOnly bool) {
+	e.closeOnly = closeOnly
+}
+
+func (e *GeneralOrderExecutor) IsCloseOnly() bool {
+	return e.closeOnly
+}
+
+// SetDryRun enables or disables the dry run mode,
+// in the dry run mode, the orders are validated and logged but not sent to the exchange.
+func (e *GeneralOrderExecutor) SetDryRun(dryRun bool) {
+	e.dryRun = dryRun
+}
+
+func (e *GeneralOrderExecutor) IsDryRun() bool {
+	return e.dryRun
+}
+
+// SetTrimQuantityOnInsufficientBalance enables or disables trimming the order quantity to the available balance
+// when the exchange rejects the order with the insufficient balance error, e.g., the fee is deducted from the balance.
+// The trimmed orders are re-submitted once, and the order is dropped if the trimmed quantity is less than the minimum.
+func (e *GeneralOrderExecutor) SetTrimQuantityOnInsufficientBalance(enabled bool) {
+	e.trimQuantityOnInsufficientBalance = enabled
+}
+
+// insufficientBalanceErrorPattern matches the insufficient balance error messages of the exchanges, e.g.,
+// "Account has insufficient balance for requested action." and "Balance insufficient!"
+var insufficientBalanceErrorPattern = regexp.MustCompile(`(?i)insufficient.*(balance|fund)|balance.*(insufficient|not enough)`)
+
+func isInsufficientBalanceError(err error) bool {
+	for _, err2 := range multierr.Errors(err) {
+		if insufficientBalanceErrorPattern.MatchString(err2.Error()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// trimOrderQuantity trims the order quantity down to the given available balances of the spot account,
+// the trimmed quantity is truncated by the market step size, and the used balance is deducted from the balances.
+func (e *GeneralOrderExecutor) trimOrderQuantity(order types.SubmitOrder, balances types.BalanceMap) (types.SubmitOrder, error) {
+	market := order.Market
+	price := order.Price
+	if order.Type == types.OrderTypeMarket || order.Type == types.OrderTypeStopMarket || price.IsZero() {
+		if lastPrice, ok := e.session.LastPrice(order.Symbol); ok {
+			price = lastPrice
+		}
+	}
+
+	if price.Sign() <= 0 {
+		return order, fmt.Errorf("can not trim the order quantity without the price, order: %s", order.String())
+	}
+
+	var currency string
+	switch order.Side {
+	case types.SideTypeBuy:
+		currency = market.QuoteCurrency
+	case types.SideTypeSell:
+		currency = market.BaseCurrency
+	default:
+		return order, fmt.Errorf("unexpected order side: %s", order.Side)
+	}
+
+	balance, ok := balances[currency]
+	if !ok {
+		return order, fmt.Errorf("%s balance not found", currency)
+	}
+
+	available := balance.Available
+	if order.Side == types.SideTypeBuy {
+		available = available.Div(price)
+	}
+
+	quantity := fixedpoint.Min(order.Quantity, available)
+	if market.StepSize.Sign() > 0 {
+		quantity = market.TruncateQuantity(quantity)
+	}
+
+	if quantity.Compare(order.Quantity) >= 0 {
+		return order, fmt.Errorf("available balance covers the order quantity %s, nothing to trim", order.Quantity.String())
+	}
+
+	if market.IsDustQuantity(quantity, price) {
+		return order, types.NewZeroAssetError(
+			fmt.Errorf("trimmed quantity %s is less than the min quantity %s or the min notional %s",
+				quantity.String(), market.MinQuantity.String(), market.MinNotional.String()))
+	}
+
+	// deduct the used balance, so that the next trimmed order does not count it again
+	if order.Side == types.SideTypeBuy {
+		balance.Available = balance.Available.Sub(quantity.Mul(price))
+	} else {
+		balance.Available = balance.Available.Sub(quantity)
+	}
+	balances[currency] = balance
+
+	e.fieldLogger().Warnf("insufficient balance, trimming order quantity: %s -> %s", order.Quantity.String(), quantity.String())
+	order.Quantity = quantity
+	return order, nil
+}
+
+// submitTrimmedOrders re-queries the account balances, trims the quantity of the failed orders and re-submits them once,
+// the error of the order that can not be trimmed is returned with the original submit error.
+func (e *GeneralOrderExecutor) submitTrimmedOrders(
+	ctx context.Context, orderCallback OrderCallback, submitOrders []types.SubmitOrder, errIdx []int, submitErr error,
+) (types.OrderSlice, error) {
+	if e.session.Futures || e.session.Margin {
+		return nil, multierr.Append(submitErr, fmt.Errorf("trimming order quantity is only supported by the spot session"))
+	}
+
+	// the cached account balances can be stale when the exchange rejects the order, query the latest balances
+	balances, err := e.session.Exchange.QueryAccountBalances(ctx)
+	if err != nil {
+		return nil, multierr.Append(submitErr, fmt.Errorf("unable to query the account balances for trimming: %w", err))
+	}
+
+	e.session.GetAccount().UpdateBalances(balances)
+
+	var trimmedOrders []types.SubmitOrder
+	for _, idx := range errIdx {
+		trimmedOrder, err2 := e.trimOrderQuantity(submitOrders[idx], balances)
+		if err2 != nil {
+			err = multierr.Append(err, err2)
+			continue
+		}
+
+		trimmedOrders = append(trimmedOrders, trimmedOrder)
+	}
+
+	if err != nil {
+		err = multierr.Append(submitErr, err)
+	}
+
+	if len(trimmedOrders) == 0 {
+		return nil, err
+	}
+
+	createdOrders, _, err2 := BatchPlaceOrder(ctx, e.session.Exchange, orderCallback, trimmedOrders...)
+	return createdOrders, multierr.Append(err, err2)
+}
+
+// validateDryRunOrder checks the order with the market constraints, since there is no exchange to reject it
+func (e *GeneralOrderExecutor) validateDryRunOrder(order types.SubmitOrder) error {
+	if order.Quantity.Sign() <= 0 {
+		return fmt.Errorf("invalid order quantity %s", order.Quantity.String())
+	}
+
+	price := order.Price
+	switch order.Type {
+	case types.OrderTypeMarket, types.OrderTypeStopMarket:
+		if lastPrice, ok := e.session.LastPrice(order.Symbol); ok {
+			price = lastPrice
+		}
+
+	default:
+		if price.Sign() <= 0 {
+			return fmt.Errorf("invalid %s order price %s", order.Type, price.String())
+		}
+	}
+
+	if price.Sign() > 0 && order.Market.IsDustQuantity(order.Quantity, price) {
+		return fmt.Errorf("order quantity %s at price %s is less than the min quantity %s or the min notional %s",
+			order.Quantity.String(), price.String(), order.Market.MinQuantity.String(), order.Market.MinNotional.String())
+	}
+
+	return nil
+}
+
+// simulateOrders creates the simulated orders of the dry run mode and records them in the order store and the active order book
+func (e *GeneralOrderExecutor) simulateOrders(submitOrders []types.SubmitOrder) (types.OrderSlice, error) {
+	for _, submitOrder := range submitOrders {
+		if err := e.validateDryRunOrder(submitOrder); err != nil {
+			return nil, fmt.Errorf("dry run order validation error: %w, order: %s", err, submitOrder.String())
+		}
+	}
+
+	var createdOrders types.OrderSlice
+	for _, submitOrder := range submitOrders {
+		order := types.Order{
+			SubmitOrder:  submitOrder,
+			Exchange:     e.session.ExchangeName,
+			OrderID:      atomic.AddUint64(&dryRunOrderID, 1),
+			Status:       types.OrderStatusSimulated,
+			CreationTime: types.Time(time.Now()),
+			UpdateTime:   types.Time(time.Now()),
+		}
+
+		e.fieldLogger().Infof("[DryRun] simulated order: %s", order.String())
+		e.orderStore.Add(order)
+		e.activeMakerOrders.Add(order)
+		createdOrders = append(createdOrders, order)
+	}
+
+	return createdOrders, nil
+}
+
+// checkCloseOnly returns ErrCloseOnly unless the given orders only reduce the position,
+// it shares the check of the trading window, so the orders that flip the position are rejected as well.
+func (e *GeneralOrderExecutor) checkCloseOnly(submitOrders []types.SubmitOrder) error {
+	if e.isReducingOrders(submitOrders) {
+		return nil
+	}
+
+	e.fieldLogger().Warnf("close-only mode: rejected %s orders %v, position base %s",
+		e.symbol, submitOrders, e.position.GetBase().String())
+	return ErrCloseOnly
+}
+
 func (e *GeneralOrderExecutor) SubmitOrders(
 	ctx context.Context, submitOrders ...types.SubmitOrder,
 ) (types.OrderSlice, error) {
+	if e.tradingWindow != nil && !e.position.IsClosing() && !e.tradingWindow.IsOpen(e.tradingWindowTime()) &&
+		!e.isReducingOrders(submitOrders) {
+		return nil, ErrOutsideTradingWindow
+	}
+
+	if e.closeOnly {
+		if err := e.checkCloseOnly(submitOrders); err != nil {
+			return nil, err
+		}
+	}
+
 	formattedOrders, err := e.session.FormatOrders(submitOrders)
 	if err != nil {
 		return nil, err
 	}
 
+	if e.dryRun {
+		return e.simulateOrders(formattedOrders)
+	}
+
+	executions := e.startExecutions(formattedOrders)
+
 	orderCreateCallback := func(createdOrder types.Order) {
 		e.orderStore.Add(createdOrder)
 		e.activeMakerOrders.Add(createdOrder)
+		e.addExecutionOrder(executions, createdOrder)
 	}
 
 	defer e.tradeCollector.Process()
 
+	var createdOrders types.OrderSlice
+	var errIdx []int
+	var orderErrs map[int]error
 	if e.maxRetries == 0 {
-		createdOrders, _, err := BatchPlaceOrder(ctx, e.session.Exchange, orderCreateCallback, formattedOrders...)
-		return createdOrders, err
+		createdOrders, errIdx, orderErrs = batchPlaceOrder(ctx, e.session.Exchange, orderCreateCallback, formattedOrders...)
+		for _, idx := range errIdx {
+			err = multierr.Append(err, orderErrs[idx])
+		}
+	} else {
+		createdOrders, errIdx, orderErrs, err = batchRetryPlaceOrder(ctx, e.session.Exchange, nil, orderCreateCallback, e.logger, formattedOrders...)
+	}
+
+	if err != nil && len(errIdx) > 0 && e.trimQuantityOnInsufficientBalance {
+		// only the orders rejected by the insufficient balance error are trimmed,
+		// the errors of the other orders are returned as they are
+		var insufficientIdx []int
+		var insufficientErr, otherErr error
+		for _, idx := range errIdx {
+			if isInsufficientBalanceError(orderErrs[idx]) {
+				insufficientIdx = append(insufficientIdx, idx)
+				insufficientErr = multierr.Append(insufficientErr, orderErrs[idx])
+			} else {
+				otherErr = multierr.Append(otherErr, orderErrs[idx])
+			}
+		}
+
+		if len(insufficientIdx) > 0 {
+			trimmedOrders, err2 := e.submitTrimmedOrders(ctx, orderCreateCallback, formattedOrders, insufficientIdx, insufficientErr)
+			return append(createdOrders, trimmedOrders...), multierr.Append(otherErr, err2)
+		}
 	}
 
-	createdOrders, _, err := BatchRetryPlaceOrder(ctx, e.session.Exchange, nil, orderCreateCallback, e.logger, formattedOrders...)
 	return createdOrders, err
 }
 
@@ -472,6 +895,15 @@ func (e *GeneralOrderExecutor) GracefulCancelActiveOrderBook(ctx context.Context
 
 // GracefulCancel cancels all active maker orders if orders are not given, otherwise cancel all the given orders
 func (e *GeneralOrderExecutor) GracefulCancel(ctx context.Context, orders ...types.Order) error {
+	if e.dryRun {
+		if len(orders) == 0 {
+			orders = e.activeMakerOrders.Orders()
+		}
+
+		e.cancelSimulatedOrders(orders...)
+		return nil
+	}
+
 	if err := e.activeMakerOrders.GracefulCancel(ctx, e.session.Exchange, orders...); err != nil {
 		return errors.Wrap(err, "graceful cancel error")
 	}
@@ -479,8 +911,19 @@ func (e *GeneralOrderExecutor) GracefulCancel(ctx context.Context, orders ...typ
 	return nil
 }
 
+// cancelSimulatedOrders removes the simulated orders from the active order book without calling the exchange
+func (e *GeneralOrderExecutor) cancelSimulatedOrders(orders ...types.Order) {
+	for _, order := range orders {
+		if e.activeMakerOrders.Remove(order) {
+			e.fieldLogger().Infof("[DryRun] simulated order canceled: %s", order.String())
+		}
+	}
+}
+
 var ErrPositionAlreadyClosing = errors.New("position is already in closing process")
 
+var ErrMinProfitNotReached = errors.New("min profit not reached")
+
 // ClosePosition closes the current position by a percentage.
 // percentage 0.1 means close 10% position
 // tag is the order tag you want to attach, you may pass multiple tags, the tags will be combined into one tag string by commas.
@@ -495,6 +938,10 @@ func (e *GeneralOrderExecutor) ClosePosition(ctx context.Context, percentage fix
 		return nil
 	}
 
+	if err := e.checkMinProfit(submitOrder.Quantity); err != nil {
+		return err
+	}
+
 	if e.session.Futures { // Futures: Use base qty in e.position
 		submitOrder.Quantity = e.position.GetBase().Abs()
 		submitOrder.ReduceOnly = true
@@ -0,0 +1,771 @@
+package bbgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/types/mocks"
+)
+
+func TestGeneralOrderExecutor_ClosePosition_MinProfit(t *testing.T) {
+	market := getTestMarket()
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+
+	session := NewExchangeSession("test", mockEx)
+	session.markets[market.Symbol] = market
+	session.TakerFeeRate = fixedpoint.NewFromFloat(0.001)
+	session.Account.UpdateBalances(types.BalanceMap{
+		"BTC": {Currency: "BTC", Available: fixedpoint.One},
+	})
+
+	position := types.NewPositionFromMarket(market)
+	position.AverageCost = fixedpoint.NewFromFloat(20000.0)
+	position.Base = fixedpoint.One
+
+	orderExecutor := NewGeneralOrderExecutor(session, "BTCUSDT", "test", "test-01", position)
+	orderExecutor.SetMinProfit(fixedpoint.NewFromFloat(10.0))
+
+	ctx := context.Background()
+
+	// gross profit = 30, estimated fee = (20000 + 20030) * 0.001 = 40.03
+	session.lastPrices[market.Symbol] = fixedpoint.NewFromFloat(20030.0)
+	err := orderExecutor.ClosePosition(ctx, fixedpoint.One)
+	assert.True(t, errors.Is(err, ErrMinProfitNotReached), "tiny profit should be suppressed")
+
+	// gross profit = 100, estimated fee = (20000 + 20100) * 0.001 = 40.1
+	mockEx.EXPECT().SubmitOrder(gomock.Any(), types.SubmitOrder{
+		Symbol:           "BTCUSDT",
+		Side:             types.SideTypeSell,
+		Type:             types.OrderTypeMarket,
+		Market:           market,
+		Quantity:         fixedpoint.One,
+		MarginSideEffect: types.SideEffectTypeAutoRepay,
+	}).Return(&types.Order{}, nil)
+
+	session.lastPrices[market.Symbol] = fixedpoint.NewFromFloat(20100.0)
+	err = orderExecutor.ClosePosition(ctx, fixedpoint.One)
+	assert.NoError(t, err)
+}
+
+func TestGeneralOrderExecutor_ClosePosition_MinProfitStopLoss(t *testing.T) {
+	market := getTestMarket()
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+	mockEx.EXPECT().SubmitOrder(gomock.Any(), gomock.Any()).Return(&types.Order{}, nil)
+
+	session := NewExchangeSession("test", mockEx)
+	session.markets[market.Symbol] = market
+	session.TakerFeeRate = fixedpoint.NewFromFloat(0.001)
+	session.Account.UpdateBalances(types.BalanceMap{
+		"BTC": {Currency: "BTC", Available: fixedpoint.One},
+	})
+
+	position := types.NewPositionFromMarket(market)
+	position.AverageCost = fixedpoint.NewFromFloat(20000.0)
+	position.Base = fixedpoint.One
+
+	orderExecutor := NewGeneralOrderExecutor(session, "BTCUSDT", "test", "test-01", position)
+	orderExecutor.SetMinProfit(fixedpoint.NewFromFloat(10.0))
+
+	// closing at a loss should never be suppressed
+	session.lastPrices[market.Symbol] = fixedpoint.NewFromFloat(19000.0)
+	err := orderExecutor.ClosePosition(context.Background(), fixedpoint.One)
+	assert.NoError(t, err)
+}
+
+func TestGeneralOrderExecutor_LastExecution(t *testing.T) {
+	market := getTestMarket()
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+	mockEx.EXPECT().SubmitOrder(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, order types.SubmitOrder) (*types.Order, error) {
+			return &types.Order{SubmitOrder: order, OrderID: 1, Status: types.OrderStatusNew}, nil
+		})
+
+	session := NewExchangeSession("test", mockEx)
+	session.markets[market.Symbol] = market
+	session.lastPrices[market.Symbol] = fixedpoint.NewFromFloat(20000.0)
+
+	position := types.NewPositionFromMarket(market)
+	orderExecutor := NewGeneralOrderExecutor(session, "BTCUSDT", "test", "test-01", position)
+
+	_, err := orderExecutor.SubmitOrders(context.Background(), types.SubmitOrder{
+		Symbol:   "BTCUSDT",
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeMarket,
+		Market:   market,
+		Quantity: fixedpoint.One,
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	fills := []struct {
+		price, quantity float64
+	}{
+		{20010.0, 0.3},
+		{20020.0, 0.5},
+		{20050.0, 0.2},
+	}
+	for i, fill := range fills {
+		price := fixedpoint.NewFromFloat(fill.price)
+		quantity := fixedpoint.NewFromFloat(fill.quantity)
+		orderExecutor.TradeCollector().ProcessTrade(types.Trade{
+			ID:            uint64(i + 1),
+			OrderID:       1,
+			Exchange:      types.ExchangeBinance,
+			Symbol:        "BTCUSDT",
+			Side:          types.SideTypeBuy,
+			IsBuyer:       true,
+			Price:         price,
+			Quantity:      quantity,
+			QuoteQuantity: price.Mul(quantity),
+		})
+	}
+
+	// trades of other orders are not counted
+	orderExecutor.TradeCollector().ProcessTrade(types.Trade{
+		ID:            100,
+		OrderID:       2,
+		Exchange:      types.ExchangeBinance,
+		Symbol:        "BTCUSDT",
+		Side:          types.SideTypeBuy,
+		Price:         fixedpoint.NewFromFloat(30000.0),
+		Quantity:      fixedpoint.One,
+		QuoteQuantity: fixedpoint.NewFromFloat(30000.0),
+	})
+
+	execution := orderExecutor.LastExecution()
+	assert.Equal(t, types.SideTypeBuy, execution.Side)
+	assert.Equal(t, "20000", execution.ArrivalPrice.String())
+	assert.Equal(t, "1", execution.FilledQuantity.String())
+	// (20010 * 0.3 + 20020 * 0.5 + 20050 * 0.2) / 1.0
+	assert.Equal(t, "20023", execution.AveragePrice.String())
+	assert.InDelta(t, 0.00115, execution.Slippage().Float64(), 1e-8)
+}
+
+func TestGeneralOrderExecutor_CheckPosition_SpotDrift(t *testing.T) {
+	hook := test.NewGlobal()
+	defer hook.Reset()
+
+	market := getTestMarket()
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+
+	// the account holds 10 BTC which are not managed by the strategy
+	gomock.InOrder(
+		mockEx.EXPECT().QueryAccountBalances(gomock.Any()).Return(types.BalanceMap{
+			"BTC": {Currency: "BTC", Available: fixedpoint.NewFromFloat(10.5)},
+		}, nil),
+		mockEx.EXPECT().QueryAccountBalances(gomock.Any()).Return(types.BalanceMap{
+			"BTC": {Currency: "BTC", Available: fixedpoint.NewFromFloat(10.3), Locked: fixedpoint.NewFromFloat(0.2)},
+		}, nil),
+		// a sell trade of 0.2 BTC is missed
+		mockEx.EXPECT().QueryAccountBalances(gomock.Any()).Return(types.BalanceMap{
+			"BTC": {Currency: "BTC", Available: fixedpoint.NewFromFloat(10.3)},
+		}, nil),
+	)
+
+	session := NewExchangeSession("test", mockEx)
+	position := types.NewPositionFromMarket(market)
+	position.Base = fixedpoint.NewFromFloat(0.5)
+	position.AverageCost = fixedpoint.NewFromFloat(20000.0)
+
+	executor := NewGeneralOrderExecutor(session, market.Symbol, "test", "test-01", position)
+	executor.DisableNotify()
+
+	var drifts []PositionDrift
+	executor.OnPositionDrift(func(drift PositionDrift) {
+		drifts = append(drifts, drift)
+	})
+
+	options := PositionCheckOptions{Tolerance: fixedpoint.NewFromFloat(0.01), Resync: true}
+
+	// the first check records the base offset and reports it
+	drift, err := executor.CheckPosition(context.Background(), options)
+	assert.NoError(t, err)
+	assert.Nil(t, drift)
+	if entry := hook.LastEntry(); assert.NotNil(t, entry) {
+		assert.Contains(t, entry.Message, "the difference 10 is treated as the base asset not managed by the strategy")
+	}
+
+	// the locked balance is a part of the position
+	drift, err = executor.CheckPosition(context.Background(), options)
+	assert.NoError(t, err)
+	assert.Nil(t, drift)
+
+	drift, err = executor.CheckPosition(context.Background(), options)
+	assert.NoError(t, err)
+	if assert.NotNil(t, drift) && assert.Len(t, drifts, 1) {
+		assert.Equal(t, "0.5", drift.Expected.String())
+		assert.Equal(t, "0.3", drift.Actual.String())
+		assert.Equal(t, "-0.2", drift.Drift.String())
+		assert.True(t, drift.Resynced)
+		assert.Equal(t, *drift, drifts[0])
+	}
+
+	assert.Equal(t, "0.3", position.GetBase().String())
+}
+
+type testPositionExchange struct {
+	*mocks.MockExchange
+
+	positions []types.Position
+}
+
+func (e *testPositionExchange) QueryPositions(ctx context.Context, symbols ...string) ([]types.Position, error) {
+	return e.positions, nil
+}
+
+func TestGeneralOrderExecutor_CheckPosition_FuturesDrift(t *testing.T) {
+	market := getTestMarket()
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+
+	ex := &testPositionExchange{
+		MockExchange: mockEx,
+		positions: []types.Position{
+			{Symbol: "ETHUSDT", Base: fixedpoint.NewFromFloat(3.0)},
+			{Symbol: "BTCUSDT", Base: fixedpoint.NewFromFloat(-1.0)},
+		},
+	}
+
+	session := NewExchangeSession("test", ex)
+	session.Futures = true
+
+	position := types.NewPositionFromMarket(market)
+	position.Base = fixedpoint.NewFromFloat(-1.2)
+
+	executor := NewGeneralOrderExecutor(session, market.Symbol, "test", "test-01", position)
+	executor.DisableNotify()
+
+	fired := false
+	executor.OnPositionDrift(func(drift PositionDrift) {
+		fired = true
+	})
+
+	drift, err := executor.CheckPosition(context.Background(), PositionCheckOptions{Tolerance: fixedpoint.NewFromFloat(0.5)})
+	assert.NoError(t, err)
+	assert.Nil(t, drift, "the drift is within the tolerance")
+	assert.False(t, fired)
+
+	drift, err = executor.CheckPosition(context.Background(), PositionCheckOptions{Tolerance: fixedpoint.NewFromFloat(0.1)})
+	assert.NoError(t, err)
+	if assert.NotNil(t, drift) {
+		assert.Equal(t, "0.2", drift.Drift.String())
+		assert.False(t, drift.Resynced)
+	}
+
+	assert.True(t, fired)
+	assert.Equal(t, "-1.2", position.GetBase().String(), "the position should not be modified without resync")
+}
+
+func TestGeneralOrderExecutor_CloseOnly(t *testing.T) {
+	market := getTestMarket()
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	newExecutor := func(base fixedpoint.Value) (*GeneralOrderExecutor, *mocks.MockExchange) {
+		mockEx := mocks.NewMockExchange(mockCtrl)
+		mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+
+		session := NewExchangeSession("test", mockEx)
+		session.markets[market.Symbol] = market
+
+		position := types.NewPositionFromMarket(market)
+		position.AverageCost = fixedpoint.NewFromFloat(20000.0)
+		position.Base = base
+
+		orderExecutor := NewGeneralOrderExecutor(session, "BTCUSDT", "test", "test-01", position)
+		orderExecutor.SetCloseOnly(true)
+		return orderExecutor, mockEx
+	}
+
+	newOrder := func(side types.SideType, quantity fixedpoint.Value) types.SubmitOrder {
+		return types.SubmitOrder{
+			Symbol:   "BTCUSDT",
+			Side:     side,
+			Type:     types.OrderTypeLimit,
+			Market:   market,
+			Price:    fixedpoint.NewFromFloat(20000.0),
+			Quantity: quantity,
+		}
+	}
+
+	ctx := context.Background()
+
+	t.Run("long position", func(t *testing.T) {
+		orderExecutor, mockEx := newExecutor(fixedpoint.One)
+
+		_, err := orderExecutor.SubmitOrders(ctx, newOrder(types.SideTypeBuy, fixedpoint.One))
+		assert.True(t, errors.Is(err, ErrCloseOnly))
+
+		// selling more than twice of the position flips it to a larger short position
+		_, err = orderExecutor.SubmitOrders(ctx, newOrder(types.SideTypeSell, fixedpoint.NewFromFloat(3.0)))
+		assert.True(t, errors.Is(err, ErrCloseOnly))
+
+		// selling 1.5 flips it to a smaller short position, which is not a close either
+		_, err = orderExecutor.SubmitOrders(ctx, newOrder(types.SideTypeSell, fixedpoint.NewFromFloat(1.5)))
+		assert.True(t, errors.Is(err, ErrCloseOnly))
+
+		sellOrder := newOrder(types.SideTypeSell, fixedpoint.One)
+		mockEx.EXPECT().SubmitOrder(gomock.Any(), sellOrder).Return(&types.Order{SubmitOrder: sellOrder}, nil)
+		_, err = orderExecutor.SubmitOrders(ctx, sellOrder)
+		assert.NoError(t, err)
+	})
+
+	t.Run("short position", func(t *testing.T) {
+		orderExecutor, mockEx := newExecutor(fixedpoint.NewFromFloat(-1.0))
+
+		_, err := orderExecutor.SubmitOrders(ctx, newOrder(types.SideTypeSell, fixedpoint.One))
+		assert.True(t, errors.Is(err, ErrCloseOnly))
+
+		buyOrder := newOrder(types.SideTypeBuy, fixedpoint.MustNewFromString("0.5"))
+		mockEx.EXPECT().SubmitOrder(gomock.Any(), buyOrder).Return(&types.Order{SubmitOrder: buyOrder}, nil)
+		_, err = orderExecutor.SubmitOrders(ctx, buyOrder)
+		assert.NoError(t, err)
+	})
+
+	t.Run("flat position", func(t *testing.T) {
+		orderExecutor, _ := newExecutor(fixedpoint.Zero)
+
+		_, err := orderExecutor.SubmitOrders(ctx, newOrder(types.SideTypeBuy, fixedpoint.One))
+		assert.True(t, errors.Is(err, ErrCloseOnly))
+
+		_, err = orderExecutor.SubmitOrders(ctx, newOrder(types.SideTypeSell, fixedpoint.One))
+		assert.True(t, errors.Is(err, ErrCloseOnly))
+	})
+}
+
+func TestGeneralOrderExecutor_DryRun(t *testing.T) {
+	hook := test.NewGlobal()
+	defer hook.Reset()
+
+	market := getTestMarket()
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	// no SubmitOrder or CancelOrders call is expected on the exchange
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+
+	session := NewExchangeSession("test", mockEx)
+	session.markets[market.Symbol] = market
+
+	position := types.NewPositionFromMarket(market)
+	orderExecutor := NewGeneralOrderExecutor(session, "BTCUSDT", "test", "test-01", position)
+	orderExecutor.SetDryRun(true)
+	assert.True(t, orderExecutor.IsDryRun())
+
+	ctx := context.Background()
+	createdOrders, err := orderExecutor.SubmitOrders(ctx, types.SubmitOrder{
+		Symbol:   "BTCUSDT",
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimit,
+		Market:   market,
+		Price:    fixedpoint.NewFromFloat(20000.0),
+		Quantity: fixedpoint.MustNewFromString("0.01"),
+	}, types.SubmitOrder{
+		Symbol:   "BTCUSDT",
+		Side:     types.SideTypeSell,
+		Type:     types.OrderTypeLimit,
+		Market:   market,
+		Price:    fixedpoint.NewFromFloat(21000.0),
+		Quantity: fixedpoint.MustNewFromString("0.01"),
+	})
+	if !assert.NoError(t, err) || !assert.Len(t, createdOrders, 2) {
+		return
+	}
+
+	for _, order := range createdOrders {
+		assert.Equal(t, types.OrderStatusSimulated, order.Status)
+		assert.True(t, orderExecutor.ActiveMakerOrders().Exists(order))
+		assert.True(t, IsDryRunOrderID(order.OrderID), "the simulated order id should be in the reserved range")
+	}
+	assert.NotEqual(t, createdOrders[0].OrderID, createdOrders[1].OrderID)
+	assert.False(t, IsDryRunOrderID(1))
+
+	// the executors of the same session don't reuse the simulated order ids
+	orderExecutor2 := NewGeneralOrderExecutor(session, "BTCUSDT", "test", "test-02", types.NewPositionFromMarket(market))
+	orderExecutor2.SetDryRun(true)
+	createdOrders2, err := orderExecutor2.SubmitOrders(ctx, types.SubmitOrder{
+		Symbol:   "BTCUSDT",
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimit,
+		Market:   market,
+		Price:    fixedpoint.NewFromFloat(20000.0),
+		Quantity: fixedpoint.MustNewFromString("0.01"),
+	})
+	if assert.NoError(t, err) && assert.Len(t, createdOrders2, 1) {
+		assert.NotContains(t, []uint64{createdOrders[0].OrderID, createdOrders[1].OrderID}, createdOrders2[0].OrderID)
+	}
+
+	if entry := hook.LastEntry(); assert.NotNil(t, entry) {
+		assert.Contains(t, entry.Message, "[DryRun] simulated order")
+		assert.Contains(t, entry.Message, string(types.OrderStatusSimulated))
+	}
+
+	// the invalid order is rejected by the validation
+	_, err = orderExecutor.SubmitOrders(ctx, types.SubmitOrder{
+		Symbol:   "BTCUSDT",
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimit,
+		Market:   market,
+		Price:    fixedpoint.NewFromFloat(20000.0),
+		Quantity: fixedpoint.MustNewFromString("0.0001"),
+	})
+	assert.Error(t, err)
+
+	// the simulated orders are canceled locally
+	assert.NoError(t, orderExecutor.GracefulCancel(ctx))
+	assert.Equal(t, 0, orderExecutor.ActiveMakerOrders().NumOfOrders())
+}
+
+func TestGeneralOrderExecutor_TrimQuantityOnInsufficientBalance(t *testing.T) {
+	market := getTestMarket()
+	market.StepSize = fixedpoint.MustNewFromString("0.00001")
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+
+	session := NewExchangeSession("test", mockEx)
+	session.markets[market.Symbol] = market
+	// the cached balance is stale, it still covers the order
+	session.Account.UpdateBalances(types.BalanceMap{
+		"USDT": {Currency: "USDT", Available: fixedpoint.NewFromFloat(1000.0)},
+	})
+
+	position := types.NewPositionFromMarket(market)
+	orderExecutor := NewGeneralOrderExecutor(session, "BTCUSDT", "test", "test-01", position)
+	orderExecutor.SetTrimQuantityOnInsufficientBalance(true)
+
+	submitOrder := types.SubmitOrder{
+		Symbol:   "BTCUSDT",
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimit,
+		Market:   market,
+		Price:    fixedpoint.NewFromFloat(20000.0),
+		Quantity: fixedpoint.MustNewFromString("0.01"),
+	}
+
+	gomock.InOrder(
+		mockEx.EXPECT().SubmitOrder(gomock.Any(), submitOrder).
+			Return(nil, errors.New("Account has insufficient balance for requested action.")),
+		// the fee is deducted from the balance, so the balance is a bit less than 0.01 BTC
+		mockEx.EXPECT().QueryAccountBalances(gomock.Any()).Return(types.BalanceMap{
+			"USDT": {Currency: "USDT", Available: fixedpoint.NewFromFloat(199.9)},
+		}, nil),
+		mockEx.EXPECT().SubmitOrder(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, order types.SubmitOrder) (*types.Order, error) {
+				// 199.9 / 20000 = 0.009995, truncated by the step size
+				assert.Equal(t, "0.00999", order.Quantity.String())
+				return &types.Order{SubmitOrder: order, OrderID: 1, Status: types.OrderStatusNew}, nil
+			}),
+	)
+
+	ctx := context.Background()
+	createdOrders, err := orderExecutor.SubmitOrders(ctx, submitOrder)
+	if assert.NoError(t, err) && assert.Len(t, createdOrders, 1) {
+		assert.Equal(t, "0.00999", createdOrders[0].Quantity.String())
+		assert.True(t, orderExecutor.ActiveMakerOrders().Exists(createdOrders[0]))
+	}
+
+	// the cached balance is updated by the query
+	balance, ok := session.Account.Balance("USDT")
+	if assert.True(t, ok) {
+		assert.Equal(t, "199.9", balance.Available.String())
+	}
+
+	// the trimmed quantity is less than the min quantity, the order should not be re-submitted
+	gomock.InOrder(
+		mockEx.EXPECT().SubmitOrder(gomock.Any(), submitOrder).
+			Return(nil, errors.New("Account has insufficient balance for requested action.")),
+		mockEx.EXPECT().QueryAccountBalances(gomock.Any()).Return(types.BalanceMap{
+			"USDT": {Currency: "USDT", Available: fixedpoint.NewFromFloat(10.0)},
+		}, nil),
+	)
+
+	createdOrders, err = orderExecutor.SubmitOrders(ctx, submitOrder)
+	assert.Error(t, err)
+	assert.Empty(t, createdOrders)
+}
+
+func TestGeneralOrderExecutor_TrimQuantityOnInsufficientBalance_OnlyMatchedOrders(t *testing.T) {
+	market := getTestMarket()
+	market.StepSize = fixedpoint.MustNewFromString("0.00001")
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+
+	session := NewExchangeSession("test", mockEx)
+	session.markets[market.Symbol] = market
+
+	position := types.NewPositionFromMarket(market)
+	orderExecutor := NewGeneralOrderExecutor(session, "BTCUSDT", "test", "test-01", position)
+	orderExecutor.SetTrimQuantityOnInsufficientBalance(true)
+
+	buyOrder := types.SubmitOrder{
+		Symbol:   "BTCUSDT",
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimit,
+		Market:   market,
+		Price:    fixedpoint.NewFromFloat(20000.0),
+		Quantity: fixedpoint.MustNewFromString("0.01"),
+	}
+
+	sellOrder := types.SubmitOrder{
+		Symbol:   "BTCUSDT",
+		Side:     types.SideTypeSell,
+		Type:     types.OrderTypeLimit,
+		Market:   market,
+		Price:    fixedpoint.NewFromFloat(21000.0),
+		Quantity: fixedpoint.MustNewFromString("0.01"),
+	}
+
+	gomock.InOrder(
+		mockEx.EXPECT().SubmitOrder(gomock.Any(), buyOrder).
+			Return(nil, errors.New("Account has insufficient balance for requested action.")),
+		mockEx.EXPECT().SubmitOrder(gomock.Any(), sellOrder).
+			Return(nil, errors.New("Order price exceeds the limit")),
+		mockEx.EXPECT().QueryAccountBalances(gomock.Any()).Return(types.BalanceMap{
+			"USDT": {Currency: "USDT", Available: fixedpoint.NewFromFloat(100.0)},
+			"BTC":  {Currency: "BTC", Available: fixedpoint.NewFromFloat(1.0)},
+		}, nil),
+		// only the buy order is trimmed and re-submitted
+		mockEx.EXPECT().SubmitOrder(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, order types.SubmitOrder) (*types.Order, error) {
+				assert.Equal(t, types.SideTypeBuy, order.Side)
+				assert.Equal(t, "0.005", order.Quantity.String())
+				return &types.Order{SubmitOrder: order, OrderID: 1, Status: types.OrderStatusNew}, nil
+			}),
+	)
+
+	createdOrders, err := orderExecutor.SubmitOrders(context.Background(), buyOrder, sellOrder)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "Order price exceeds the limit")
+	}
+
+	if assert.Len(t, createdOrders, 1) {
+		assert.Equal(t, types.SideTypeBuy, createdOrders[0].Side)
+	}
+}
+
+func Test_isInsufficientBalanceError(t *testing.T) {
+	assert.True(t, isInsufficientBalanceError(errors.New("Account has insufficient balance for requested action.")))
+	assert.True(t, isInsufficientBalanceError(errors.New("Balance insufficient!")))
+	assert.True(t, isInsufficientBalanceError(errors.New("Order failed. Insufficient USDT balance in account")))
+	assert.False(t, isInsufficientBalanceError(errors.New("Timestamp for this request is outside of the recvWindow.")))
+}
+
+func TestGeneralOrderExecutor_Execution(t *testing.T) {
+	market := getTestMarket()
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	orderID := uint64(0)
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+	mockEx.EXPECT().SubmitOrder(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, order types.SubmitOrder) (*types.Order, error) {
+			orderID++
+			return &types.Order{SubmitOrder: order, OrderID: orderID, Status: types.OrderStatusNew}, nil
+		}).Times(2)
+
+	session := NewExchangeSession("test", mockEx)
+	session.markets[market.Symbol] = market
+	session.lastPrices[market.Symbol] = fixedpoint.NewFromFloat(20000.0)
+
+	position := types.NewPositionFromMarket(market)
+	orderExecutor := NewGeneralOrderExecutor(session, "BTCUSDT", "test", "test-01", position)
+
+	buyOrders, err := orderExecutor.SubmitOrders(context.Background(), types.SubmitOrder{
+		Symbol:   "BTCUSDT",
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeMarket,
+		Market:   market,
+		Quantity: fixedpoint.One,
+	})
+	if !assert.NoError(t, err) || !assert.Len(t, buyOrders, 1) {
+		return
+	}
+
+	session.lastPrices[market.Symbol] = fixedpoint.NewFromFloat(21000.0)
+	sellOrders, err := orderExecutor.SubmitOrders(context.Background(), types.SubmitOrder{
+		Symbol:   "BTCUSDT",
+		Side:     types.SideTypeSell,
+		Type:     types.OrderTypeMarket,
+		Market:   market,
+		Quantity: fixedpoint.One,
+	})
+	if !assert.NoError(t, err) || !assert.Len(t, sellOrders, 1) {
+		return
+	}
+
+	// the fill of the first parent order arrives after the second parent order is submitted
+	orderExecutor.TradeCollector().ProcessTrade(types.Trade{
+		ID:            1,
+		OrderID:       buyOrders[0].OrderID,
+		Exchange:      types.ExchangeBinance,
+		Symbol:        "BTCUSDT",
+		Side:          types.SideTypeBuy,
+		IsBuyer:       true,
+		Price:         fixedpoint.NewFromFloat(20010.0),
+		Quantity:      fixedpoint.One,
+		QuoteQuantity: fixedpoint.NewFromFloat(20010.0),
+	})
+
+	buyExecution, ok := orderExecutor.Execution(buyOrders[0].OrderID)
+	if assert.True(t, ok) {
+		assert.Equal(t, uint64(1), buyExecution.ID)
+		assert.Equal(t, types.SideTypeBuy, buyExecution.Side)
+		assert.Equal(t, "20000", buyExecution.ArrivalPrice.String())
+		assert.Equal(t, "20010", buyExecution.AveragePrice.String())
+		assert.Equal(t, "1", buyExecution.FilledQuantity.String())
+	}
+
+	sellExecution, ok := orderExecutor.Execution(sellOrders[0].OrderID)
+	if assert.True(t, ok) {
+		assert.Equal(t, uint64(2), sellExecution.ID)
+		assert.Equal(t, types.SideTypeSell, sellExecution.Side)
+		assert.Equal(t, "21000", sellExecution.ArrivalPrice.String())
+		assert.True(t, sellExecution.FilledQuantity.IsZero())
+	}
+
+	// the sell parent order is not filled yet, so the last execution with fills is the buy parent order
+	assert.Equal(t, buyExecution, orderExecutor.LastExecution())
+
+	_, ok = orderExecutor.Execution(100)
+	assert.False(t, ok)
+}
+
+func TestGeneralOrderExecutor_Execution_MixedSides(t *testing.T) {
+	market := getTestMarket()
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	orderID := uint64(0)
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+	mockEx.EXPECT().SubmitOrder(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, order types.SubmitOrder) (*types.Order, error) {
+			orderID++
+			return &types.Order{SubmitOrder: order, OrderID: orderID, Status: types.OrderStatusNew}, nil
+		}).Times(3)
+
+	session := NewExchangeSession("test", mockEx)
+	session.markets[market.Symbol] = market
+	session.lastPrices[market.Symbol] = fixedpoint.NewFromFloat(20000.0)
+
+	position := types.NewPositionFromMarket(market)
+	orderExecutor := NewGeneralOrderExecutor(session, "BTCUSDT", "test", "test-01", position)
+
+	assert.Equal(t, Execution{}, orderExecutor.LastExecution())
+
+	// the bid and ask orders of a grid are submitted in one call
+	createdOrders, err := orderExecutor.SubmitOrders(context.Background(),
+		types.SubmitOrder{
+			Symbol:   "BTCUSDT",
+			Side:     types.SideTypeBuy,
+			Type:     types.OrderTypeLimit,
+			Market:   market,
+			Price:    fixedpoint.NewFromFloat(19900.0),
+			Quantity: fixedpoint.One,
+		},
+		types.SubmitOrder{
+			Symbol:   "BTCUSDT",
+			Side:     types.SideTypeSell,
+			Type:     types.OrderTypeLimit,
+			Market:   market,
+			Price:    fixedpoint.NewFromFloat(20100.0),
+			Quantity: fixedpoint.One,
+		},
+		types.SubmitOrder{
+			Symbol:   "BTCUSDT",
+			Side:     types.SideTypeBuy,
+			Type:     types.OrderTypeLimit,
+			Market:   market,
+			Price:    fixedpoint.NewFromFloat(19800.0),
+			Quantity: fixedpoint.One,
+		},
+	)
+	if !assert.NoError(t, err) || !assert.Len(t, createdOrders, 3) {
+		return
+	}
+
+	for i, order := range createdOrders {
+		price := order.Price
+		orderExecutor.TradeCollector().ProcessTrade(types.Trade{
+			ID:            uint64(i + 1),
+			OrderID:       order.OrderID,
+			Exchange:      types.ExchangeBinance,
+			Symbol:        "BTCUSDT",
+			Side:          order.Side,
+			IsBuyer:       order.Side == types.SideTypeBuy,
+			Price:         price,
+			Quantity:      fixedpoint.One,
+			QuoteQuantity: price,
+		})
+	}
+
+	buyExecution, ok := orderExecutor.Execution(createdOrders[0].OrderID)
+	if assert.True(t, ok) {
+		assert.Equal(t, types.SideTypeBuy, buyExecution.Side)
+		assert.Equal(t, "2", buyExecution.FilledQuantity.String())
+		assert.Equal(t, "19850", buyExecution.AveragePrice.String())
+		// the buy fills are better than the arrival price
+		assert.Equal(t, "-0.0075", buyExecution.Slippage().String())
+	}
+
+	otherBuyExecution, ok := orderExecutor.Execution(createdOrders[2].OrderID)
+	if assert.True(t, ok) {
+		assert.Equal(t, buyExecution, otherBuyExecution)
+	}
+
+	sellExecution, ok := orderExecutor.Execution(createdOrders[1].OrderID)
+	if assert.True(t, ok) {
+		assert.NotEqual(t, buyExecution.ID, sellExecution.ID)
+		assert.Equal(t, types.SideTypeSell, sellExecution.Side)
+		assert.Equal(t, "1", sellExecution.FilledQuantity.String())
+		assert.Equal(t, "20100", sellExecution.AveragePrice.String())
+		// the sell fill is better than the arrival price
+		assert.Equal(t, "-0.005", sellExecution.Slippage().String())
+	}
+}
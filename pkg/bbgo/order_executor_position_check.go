@@ -0,0 +1,172 @@
+package bbgo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/util"
+)
+
+// ExchangePositionService is implemented by the derivatives exchanges which can report the actual positions
+type ExchangePositionService interface {
+	QueryPositions(ctx context.Context, symbols ...string) ([]types.Position, error)
+}
+
+// PositionDrift describes the divergence between the position built by the trade collector
+// and the actual position reported by the exchange
+type PositionDrift struct {
+	Symbol   string
+	Expected fixedpoint.Value
+	Actual   fixedpoint.Value
+	Drift    fixedpoint.Value
+	Resynced bool
+}
+
+func (d PositionDrift) String() string {
+	return fmt.Sprintf("%s position drift detected: expected base %s, actual base %s, drift %s",
+		d.Symbol, d.Expected.String(), d.Actual.String(), d.Drift.String())
+}
+
+// PositionCheckOptions configures the periodic position self-check of the GeneralOrderExecutor
+type PositionCheckOptions struct {
+	// Interval is the check interval
+	Interval time.Duration
+
+	// Tolerance is the maximum base quantity difference that is not treated as a drift
+	Tolerance fixedpoint.Value
+
+	// Resync updates the position base quantity to the actual quantity when a drift is detected
+	Resync bool
+}
+
+// OnPositionDrift registers the callback which is called when the position check detects a drift
+func (e *GeneralOrderExecutor) OnPositionDrift(cb func(drift PositionDrift)) {
+	e.positionDriftCallbacks = append(e.positionDriftCallbacks, cb)
+}
+
+func (e *GeneralOrderExecutor) emitPositionDrift(drift PositionDrift) {
+	for _, cb := range e.positionDriftCallbacks {
+		cb(drift)
+	}
+}
+
+// queryActualPositionBase returns the actual position base quantity.
+// For the futures session, the position is queried from the exchange, e.g., binance futures.
+// For the spot session, the position is computed from the base currency balance,
+// since the account may hold the base asset which is not managed by the strategy,
+// the difference of the first check is recorded as the offset and reported,
+// a drift which already exists at the first check can not be told from the unmanaged base asset.
+func (e *GeneralOrderExecutor) queryActualPositionBase(ctx context.Context) (fixedpoint.Value, error) {
+	if e.session.Futures {
+		service, ok := e.session.Exchange.(ExchangePositionService)
+		if !ok {
+			return fixedpoint.Zero, fmt.Errorf("exchange %s does not support querying positions", e.session.ExchangeName)
+		}
+
+		positions, err := service.QueryPositions(ctx, e.symbol)
+		if err != nil {
+			return fixedpoint.Zero, err
+		}
+
+		for i := range positions {
+			if positions[i].Symbol == e.symbol {
+				return positions[i].Base, nil
+			}
+		}
+
+		return fixedpoint.Zero, nil
+	}
+
+	balances, err := e.session.Exchange.QueryAccountBalances(ctx)
+	if err != nil {
+		return fixedpoint.Zero, err
+	}
+
+	var total fixedpoint.Value
+	if balance, ok := balances[e.position.Market.BaseCurrency]; ok {
+		total = balance.Total()
+	}
+
+	if e.spotBaseOffset == nil {
+		offset := total.Sub(e.position.GetBase())
+		e.spotBaseOffset = &offset
+
+		if !offset.IsZero() {
+			msg := fmt.Sprintf("%s position check: the %s balance %s differs from the position base %s, "+
+				"the difference %s is treated as the base asset not managed by the strategy, please verify it",
+				e.symbol, e.position.Market.BaseCurrency, total.String(), e.position.GetBase().String(), offset.String())
+			e.fieldLogger().Warn(msg)
+			if !e.disableNotify {
+				Notify(msg)
+			}
+		}
+	}
+
+	return total.Sub(*e.spotBaseOffset), nil
+}
+
+// CheckPosition compares the position built from the collected trades with the actual position,
+// the drift is returned when the difference exceeds the tolerance.
+func (e *GeneralOrderExecutor) CheckPosition(ctx context.Context, options PositionCheckOptions) (*PositionDrift, error) {
+	actual, err := e.queryActualPositionBase(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	expected := e.position.GetBase()
+	diff := actual.Sub(expected)
+	if diff.Abs().Compare(options.Tolerance) <= 0 {
+		return nil, nil
+	}
+
+	drift := &PositionDrift{
+		Symbol:   e.symbol,
+		Expected: expected,
+		Actual:   actual,
+		Drift:    diff,
+	}
+
+	e.fieldLogger().Warn(drift.String())
+
+	if options.Resync {
+		if err := e.position.ModifyBase(actual); err != nil {
+			return drift, err
+		}
+
+		drift.Resynced = true
+	}
+
+	if !e.disableNotify {
+		Notify(drift.String())
+	}
+
+	e.emitPositionDrift(*drift)
+	return drift, nil
+}
+
+// StartPositionCheck starts a goroutine that checks the position periodically until the context is canceled
+func (e *GeneralOrderExecutor) StartPositionCheck(ctx context.Context, options PositionCheckOptions) {
+	if options.Interval == 0 {
+		options.Interval = 10 * time.Minute
+	}
+
+	types.GoWithContext(ctx, func() {
+		t := time.NewTicker(util.MillisecondsJitter(options.Interval, 500))
+		defer t.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case <-t.C:
+				if _, err := e.CheckPosition(ctx, options); err != nil {
+					e.fieldLogger().WithError(err).Errorf("position check error")
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,206 @@
+package bbgo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// OrderRouteQuote is the quote of a session for routing an order
+type OrderRouteQuote struct {
+	Session *ExchangeSession
+
+	// Price is the best ask price for the buy order, or the best bid price for the sell order
+	Price fixedpoint.Value
+
+	// FeeRate is the fee rate applied to the order, maker fee rate for the limit maker orders, otherwise the taker fee rate
+	FeeRate fixedpoint.Value
+
+	// EffectivePrice is the price after fees, price * (1 + fee) for buy, price * (1 - fee) for sell
+	EffectivePrice fixedpoint.Value
+}
+
+// OrderRoutePolicy picks the quote to route the order to from the given quotes, the quotes are never empty
+type OrderRoutePolicy func(side types.SideType, quotes []OrderRouteQuote) OrderRouteQuote
+
+// BestPriceRoutePolicy picks the session with the best effective price after fees
+func BestPriceRoutePolicy(side types.SideType, quotes []OrderRouteQuote) OrderRouteQuote {
+	best := quotes[0]
+	for _, q := range quotes[1:] {
+		switch side {
+		case types.SideTypeBuy:
+			if q.EffectivePrice.Compare(best.EffectivePrice) < 0 {
+				best = q
+			}
+		case types.SideTypeSell:
+			if q.EffectivePrice.Compare(best.EffectivePrice) > 0 {
+				best = q
+			}
+		}
+	}
+
+	return best
+}
+
+// LowestFeeRoutePolicy picks the session with the lowest fee rate, the effective price breaks the tie
+func LowestFeeRoutePolicy(side types.SideType, quotes []OrderRouteQuote) OrderRouteQuote {
+	best := quotes[0]
+	for _, q := range quotes[1:] {
+		if c := q.FeeRate.Compare(best.FeeRate); c < 0 {
+			best = q
+		} else if c == 0 {
+			best = BestPriceRoutePolicy(side, []OrderRouteQuote{best, q})
+		}
+	}
+
+	return best
+}
+
+// SetOrderRoutePolicy sets the policy used by RouteOrder, BestPriceRoutePolicy is used by default
+func (environ *Environment) SetOrderRoutePolicy(policy OrderRoutePolicy) {
+	environ.orderRoutePolicy = policy
+}
+
+// RouteOrder submits the order to the session picked by the order route policy among the sessions of the given executors,
+// the executors are keyed by the session name and they should be bound to the order symbol.
+// The price and the quantity are formatted with the market of the chosen session, and the order is submitted through
+// the executor of the chosen session, so that it's tracked by the order store and the position of the executor.
+// It returns the created order and the name of the chosen session.
+func (environ *Environment) RouteOrder(
+	ctx context.Context, order types.SubmitOrder, executors map[string]*GeneralOrderExecutor,
+) (*types.Order, string, error) {
+	quotes, err := environ.queryOrderRouteQuotes(ctx, order, executors)
+	if err != nil {
+		return nil, "", err
+	}
+
+	policy := environ.orderRoutePolicy
+	if policy == nil {
+		policy = BestPriceRoutePolicy
+	}
+
+	quote := policy(order.Side, quotes)
+	session := quote.Session
+
+	market, _ := session.Market(order.Symbol)
+	order, err = formatRouteOrder(order, market)
+	if err != nil {
+		return nil, session.Name, err
+	}
+
+	createdOrders, err := executors[session.Name].SubmitOrders(ctx, order)
+	if err != nil {
+		return nil, session.Name, err
+	}
+
+	if len(createdOrders) == 0 {
+		return nil, session.Name, fmt.Errorf("no order is created on session %s", session.Name)
+	}
+
+	return &createdOrders[0], session.Name, nil
+}
+
+// formatRouteOrder truncates the price and the quantity of the order with the market of the chosen session,
+// the sessions can list the same symbol with different tick sizes and step sizes.
+func formatRouteOrder(order types.SubmitOrder, market types.Market) (types.SubmitOrder, error) {
+	order.Market = market
+	order.Quantity = market.TruncateQuantity(order.Quantity)
+	if order.Quantity.Sign() <= 0 {
+		return order, fmt.Errorf("%s order quantity is truncated to zero by the step size %s", order.Symbol, market.StepSize.String())
+	}
+
+	if order.Price.Sign() > 0 {
+		order.Price = market.TruncatePrice(order.Price)
+	}
+
+	if order.StopPrice.Sign() > 0 {
+		order.StopPrice = market.TruncatePrice(order.StopPrice)
+	}
+
+	return order, nil
+}
+
+func (environ *Environment) queryOrderRouteQuotes(
+	ctx context.Context, order types.SubmitOrder, executors map[string]*GeneralOrderExecutor,
+) ([]OrderRouteQuote, error) {
+	var names []string
+	for name := range executors {
+		names = append(names, name)
+	}
+
+	// iterate the sessions in a stable order
+	sort.Strings(names)
+
+	var quotes []OrderRouteQuote
+	for _, name := range names {
+		session, ok := environ.sessions[name]
+		if !ok {
+			continue
+		}
+
+		if _, ok := session.Market(order.Symbol); !ok {
+			continue
+		}
+
+		bid, ask, ok := sessionBestBidAsk(ctx, session, order.Symbol)
+		if !ok {
+			continue
+		}
+
+		feeRate := session.TakerFeeRate
+		if order.Type == types.OrderTypeLimitMaker {
+			feeRate = session.MakerFeeRate
+		}
+
+		quote := OrderRouteQuote{
+			Session: session,
+			FeeRate: feeRate,
+		}
+
+		switch order.Side {
+		case types.SideTypeBuy:
+			quote.Price = ask
+			quote.EffectivePrice = ask.Mul(fixedpoint.One.Add(feeRate))
+		case types.SideTypeSell:
+			quote.Price = bid
+			quote.EffectivePrice = bid.Mul(fixedpoint.One.Sub(feeRate))
+		default:
+			return nil, fmt.Errorf("unsupported order side: %s", order.Side)
+		}
+
+		if quote.Price.Sign() <= 0 {
+			continue
+		}
+
+		quotes = append(quotes, quote)
+	}
+
+	if len(quotes) == 0 {
+		return nil, fmt.Errorf("no session available for routing the %s order", order.Symbol)
+	}
+
+	return quotes, nil
+}
+
+// sessionBestBidAsk returns the best bid and ask from the session order book,
+// it falls back to query the ticker if the order book is not subscribed.
+func sessionBestBidAsk(ctx context.Context, session *ExchangeSession, symbol string) (bid, ask fixedpoint.Value, ok bool) {
+	if book, hasBook := session.OrderBook(symbol); hasBook {
+		if bestBid, bestAsk, hasBest := book.BestBidAndAsk(); hasBest {
+			return bestBid.Price, bestAsk.Price, true
+		}
+	}
+
+	ticker, err := session.Exchange.QueryTicker(ctx, symbol)
+	if err != nil || ticker == nil {
+		log.WithError(err).Warnf("unable to query %s ticker from session %s", symbol, session.Name)
+		return bid, ask, false
+	}
+
+	return ticker.Buy, ticker.Sell, true
+}
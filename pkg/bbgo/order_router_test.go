@@ -0,0 +1,150 @@
+package bbgo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/types/mocks"
+)
+
+func TestEnvironment_RouteOrder(t *testing.T) {
+	market := getTestMarket()
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	newSession := func(name string, market types.Market, bid, ask, takerFeeRate float64) (*ExchangeSession, *mocks.MockExchange) {
+		mockEx := mocks.NewMockExchange(mockCtrl)
+		mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+		mockEx.EXPECT().QueryTicker(gomock.Any(), "BTCUSDT").Return(&types.Ticker{
+			Buy:  fixedpoint.NewFromFloat(bid),
+			Sell: fixedpoint.NewFromFloat(ask),
+		}, nil).AnyTimes()
+
+		session := NewExchangeSession(name, mockEx)
+		session.markets[market.Symbol] = market
+		session.TakerFeeRate = fixedpoint.NewFromFloat(takerFeeRate)
+		return session, mockEx
+	}
+
+	newExecutors := func(sessions ...*ExchangeSession) map[string]*GeneralOrderExecutor {
+		executors := make(map[string]*GeneralOrderExecutor)
+		for _, session := range sessions {
+			position := types.NewPositionFromMarket(market)
+			executors[session.Name] = NewGeneralOrderExecutor(session, "BTCUSDT", "test", "test-"+session.Name, position)
+		}
+		return executors
+	}
+
+	order := types.SubmitOrder{
+		Symbol:   "BTCUSDT",
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeMarket,
+		Quantity: fixedpoint.One,
+	}
+
+	t.Run("best price", func(t *testing.T) {
+		// effective ask: 20000 * 1.001 = 20020 vs 20010 * 1.0002 = 20014.002
+		sessionA, _ := newSession("a", market, 19990.0, 20000.0, 0.001)
+		sessionB, mockExB := newSession("b", market, 20000.0, 20010.0, 0.0002)
+
+		environ := NewEnvironment()
+		environ.AddExchangeSession("a", sessionA)
+		environ.AddExchangeSession("b", sessionB)
+		executors := newExecutors(sessionA, sessionB)
+
+		expectedOrder := order
+		expectedOrder.Market = market
+		mockExB.EXPECT().SubmitOrder(gomock.Any(), expectedOrder).Return(&types.Order{SubmitOrder: expectedOrder, OrderID: 1}, nil)
+
+		createdOrder, sessionName, err := environ.RouteOrder(context.Background(), order, executors)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "b", sessionName)
+			assert.NotNil(t, createdOrder)
+
+			// the order is tracked by the executor of the chosen session
+			assert.True(t, executors["b"].OrderStore().Exists(1))
+			assert.False(t, executors["a"].OrderStore().Exists(1))
+		}
+	})
+
+	t.Run("lowest fee", func(t *testing.T) {
+		sessionA, mockExA := newSession("a", market, 19990.0, 20000.0, 0.0001)
+		sessionB, _ := newSession("b", market, 19995.0, 19996.0, 0.001)
+
+		environ := NewEnvironment()
+		environ.AddExchangeSession("a", sessionA)
+		environ.AddExchangeSession("b", sessionB)
+		environ.SetOrderRoutePolicy(LowestFeeRoutePolicy)
+
+		mockExA.EXPECT().SubmitOrder(gomock.Any(), gomock.Any()).Return(&types.Order{}, nil)
+
+		_, sessionName, err := environ.RouteOrder(context.Background(), order, newExecutors(sessionA, sessionB))
+		if assert.NoError(t, err) {
+			assert.Equal(t, "a", sessionName)
+		}
+	})
+
+	t.Run("format with the chosen market", func(t *testing.T) {
+		marketB := market
+		marketB.TickSize = fixedpoint.MustNewFromString("0.1")
+		marketB.PricePrecision = 1
+		marketB.StepSize = fixedpoint.MustNewFromString("0.001")
+		marketB.VolumePrecision = 3
+
+		sessionA, _ := newSession("a", market, 19990.0, 20000.0, 0.001)
+		sessionB, mockExB := newSession("b", marketB, 19995.0, 19996.0, 0.001)
+
+		environ := NewEnvironment()
+		environ.AddExchangeSession("a", sessionA)
+		environ.AddExchangeSession("b", sessionB)
+
+		limitOrder := types.SubmitOrder{
+			Symbol:   "BTCUSDT",
+			Side:     types.SideTypeBuy,
+			Type:     types.OrderTypeLimit,
+			Price:    fixedpoint.MustNewFromString("19996.12345"),
+			Quantity: fixedpoint.MustNewFromString("0.12345678"),
+		}
+
+		expectedOrder := limitOrder
+		expectedOrder.Market = marketB
+		expectedOrder.Price = fixedpoint.MustNewFromString("19996.1")
+		expectedOrder.Quantity = fixedpoint.MustNewFromString("0.123")
+		mockExB.EXPECT().SubmitOrder(gomock.Any(), expectedOrder).Return(&types.Order{SubmitOrder: expectedOrder}, nil)
+
+		createdOrder, sessionName, err := environ.RouteOrder(context.Background(), limitOrder, newExecutors(sessionA, sessionB))
+		if assert.NoError(t, err) {
+			assert.Equal(t, "b", sessionName)
+			assert.Equal(t, "19996.1", createdOrder.Price.String())
+			assert.Equal(t, "0.123", createdOrder.Quantity.String())
+		}
+	})
+
+	t.Run("session without executor", func(t *testing.T) {
+		sessionA, mockExA := newSession("a", market, 19990.0, 20000.0, 0.001)
+		sessionB, _ := newSession("b", market, 20000.0, 20010.0, 0.0002)
+
+		environ := NewEnvironment()
+		environ.AddExchangeSession("a", sessionA)
+		environ.AddExchangeSession("b", sessionB)
+
+		mockExA.EXPECT().SubmitOrder(gomock.Any(), gomock.Any()).Return(&types.Order{}, nil)
+
+		_, sessionName, err := environ.RouteOrder(context.Background(), order, newExecutors(sessionA))
+		if assert.NoError(t, err) {
+			assert.Equal(t, "a", sessionName)
+		}
+	})
+
+	t.Run("no session lists the symbol", func(t *testing.T) {
+		environ := NewEnvironment()
+		_, _, err := environ.RouteOrder(context.Background(), order, nil)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,148 @@
+package bbgo
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+var tenThousand = fixedpoint.NewFromInt(10_000)
+
+// QuoteManager maintains one resting quote per side for market making,
+// the quote is only cancel-replaced when the desired price moves beyond the threshold or the quantity changes materially,
+// so that the sub-tick price changes won't churn the orders and waste the rate limit.
+type QuoteManager struct {
+	// PriceThresholdTicks is the minimal price change in ticks to replace the quote
+	PriceThresholdTicks int `json:"priceThresholdTicks,omitempty"`
+
+	// PriceThresholdBps is the minimal price change in basis points to replace the quote,
+	// the larger one of the ticks and the bps threshold is used.
+	PriceThresholdBps fixedpoint.Value `json:"priceThresholdBps,omitempty"`
+
+	// QuantityThresholdRatio is the minimal quantity change ratio to replace the quote, e.g. 0.1 for 10%
+	QuantityThresholdRatio fixedpoint.Value `json:"quantityThresholdRatio,omitempty"`
+
+	market        types.Market
+	orderExecutor OrderExecutor
+
+	mu     sync.Mutex
+	quotes map[types.SideType]types.Order
+}
+
+func NewQuoteManager(market types.Market, orderExecutor OrderExecutor) *QuoteManager {
+	return &QuoteManager{
+		market:        market,
+		orderExecutor: orderExecutor,
+		quotes:        make(map[types.SideType]types.Order),
+	}
+}
+
+// BindStream removes the quotes that are filled, canceled or rejected
+func (m *QuoteManager) BindStream(stream types.Stream) {
+	stream.OnOrderUpdate(func(order types.Order) {
+		switch order.Status {
+		case types.OrderStatusFilled, types.OrderStatusCanceled, types.OrderStatusRejected:
+		default:
+			return
+		}
+
+		m.mu.Lock()
+		if quote, ok := m.quotes[order.Side]; ok && quote.OrderID == order.OrderID {
+			delete(m.quotes, order.Side)
+		}
+		m.mu.Unlock()
+	})
+}
+
+// Quote returns the resting quote of the side, or cancel-replaces it with a new quote at the given price and quantity
+// when the change exceeds the thresholds. The returned bool is true when a new quote is submitted.
+func (m *QuoteManager) Quote(
+	ctx context.Context, side types.SideType, price, quantity fixedpoint.Value,
+) (*types.Order, bool, error) {
+	m.mu.Lock()
+	quote, hasQuote := m.quotes[side]
+	m.mu.Unlock()
+
+	if hasQuote {
+		if !m.shouldReplace(quote, price, quantity) {
+			return &quote, false, nil
+		}
+
+		if err := m.orderExecutor.CancelOrders(ctx, quote); err != nil {
+			return nil, false, fmt.Errorf("unable to cancel the quote %s: %w", quote.String(), err)
+		}
+
+		m.mu.Lock()
+		delete(m.quotes, side)
+		m.mu.Unlock()
+	}
+
+	createdOrders, err := m.orderExecutor.SubmitOrders(ctx, types.SubmitOrder{
+		Symbol:   m.market.Symbol,
+		Side:     side,
+		Type:     types.OrderTypeLimitMaker,
+		Price:    price,
+		Quantity: quantity,
+		Market:   m.market,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(createdOrders) == 0 {
+		return nil, false, fmt.Errorf("no %s quote is created", side)
+	}
+
+	created := createdOrders[0]
+	m.mu.Lock()
+	m.quotes[side] = created
+	m.mu.Unlock()
+	return &created, true, nil
+}
+
+// Cancel cancels all the resting quotes
+func (m *QuoteManager) Cancel(ctx context.Context) error {
+	m.mu.Lock()
+	var orders []types.Order
+	for _, quote := range m.quotes {
+		orders = append(orders, quote)
+	}
+	m.quotes = make(map[types.SideType]types.Order)
+	m.mu.Unlock()
+
+	if len(orders) == 0 {
+		return nil
+	}
+
+	return m.orderExecutor.CancelOrders(ctx, orders...)
+}
+
+func (m *QuoteManager) priceThreshold(price fixedpoint.Value) fixedpoint.Value {
+	threshold := m.market.TickSize.Mul(fixedpoint.NewFromInt(int64(m.PriceThresholdTicks)))
+	if m.PriceThresholdBps.Sign() > 0 {
+		threshold = fixedpoint.Max(threshold, price.Mul(m.PriceThresholdBps).Div(tenThousand))
+	}
+
+	return threshold
+}
+
+func (m *QuoteManager) shouldReplace(quote types.Order, price, quantity fixedpoint.Value) bool {
+	priceDiff := price.Sub(quote.Price).Abs()
+	if priceDiff.Compare(m.priceThreshold(quote.Price)) > 0 {
+		return true
+	}
+
+	quantityDiff := quantity.Sub(quote.Quantity).Abs()
+	if quantityDiff.IsZero() {
+		return false
+	}
+
+	if quote.Quantity.IsZero() {
+		return true
+	}
+
+	return quantityDiff.Div(quote.Quantity).Compare(m.QuantityThresholdRatio) > 0
+}
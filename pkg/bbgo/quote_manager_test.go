@@ -0,0 +1,104 @@
+package bbgo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	bbgomocks "github.com/c9s/bbgo/pkg/bbgo/mocks"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestQuoteManager_Quote(t *testing.T) {
+	market := getTestMarket()
+	market.TickSize = fixedpoint.MustNewFromString("0.01")
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	var orderID uint64
+	orderExecutor := bbgomocks.NewMockOrderExecutorExtended(mockCtrl)
+	orderExecutor.EXPECT().SubmitOrders(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, orders ...types.SubmitOrder) (types.OrderSlice, error) {
+			orderID++
+			return types.OrderSlice{{SubmitOrder: orders[0], OrderID: orderID, Status: types.OrderStatusNew}}, nil
+		}).Times(3)
+
+	manager := NewQuoteManager(market, orderExecutor)
+	manager.PriceThresholdTicks = 5
+	manager.QuantityThresholdRatio = fixedpoint.NewFromFloat(0.1)
+
+	ctx := context.Background()
+	quote, replaced, err := manager.Quote(ctx, types.SideTypeBuy, fixedpoint.NewFromFloat(20000.0), fixedpoint.One)
+	if assert.NoError(t, err) {
+		assert.True(t, replaced)
+		assert.Equal(t, types.OrderTypeLimitMaker, quote.Type)
+		assert.Equal(t, uint64(1), quote.OrderID)
+	}
+
+	// sub-threshold changes: 3 ticks and 5% of the quantity
+	quote, replaced, err = manager.Quote(ctx, types.SideTypeBuy, fixedpoint.MustNewFromString("20000.03"), fixedpoint.NewFromFloat(1.05))
+	if assert.NoError(t, err) {
+		assert.False(t, replaced, "sub-threshold change should not replace the quote")
+		assert.Equal(t, uint64(1), quote.OrderID)
+		assert.Equal(t, "20000", quote.Price.String())
+	}
+
+	// the price moves 10 ticks
+	orderExecutor.EXPECT().CancelOrders(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, orders ...types.Order) error {
+			assert.Equal(t, uint64(1), orders[0].OrderID)
+			return nil
+		})
+	quote, replaced, err = manager.Quote(ctx, types.SideTypeBuy, fixedpoint.MustNewFromString("20000.10"), fixedpoint.One)
+	if assert.NoError(t, err) {
+		assert.True(t, replaced)
+		assert.Equal(t, uint64(2), quote.OrderID)
+		assert.Equal(t, "20000.1", quote.Price.String())
+	}
+
+	// the quantity changes by 50%
+	orderExecutor.EXPECT().CancelOrders(gomock.Any(), gomock.Any()).Return(nil)
+	quote, replaced, err = manager.Quote(ctx, types.SideTypeBuy, fixedpoint.MustNewFromString("20000.10"), fixedpoint.NewFromFloat(1.5))
+	if assert.NoError(t, err) {
+		assert.True(t, replaced)
+		assert.Equal(t, uint64(3), quote.OrderID)
+	}
+}
+
+func TestQuoteManager_BindStream(t *testing.T) {
+	market := getTestMarket()
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	orderExecutor := bbgomocks.NewMockOrderExecutorExtended(mockCtrl)
+	orderExecutor.EXPECT().SubmitOrders(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, orders ...types.SubmitOrder) (types.OrderSlice, error) {
+			return types.OrderSlice{{SubmitOrder: orders[0], OrderID: 1, Status: types.OrderStatusNew}}, nil
+		}).Times(2)
+
+	stream := types.NewStandardStream()
+	manager := NewQuoteManager(market, orderExecutor)
+	manager.PriceThresholdBps = fixedpoint.NewFromFloat(10)
+	manager.BindStream(&stream)
+
+	ctx := context.Background()
+	quote, _, err := manager.Quote(ctx, types.SideTypeSell, fixedpoint.NewFromFloat(20000.0), fixedpoint.One)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// the filled quote is removed, so the next quote is submitted without canceling
+	filled := *quote
+	filled.Status = types.OrderStatusFilled
+	stream.EmitOrderUpdate(filled)
+
+	_, replaced, err := manager.Quote(ctx, types.SideTypeSell, fixedpoint.NewFromFloat(20000.0), fixedpoint.One)
+	if assert.NoError(t, err) {
+		assert.True(t, replaced)
+	}
+}
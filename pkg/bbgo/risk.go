@@ -196,10 +196,10 @@ func (c *AccountValueCalculator) MarginLevel(ctx context.Context) (fixedpoint.Va
 
 func aggregateUsdNetValue(balances types.BalanceMap) fixedpoint.Value {
 	totalUsdValue := fixedpoint.Zero
-	// get all usd value if any
+	// get all usd value if any, the stable coins are converted by their peg ratios
 	for currency, balance := range balances {
-		if types.IsUSDFiatCurrency(currency) {
-			totalUsdValue = totalUsdValue.Add(balance.Net())
+		if priceInUSD, ok := types.StableCoinPriceInUSD(currency, nil); ok {
+			totalUsdValue = totalUsdValue.Add(balance.Net().Mul(priceInUSD))
 		}
 	}
 
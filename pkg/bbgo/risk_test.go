@@ -187,6 +187,20 @@ func Test_aggregateUsdValue(t *testing.T) {
 			assert.Equalf(t, tt.want, aggregateUsdNetValue(tt.args.balances), "aggregateUsdNetValue(%v)", tt.args.balances)
 		})
 	}
+
+	t.Run("overridden peg", func(t *testing.T) {
+		defer types.SetStableCoinEquivalence(types.GetStableCoinEquivalence())
+
+		e := types.NewStableCoinEquivalence()
+		e.SetPeg("USDC", number(0.9))
+		types.SetStableCoinEquivalence(e)
+
+		// 70 * 0.9 + 100
+		assert.Equal(t, number(163.0), aggregateUsdNetValue(types.BalanceMap{
+			"USDC": types.Balance{Currency: "USDC", Available: number(70.0)},
+			"USDT": types.Balance{Currency: "USDT", Available: number(100.0)},
+		}))
+	})
 }
 
 func Test_usdFiatBalances(t *testing.T) {
@@ -2,8 +2,12 @@ package bbgo
 
 import (
 	"context"
+	"encoding/csv"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"strings"
 	"sync"
 	"time"
 
@@ -26,6 +30,8 @@ import (
 
 var KLinePreloadLimit int64 = 1000
 
+const defaultMidPriceSmoothingWindow = 10
+
 var ErrEmptyMarketInfo = errors.New("market info should not be empty, 0 markets loaded")
 
 // ExchangeSession presents the exchange connection Session
@@ -93,6 +99,22 @@ type ExchangeSession struct {
 
 	UseHeikinAshi bool `json:"heikinAshi,omitempty" yaml:"heikinAshi,omitempty"`
 
+	// TickSizeOverride overrides the tick size of the symbols reported by the exchange,
+	// the override must divide the exchange tick size evenly, e.g. 0.01 or 0.05 for the tick size 0.1
+	TickSizeOverride map[string]fixedpoint.Value `json:"tickSizeOverride,omitempty" yaml:"tickSizeOverride,omitempty"`
+
+	// MidPriceSmoothingWindow is the EMA window of the smoothed mid price updated from the tickers
+	MidPriceSmoothingWindow int `json:"midPriceSmoothingWindow,omitempty" yaml:"midPriceSmoothingWindow,omitempty"`
+
+	// MaxNumOfKLines is the max number of klines kept per interval in the market data stores,
+	// the oldest klines are dropped, zero uses the default truncation of MaxNumOfKLines.
+	MaxNumOfKLines int `json:"maxNumOfKLines,omitempty" yaml:"maxNumOfKLines,omitempty"`
+
+	// KLineCloseGracePeriod is the period waiting for the confirmed closed kline from the websocket
+	// after the provisional kline passed the interval boundary, the kline is queried via REST when the period is over.
+	// It's DefaultKLineCloseGracePeriod if not set, a negative value disables the REST query.
+	KLineCloseGracePeriod types.Duration `json:"klineCloseGracePeriod,omitempty" yaml:"klineCloseGracePeriod,omitempty"`
+
 	// Trades collects the executed trades from the exchange
 	// map: symbol -> []trade
 	Trades map[string]*types.TradeSlice `json:"-" yaml:"-"`
@@ -109,6 +131,11 @@ type ExchangeSession struct {
 	lastPrices         map[string]fixedpoint.Value
 	lastPriceUpdatedAt time.Time
 
+	// smoothedMidPrices stores the EMA-smoothed mid prices of the tickers,
+	// it's updated from the book ticker and the book snapshot callbacks
+	smoothedMidPrices      map[string]fixedpoint.Value
+	smoothedMidPricesMutex sync.Mutex
+
 	// marketDataStores contains the market data store of each market
 	marketDataStores map[string]*MarketDataStore
 
@@ -125,6 +152,9 @@ type ExchangeSession struct {
 	usedSymbols        map[string]struct{}
 	initializedSymbols map[string]struct{}
 
+	// serverTimeSynced is set once the server time is synchronized, see syncServerTime
+	serverTimeSynced bool
+
 	logger log.FieldLogger
 }
 
@@ -146,6 +176,7 @@ func NewExchangeSession(name string, exchange types.Exchange) *ExchangeSession {
 		markets:               make(map[string]types.Market),
 		startPrices:           make(map[string]fixedpoint.Value),
 		lastPrices:            make(map[string]fixedpoint.Value),
+		smoothedMidPrices:     make(map[string]fixedpoint.Value),
 		positions:             make(map[string]*types.Position),
 		marketDataStores:      make(map[string]*MarketDataStore),
 		standardIndicatorSets: make(map[string]*StandardIndicatorSet),
@@ -188,6 +219,43 @@ func (session *ExchangeSession) setAccount(a *types.Account) {
 	session.accountMutex.Unlock()
 }
 
+// Ping checks the connectivity and the api credentials of the session exchange,
+// the exchanges that don't support ping are skipped.
+func (session *ExchangeSession) Ping(ctx context.Context) error {
+	// the signed ping request might be rejected if the clock is skewed
+	session.syncServerTime(ctx)
+
+	pinger, ok := session.Exchange.(types.ExchangePinger)
+	if !ok {
+		return nil
+	}
+
+	if err := pinger.Ping(ctx); err != nil {
+		return fmt.Errorf("session %s: %s ping failed, please check the network connectivity and the api credentials: %w",
+			session.Name, session.ExchangeName, err)
+	}
+
+	return nil
+}
+
+// syncServerTime synchronizes the signed request timestamp with the exchange server time once,
+// the error is logged since the signed requests still work when the local clock is not skewed.
+func (session *ExchangeSession) syncServerTime(ctx context.Context) {
+	if session.PublicOnly || session.serverTimeSynced {
+		return
+	}
+
+	syncer, ok := session.Exchange.(types.ExchangeServerTimeSyncer)
+	if !ok {
+		return
+	}
+
+	session.serverTimeSynced = true
+	if err := syncer.SyncServerTime(ctx); err != nil {
+		log.WithError(err).Errorf("session %s: can not sync the server time", session.Name)
+	}
+}
+
 // Init initializes the basic data structure and market information by its exchange.
 // Note that the subscribed symbols are not loaded in this stage.
 func (session *ExchangeSession) Init(ctx context.Context, environ *Environment) error {
@@ -220,6 +288,13 @@ func (session *ExchangeSession) Init(ctx context.Context, environ *Environment)
 		return ErrEmptyMarketInfo
 	}
 
+	if len(session.TickSizeOverride) > 0 {
+		markets, err = applyTickSizeOverride(markets, session.TickSizeOverride)
+		if err != nil {
+			return err
+		}
+	}
+
 	session.markets = markets
 
 	if feeRateProvider, ok := session.Exchange.(types.ExchangeDefaultFeeRates); ok {
@@ -250,6 +325,8 @@ func (session *ExchangeSession) Init(ctx context.Context, environ *Environment)
 
 	// query and initialize the balances
 	if !session.PublicOnly {
+		session.syncServerTime(ctx)
+
 		if len(session.PrivateChannels) > 0 {
 			if setter, ok := session.UserDataStream.(types.PrivateChannelSetter); ok {
 				setter.SetPrivateChannels(session.PrivateChannels)
@@ -369,6 +446,8 @@ func (session *ExchangeSession) Init(ctx context.Context, environ *Environment)
 		session.lastPrices[trade.Symbol] = trade.Price
 	})
 
+	session.bindSmoothedMidPrice(session.MarketDataStream)
+
 	session.IsInitialized = true
 	return nil
 }
@@ -442,7 +521,7 @@ func (session *ExchangeSession) initSymbol(ctx context.Context, environ *Environ
 	orderStore.BindStream(session.UserDataStream)
 	session.orderStores[symbol] = orderStore
 
-	marketDataStore := NewMarketDataStore(symbol)
+	marketDataStore := session.newMarketDataStore(symbol)
 	if !disableMarketDataStore {
 		if _, ok := session.marketDataStores[symbol]; !ok {
 			marketDataStore.BindStream(session.MarketDataStream)
@@ -544,6 +623,16 @@ func (session *ExchangeSession) Indicators(symbol string) *IndicatorSet {
 	return set
 }
 
+// IndicatorSnapshot returns the latest values of the standard indicators bound to the symbol
+func (session *ExchangeSession) IndicatorSnapshot(symbol string) (IndicatorSnapshot, bool) {
+	set, ok := session.standardIndicatorSets[symbol]
+	if !ok {
+		return nil, false
+	}
+
+	return set.Snapshot(), true
+}
+
 func (session *ExchangeSession) StandardIndicatorSet(symbol string) *StandardIndicatorSet {
 	log.Warnf("StandardIndicatorSet() is deprecated in v1.49.0 and which will be removed in the next version, please use Indicators() instead")
 
@@ -590,12 +679,22 @@ func (session *ExchangeSession) MarketDataStore(symbol string) (s *MarketDataSto
 		return s, true
 	}
 
-	s = NewMarketDataStore(symbol)
+	s = session.newMarketDataStore(symbol)
 	s.BindStream(session.MarketDataStream)
 	session.marketDataStores[symbol] = s
 	return s, true
 }
 
+// newMarketDataStore creates the market data store of a symbol with the session kline retention cap
+func (session *ExchangeSession) newMarketDataStore(symbol string) *MarketDataStore {
+	store := NewMarketDataStore(symbol)
+	if session.MaxNumOfKLines > 0 {
+		store.SetMaxNumOfKLines(session.MaxNumOfKLines)
+	}
+
+	return store
+}
+
 // KLine updates will be received in the order listend in intervals array
 func (session *ExchangeSession) SerialMarketDataStore(
 	ctx context.Context, symbol string, intervals []types.Interval, useAggTrade ...bool,
@@ -632,6 +731,68 @@ func (session *ExchangeSession) OrderBook(symbol string) (s *types.StreamOrderBo
 	return s, ok
 }
 
+// exportedTrade is the trade record written by ExportTrades
+type exportedTrade struct {
+	Time        time.Time        `json:"time"`
+	Price       fixedpoint.Value `json:"price"`
+	Quantity    fixedpoint.Value `json:"quantity"`
+	Side        types.SideType   `json:"side"`
+	Fee         fixedpoint.Value `json:"fee"`
+	FeeCurrency string           `json:"feeCurrency"`
+}
+
+// ExportTrades writes the collected trades of the symbol to the writer,
+// the supported formats are "csv" and "json".
+func (session *ExchangeSession) ExportTrades(symbol string, w io.Writer, format string) error {
+	tradeSlice, ok := session.Trades[symbol]
+	if !ok {
+		return fmt.Errorf("trades of symbol %s not found in session %s", symbol, session.Name)
+	}
+
+	trades := tradeSlice.Copy()
+
+	switch strings.ToLower(format) {
+	case "csv":
+		writer := csv.NewWriter(w)
+		if err := writer.Write([]string{"time", "price", "quantity", "side", "fee", "fee_currency"}); err != nil {
+			return err
+		}
+
+		for _, trade := range trades {
+			if err := writer.Write([]string{
+				trade.Time.Time().Format(time.RFC3339Nano),
+				trade.Price.String(),
+				trade.Quantity.String(),
+				trade.Side.String(),
+				trade.Fee.String(),
+				trade.FeeCurrency,
+			}); err != nil {
+				return err
+			}
+		}
+
+		writer.Flush()
+		return writer.Error()
+
+	case "json":
+		records := make([]exportedTrade, 0, len(trades))
+		for _, trade := range trades {
+			records = append(records, exportedTrade{
+				Time:        trade.Time.Time(),
+				Price:       trade.Price,
+				Quantity:    trade.Quantity,
+				Side:        trade.Side,
+				Fee:         trade.Fee,
+				FeeCurrency: trade.FeeCurrency,
+			})
+		}
+
+		return json.NewEncoder(w).Encode(records)
+	}
+
+	return fmt.Errorf("unsupported trade export format: %s", format)
+}
+
 func (session *ExchangeSession) StartPrice(symbol string) (price fixedpoint.Value, ok bool) {
 	price, ok = session.startPrices[symbol]
 	return price, ok
@@ -642,6 +803,69 @@ func (session *ExchangeSession) LastPrice(symbol string) (price fixedpoint.Value
 	return price, ok
 }
 
+// SmoothedMidPrice returns the EMA-smoothed mid price of the symbol updated from the tickers
+func (session *ExchangeSession) SmoothedMidPrice(symbol string) (price fixedpoint.Value, ok bool) {
+	session.smoothedMidPricesMutex.Lock()
+	price, ok = session.smoothedMidPrices[symbol]
+	session.smoothedMidPricesMutex.Unlock()
+	return price, ok
+}
+
+// UpdateSmoothedMidPrice updates the smoothed mid price of the symbol with the mid price of the ticker
+func (session *ExchangeSession) UpdateSmoothedMidPrice(symbol string, ticker types.Ticker) fixedpoint.Value {
+	session.smoothedMidPricesMutex.Lock()
+	defer session.smoothedMidPricesMutex.Unlock()
+
+	if session.smoothedMidPrices == nil {
+		session.smoothedMidPrices = make(map[string]fixedpoint.Value)
+	}
+
+	mid := ticker.MidPrice()
+	if mid.IsZero() {
+		return session.smoothedMidPrices[symbol]
+	}
+
+	window := session.MidPriceSmoothingWindow
+	if window <= 0 {
+		window = defaultMidPriceSmoothingWindow
+	}
+
+	prev, ok := session.smoothedMidPrices[symbol]
+	if !ok || prev.IsZero() {
+		session.smoothedMidPrices[symbol] = mid
+		return mid
+	}
+
+	// ema = prev + alpha * (mid - prev), alpha = 2 / (window + 1)
+	alpha := fixedpoint.Two.Div(fixedpoint.NewFromInt(int64(window + 1)))
+	smoothed := prev.Add(mid.Sub(prev).Mul(alpha))
+	session.smoothedMidPrices[symbol] = smoothed
+	return smoothed
+}
+
+// bindSmoothedMidPrice updates the smoothed mid prices from the book ticker and the book snapshot of the stream
+func (session *ExchangeSession) bindSmoothedMidPrice(stream types.Stream) {
+	stream.OnBookTickerUpdate(func(bookTicker types.BookTicker) {
+		session.UpdateSmoothedMidPrice(bookTicker.Symbol, types.Ticker{
+			Buy:  bookTicker.Buy,
+			Sell: bookTicker.Sell,
+		})
+	})
+
+	stream.OnBookSnapshot(func(book types.SliceOrderBook) {
+		bid, hasBid := book.BestBid()
+		ask, hasAsk := book.BestAsk()
+		if !hasBid || !hasAsk {
+			return
+		}
+
+		session.UpdateSmoothedMidPrice(book.Symbol, types.Ticker{
+			Buy:  bid.Price,
+			Sell: ask.Price,
+		})
+	})
+}
+
 func (session *ExchangeSession) AllLastPrices() map[string]fixedpoint.Value {
 	return session.lastPrices
 }
@@ -672,8 +896,17 @@ func (session *ExchangeSession) OrderStores() map[string]*core.OrderStore {
 func (session *ExchangeSession) Subscribe(
 	channel types.Channel, symbol string, options types.SubscribeOptions,
 ) *ExchangeSession {
-	if channel == types.KLineChannel && len(options.Interval) == 0 {
-		panic("subscription interval for kline can not be empty")
+	if channel == types.KLineChannel {
+		if len(options.Interval) == 0 {
+			panic("subscription interval for kline can not be empty")
+		}
+
+		if _, ok := types.SupportedIntervals[options.Interval]; !ok {
+			supportedIntervals := types.SupportedIntervals.Slice()
+			supportedIntervals.Sort()
+			panic(fmt.Sprintf("unsupported kline subscription interval %q of %s, supported intervals: %s",
+				options.Interval, symbol, strings.Join(supportedIntervals.StringSlice(), ", ")))
+		}
 	}
 
 	sub := types.Subscription{
@@ -688,6 +921,70 @@ func (session *ExchangeSession) Subscribe(
 	return session
 }
 
+// Unsubscribe removes the subscription from the session. If the market data stream has subscribed the channel,
+// the stream sends the unsubscribe request when it supports types.ChannelUnsubscriber,
+// otherwise the stream is re-subscribed without it, which re-connects the stream.
+// Removing a subscription that does not exist is a no-op.
+func (session *ExchangeSession) Unsubscribe(
+	channel types.Channel, symbol string, options types.SubscribeOptions,
+) error {
+	sub := types.Subscription{
+		Channel: channel,
+		Symbol:  symbol,
+		Options: options,
+	}
+
+	if _, ok := session.Subscriptions[sub]; !ok {
+		log.Warnf("session %s: subscription %+v does not exist, skip unsubscribing", session.Name, sub)
+		return nil
+	}
+
+	delete(session.Subscriptions, sub)
+
+	// remove the symbol from the loaded symbol table if no subscription uses it
+	symbolUsed := false
+	for s := range session.Subscriptions {
+		if s.Symbol == symbol {
+			symbolUsed = true
+			break
+		}
+	}
+
+	if !symbolUsed {
+		delete(session.usedSymbols, symbol)
+	}
+
+	if session.MarketDataStream == nil {
+		return nil
+	}
+
+	subscribed := false
+	for _, s := range session.MarketDataStream.GetSubscriptions() {
+		if s == sub {
+			subscribed = true
+			break
+		}
+	}
+
+	if !subscribed {
+		return nil
+	}
+
+	if unsubscriber, ok := session.MarketDataStream.(types.ChannelUnsubscriber); ok {
+		return unsubscriber.UnsubscribeChannel(channel, symbol, options)
+	}
+
+	return session.MarketDataStream.Resubscribe(func(oldSubs []types.Subscription) ([]types.Subscription, error) {
+		var newSubs []types.Subscription
+		for _, s := range oldSubs {
+			if s != sub {
+				newSubs = append(newSubs, s)
+			}
+		}
+		return newSubs, nil
+	})
+}
+
 func (session *ExchangeSession) FormatOrder(order types.SubmitOrder) (types.SubmitOrder, error) {
 	market, ok := session.Market(order.Symbol)
 	if !ok {
@@ -734,6 +1031,8 @@ func (session *ExchangeSession) UpdatePrices(ctx context.Context, currencies []s
 			session.lastPrices[k] = v.Last
 		}
 
+		session.UpdateSmoothedMidPrice(k, v)
+
 		if v.Time.After(lastTime) {
 			lastTime = v.Time
 		}
@@ -1028,6 +1327,44 @@ func (session *ExchangeSession) FormatOrders(orders []types.SubmitOrder) (format
 	return formattedOrders, err
 }
 
+// applyTickSizeOverride returns a copy of the markets with the tick sizes replaced by the overrides,
+// the markets loaded from the cache might be shared, so they are not modified in place.
+func applyTickSizeOverride(markets types.MarketMap, overrides map[string]fixedpoint.Value) (types.MarketMap, error) {
+	newMarkets := make(types.MarketMap, len(markets))
+	for symbol, market := range markets {
+		newMarkets[symbol] = market
+	}
+
+	for symbol, tickSize := range overrides {
+		market, ok := newMarkets[symbol]
+		if !ok {
+			return nil, fmt.Errorf("tick size override: market %s not found", symbol)
+		}
+
+		if tickSize.Sign() <= 0 {
+			return nil, fmt.Errorf("tick size override: invalid tick size %s for %s", tickSize, symbol)
+		}
+
+		// the exchange tick size must be a multiple of the override tick size
+		if market.TickSize.Sign() > 0 {
+			ratio := market.TickSize.Div(tickSize).Round(0, fixedpoint.HalfUp)
+			if ratio.Sign() <= 0 || ratio.Mul(tickSize).Compare(market.TickSize) != 0 {
+				return nil, fmt.Errorf("tick size override: %s is not compatible with the %s exchange tick size %s", tickSize, symbol, market.TickSize)
+			}
+		}
+
+		market.TickSize = tickSize
+		// the tick size of 10 or more has negative fractional digits
+		market.PricePrecision = tickSize.NumFractionalDigits()
+		if market.PricePrecision < 0 {
+			market.PricePrecision = 0
+		}
+		newMarkets[symbol] = market
+	}
+
+	return newMarkets, nil
+}
+
 func findPossibleMarketSymbols(markets types.MarketMap, c, fiat string) (symbols []string) {
 	var tries []string
 	// expand USD stable coin currencies
@@ -1,10 +1,16 @@
 package bbgo
 
 import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/c9s/bbgo/pkg/fixedpoint"
 	"github.com/c9s/bbgo/pkg/types"
 )
 
@@ -42,3 +48,286 @@ func Test_findPossibleMarketSymbols(t *testing.T) {
 		}
 	})
 }
+
+func TestExchangeSession_ExportTrades(t *testing.T) {
+	session := &ExchangeSession{
+		Name:   "test",
+		Trades: make(map[string]*types.TradeSlice),
+	}
+
+	tradedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	session.Trades["BTCUSDT"] = &types.TradeSlice{Trades: []types.Trade{
+		{
+			Symbol:      "BTCUSDT",
+			Price:       fixedpoint.NewFromFloat(20000.0),
+			Quantity:    fixedpoint.NewFromFloat(0.1),
+			Side:        types.SideTypeBuy,
+			Fee:         fixedpoint.NewFromFloat(0.0001),
+			FeeCurrency: "BTC",
+			Time:        types.Time(tradedAt),
+		},
+		{
+			Symbol:      "BTCUSDT",
+			Price:       fixedpoint.NewFromFloat(21000.0),
+			Quantity:    fixedpoint.NewFromFloat(0.1),
+			Side:        types.SideTypeSell,
+			Fee:         fixedpoint.NewFromFloat(2.1),
+			FeeCurrency: "USDT",
+			Time:        types.Time(tradedAt.Add(time.Minute)),
+		},
+	}}
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := session.ExportTrades("BTCUSDT", &buf, "csv")
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		records, err := csv.NewReader(&buf).ReadAll()
+		if assert.NoError(t, err) && assert.Len(t, records, 3) {
+			assert.Equal(t, []string{"time", "price", "quantity", "side", "fee", "fee_currency"}, records[0])
+			assert.Equal(t, []string{"2024-01-02T03:04:05Z", "20000", "0.1", "BUY", "0.0001", "BTC"}, records[1])
+			assert.Equal(t, []string{"2024-01-02T03:05:05Z", "21000", "0.1", "SELL", "2.1", "USDT"}, records[2])
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := session.ExportTrades("BTCUSDT", &buf, "json")
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		var records []exportedTrade
+		err = json.Unmarshal(buf.Bytes(), &records)
+		if assert.NoError(t, err) && assert.Len(t, records, 2) {
+			assert.Equal(t, "20000", records[0].Price.String())
+			assert.Equal(t, types.SideTypeBuy, records[0].Side)
+			assert.Equal(t, "BTC", records[0].FeeCurrency)
+			assert.True(t, tradedAt.Equal(records[0].Time))
+			assert.Equal(t, "2.1", records[1].Fee.String())
+		}
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.Error(t, session.ExportTrades("BTCUSDT", &buf, "xml"))
+		assert.Error(t, session.ExportTrades("ETHUSDT", &buf, "csv"))
+	})
+}
+
+func TestExchangeSession_UpdateSmoothedMidPrice(t *testing.T) {
+	session := &ExchangeSession{
+		MidPriceSmoothingWindow: 3,
+	}
+
+	_, ok := session.SmoothedMidPrice("BTCUSDT")
+	assert.False(t, ok)
+
+	// the first mid price seeds the average
+	price := session.UpdateSmoothedMidPrice("BTCUSDT", types.Ticker{
+		Buy:  fixedpoint.NewFromFloat(99.0),
+		Sell: fixedpoint.NewFromFloat(101.0),
+	})
+	assert.Equal(t, "100", price.String())
+
+	// alpha = 2 / (3 + 1) = 0.5
+	price = session.UpdateSmoothedMidPrice("BTCUSDT", types.Ticker{
+		Buy:  fixedpoint.NewFromFloat(109.0),
+		Sell: fixedpoint.NewFromFloat(111.0),
+	})
+	assert.Equal(t, "105", price.String())
+
+	// missing ask falls back to the last price
+	price = session.UpdateSmoothedMidPrice("BTCUSDT", types.Ticker{
+		Last: fixedpoint.NewFromFloat(115.0),
+		Buy:  fixedpoint.NewFromFloat(114.0),
+	})
+	assert.Equal(t, "110", price.String())
+
+	smoothed, ok := session.SmoothedMidPrice("BTCUSDT")
+	assert.True(t, ok)
+	assert.Equal(t, "110", smoothed.String())
+}
+
+func Test_applyTickSizeOverride(t *testing.T) {
+	markets := types.MarketMap{
+		"BTCUSDT": types.Market{
+			Symbol:         "BTCUSDT",
+			PricePrecision: 1,
+			TickSize:       fixedpoint.NewFromFloat(0.1),
+		},
+		"ETHUSDT": types.Market{
+			Symbol:         "ETHUSDT",
+			PricePrecision: 2,
+			TickSize:       fixedpoint.NewFromFloat(0.01),
+		},
+	}
+
+	t.Run("applied", func(t *testing.T) {
+		newMarkets, err := applyTickSizeOverride(markets, map[string]fixedpoint.Value{
+			"BTCUSDT": fixedpoint.NewFromFloat(0.05),
+		})
+		if assert.NoError(t, err) {
+			assert.Equal(t, "0.05", newMarkets["BTCUSDT"].TickSize.String())
+			assert.Equal(t, 2, newMarkets["BTCUSDT"].PricePrecision)
+			assert.Equal(t, "0.01", newMarkets["ETHUSDT"].TickSize.String())
+		}
+
+		// the original markets are not modified
+		assert.Equal(t, "0.1", markets["BTCUSDT"].TickSize.String())
+	})
+
+	t.Run("integer tick size", func(t *testing.T) {
+		newMarkets, err := applyTickSizeOverride(types.MarketMap{
+			"BTCJPY": types.Market{Symbol: "BTCJPY", TickSize: fixedpoint.NewFromInt(100)},
+		}, map[string]fixedpoint.Value{
+			"BTCJPY": fixedpoint.NewFromInt(10),
+		})
+		if assert.NoError(t, err) {
+			assert.Equal(t, "10", newMarkets["BTCJPY"].TickSize.String())
+			assert.Equal(t, 0, newMarkets["BTCJPY"].PricePrecision)
+		}
+	})
+
+	t.Run("incompatible", func(t *testing.T) {
+		_, err := applyTickSizeOverride(markets, map[string]fixedpoint.Value{
+			"BTCUSDT": fixedpoint.NewFromFloat(0.03),
+		})
+		assert.Error(t, err)
+
+		_, err = applyTickSizeOverride(markets, map[string]fixedpoint.Value{
+			"ETHUSDT": fixedpoint.NewFromFloat(0.02),
+		})
+		assert.Error(t, err, "coarser tick size is not an integer fraction of the exchange tick")
+	})
+
+	t.Run("unknown symbol", func(t *testing.T) {
+		_, err := applyTickSizeOverride(markets, map[string]fixedpoint.Value{
+			"XRPUSDT": fixedpoint.NewFromFloat(0.001),
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestExchangeSession_Subscribe_KLineInterval(t *testing.T) {
+	session := &ExchangeSession{
+		Subscriptions: make(map[types.Subscription]types.Subscription),
+		usedSymbols:   make(map[string]struct{}),
+	}
+
+	session.Subscribe(types.KLineChannel, "BTCUSDT", types.SubscribeOptions{Interval: types.Interval5m})
+	assert.Len(t, session.Subscriptions, 1)
+
+	assert.PanicsWithValue(t, "subscription interval for kline can not be empty", func() {
+		session.Subscribe(types.KLineChannel, "BTCUSDT", types.SubscribeOptions{})
+	})
+
+	assert.Panics(t, func() {
+		session.Subscribe(types.KLineChannel, "BTCUSDT", types.SubscribeOptions{Interval: "7m"})
+	})
+	assert.Len(t, session.Subscriptions, 1)
+
+	// the interval is not required by the other channels
+	session.Subscribe(types.BookChannel, "BTCUSDT", types.SubscribeOptions{Depth: types.DepthLevelFull})
+	assert.Len(t, session.Subscriptions, 2)
+}
+
+func TestExchangeSession_Unsubscribe(t *testing.T) {
+	stream := types.NewStandardStream()
+	session := &ExchangeSession{
+		Subscriptions:    make(map[types.Subscription]types.Subscription),
+		usedSymbols:      make(map[string]struct{}),
+		MarketDataStream: &stream,
+	}
+
+	klineOptions := types.SubscribeOptions{Interval: types.Interval1m}
+	session.Subscribe(types.KLineChannel, "BTCUSDT", klineOptions)
+	session.Subscribe(types.KLineChannel, "ETHUSDT", klineOptions)
+	assert.Len(t, session.Subscriptions, 2)
+
+	// the subscriptions are copied to the stream on connect
+	for _, sub := range session.Subscriptions {
+		stream.Subscribe(sub.Channel, sub.Symbol, sub.Options)
+	}
+
+	if assert.NoError(t, session.Unsubscribe(types.KLineChannel, "BTCUSDT", klineOptions)) {
+		assert.Len(t, session.Subscriptions, 1)
+		assert.NotContains(t, session.Subscriptions, types.Subscription{Channel: types.KLineChannel, Symbol: "BTCUSDT", Options: klineOptions})
+		assert.NotContains(t, session.usedSymbols, "BTCUSDT")
+		assert.Contains(t, session.usedSymbols, "ETHUSDT")
+
+		assert.Equal(t, []types.Subscription{
+			{Channel: types.KLineChannel, Symbol: "ETHUSDT", Options: klineOptions},
+		}, stream.GetSubscriptions())
+
+		// the stream re-connects to apply the new subscriptions
+		select {
+		case <-stream.ReconnectC:
+		default:
+			t.Error("the stream should be re-connected")
+		}
+	}
+
+	// unsubscribing a subscription that does not exist is a no-op
+	assert.NoError(t, session.Unsubscribe(types.KLineChannel, "BTCUSDT", klineOptions))
+	assert.NoError(t, session.Unsubscribe(types.BookChannel, "ETHUSDT", types.SubscribeOptions{}))
+	assert.Len(t, session.Subscriptions, 1)
+	assert.Len(t, stream.GetSubscriptions(), 1)
+}
+
+func TestExchangeSession_bindSmoothedMidPrice(t *testing.T) {
+	session := &ExchangeSession{
+		MidPriceSmoothingWindow: 3,
+	}
+
+	stream := types.NewStandardStream()
+	session.bindSmoothedMidPrice(&stream)
+
+	stream.EmitBookTickerUpdate(types.BookTicker{
+		Symbol: "BTCUSDT",
+		Buy:    fixedpoint.NewFromFloat(99.0),
+		Sell:   fixedpoint.NewFromFloat(101.0),
+	})
+
+	price, ok := session.SmoothedMidPrice("BTCUSDT")
+	if assert.True(t, ok) {
+		assert.Equal(t, "100", price.String())
+	}
+
+	stream.EmitBookSnapshot(types.SliceOrderBook{
+		Symbol: "BTCUSDT",
+		Bids:   types.PriceVolumeSlice{{Price: fixedpoint.NewFromFloat(109.0), Volume: fixedpoint.One}},
+		Asks:   types.PriceVolumeSlice{{Price: fixedpoint.NewFromFloat(111.0), Volume: fixedpoint.One}},
+	})
+
+	price, ok = session.SmoothedMidPrice("BTCUSDT")
+	if assert.True(t, ok) {
+		assert.Equal(t, "105", price.String())
+	}
+
+	// the book ticker callback and the readers can run concurrently
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			stream.EmitBookTickerUpdate(types.BookTicker{
+				Symbol: "BTCUSDT",
+				Buy:    fixedpoint.NewFromFloat(104.0),
+				Sell:   fixedpoint.NewFromFloat(106.0),
+			})
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			session.SmoothedMidPrice("BTCUSDT")
+		}
+	}()
+	wg.Wait()
+
+	price, _ = session.SmoothedMidPrice("BTCUSDT")
+	assert.Equal(t, "105", price.String())
+}
@@ -1,6 +1,11 @@
 package bbgo
 
 import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sync"
+
 	"github.com/sirupsen/logrus"
 
 	"github.com/c9s/bbgo/pkg/indicator"
@@ -33,6 +38,12 @@ type StandardIndicatorSet struct {
 
 	stream types.Stream
 	store  *MarketDataStore
+
+	// mu protects the indicator maps and the recorded snapshot values.
+	// the indicators themselves are only updated from the stream callback,
+	// hence PushK and the indicator callbacks run without holding the lock.
+	mu       sync.Mutex
+	snapshot IndicatorSnapshot
 }
 
 type indicatorKey struct {
@@ -48,20 +59,36 @@ func NewStandardIndicatorSet(symbol string, stream types.Stream, store *MarketDa
 		iwIndicators:   make(map[indicatorKey]indicator.KLinePusher),
 		iwbIndicators:  make(map[types.IntervalWindowBandWidth]*indicator.BOLL),
 		macdIndicators: make(map[indicator.MACDConfig]*indicator.MACDLegacy),
+		snapshot:       make(IndicatorSnapshot),
 	}
 }
 
-func (s *StandardIndicatorSet) initAndBind(inc indicator.KLinePusher, interval types.Interval) {
+// initAndBind preloads the indicator from the stored klines and binds it to the stream.
+// record writes the latest indicator values into the snapshot, it's called with s.mu held.
+// initAndBind must be called with s.mu held.
+func (s *StandardIndicatorSet) initAndBind(inc indicator.KLinePusher, interval types.Interval, record func(snapshot IndicatorSnapshot)) {
 	if klines, ok := s.store.KLinesOfInterval(interval); ok {
 		for _, k := range *klines {
 			inc.PushK(k)
 		}
 	}
 
-	s.stream.OnKLineClosed(types.KLineWith(s.Symbol, interval, inc.PushK))
+	record(s.snapshot)
+
+	s.stream.OnKLineClosed(types.KLineWith(s.Symbol, interval, func(k types.KLine) {
+		// do not hold the lock here, the update callbacks of the indicator may call back into the set
+		inc.PushK(k)
+
+		s.mu.Lock()
+		record(s.snapshot)
+		s.mu.Unlock()
+	}))
 }
 
 func (s *StandardIndicatorSet) allocateSimpleIndicator(t indicator.KLinePusher, iw types.IntervalWindow, id string) indicator.KLinePusher {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	k := indicatorKey{
 		iw: iw,
 		id: id,
@@ -72,7 +99,15 @@ func (s *StandardIndicatorSet) allocateSimpleIndicator(t indicator.KLinePusher,
 	}
 
 	inc = t
-	s.initAndBind(inc, iw.Interval)
+	key := fmt.Sprintf("%s(%d)", id, iw.Window)
+	s.initAndBind(inc, iw.Interval, func(snapshot IndicatorSnapshot) {
+		reader, ok := inc.(lastValueReader)
+		if !ok || reader.Length() == 0 {
+			return
+		}
+
+		snapshot.set(iw.Interval, key, reader.Last(0))
+	})
 	s.iwIndicators[k] = inc
 	return t
 }
@@ -137,11 +172,23 @@ func (s *StandardIndicatorSet) STOCH(iw types.IntervalWindow) *indicator.STOCH {
 
 // BOLL returns the bollinger band indicator of the given interval, the window and bandwidth
 func (s *StandardIndicatorSet) BOLL(iw types.IntervalWindow, bandWidth float64) *indicator.BOLL {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	iwb := types.IntervalWindowBandWidth{IntervalWindow: iw, BandWidth: bandWidth}
 	inc, ok := s.iwbIndicators[iwb]
 	if !ok {
 		inc = &indicator.BOLL{IntervalWindow: iw, K: bandWidth, SMA: &indicator.SMA{IntervalWindow: iw}}
-		s.initAndBind(inc, iw.Interval)
+		key := fmt.Sprintf("boll(%d,%g)", iw.Window, bandWidth)
+		s.initAndBind(inc, iw.Interval, func(snapshot IndicatorSnapshot) {
+			if len(inc.UpBand) == 0 {
+				return
+			}
+
+			snapshot.set(iw.Interval, key+".sma", inc.SMA.Last(0))
+			snapshot.set(iw.Interval, key+".up", inc.LastUpBand())
+			snapshot.set(iw.Interval, key+".down", inc.LastDownBand())
+		})
 
 		if debugBOLL {
 			inc.OnUpdate(func(sma float64, upBand float64, downBand float64) {
@@ -155,6 +202,9 @@ func (s *StandardIndicatorSet) BOLL(iw types.IntervalWindow, bandWidth float64)
 }
 
 func (s *StandardIndicatorSet) MACD(iw types.IntervalWindow, shortPeriod, longPeriod int) *indicator.MACDLegacy {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	config := indicator.MACDConfig{IntervalWindow: iw, ShortPeriod: shortPeriod, LongPeriod: longPeriod}
 
 	inc, ok := s.macdIndicators[config]
@@ -164,7 +214,15 @@ func (s *StandardIndicatorSet) MACD(iw types.IntervalWindow, shortPeriod, longPe
 
 	inc = &indicator.MACDLegacy{MACDConfig: config}
 	s.macdIndicators[config] = inc
-	s.initAndBind(inc, config.IntervalWindow.Interval)
+
+	key := fmt.Sprintf("macd(%d,%d,%d)", iw.Window, shortPeriod, longPeriod)
+	s.initAndBind(inc, config.IntervalWindow.Interval, func(snapshot IndicatorSnapshot) {
+		if inc.Length() == 0 {
+			return
+		}
+
+		snapshot.set(iw.Interval, key, inc.Last(0))
+	})
 	return inc
 }
 
@@ -185,3 +243,54 @@ func (s *StandardIndicatorSet) KalmanFilter(iw types.IntervalWindow) *indicator.
 	inc := s.allocateSimpleIndicator(&indicator.KalmanFilter{IntervalWindow: iw, AdditionalSmoothWindow: 0}, iw, "kalmanfilter")
 	return inc.(*indicator.KalmanFilter)
 }
+
+// IndicatorValue is a float64 indicator value which is encoded as null in JSON when it's NaN or infinite,
+// since encoding/json can not encode these values.
+type IndicatorValue float64
+
+func (v IndicatorValue) MarshalJSON() ([]byte, error) {
+	f := float64(v)
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(f)
+}
+
+// IndicatorSnapshot is the latest values of the indicators, keyed by the interval and the indicator name with its window,
+// e.g. {"1m": {"sma(7)": 100.0, "boll(20,2).up": 110.0}}
+type IndicatorSnapshot map[types.Interval]map[string]IndicatorValue
+
+func (snapshot IndicatorSnapshot) set(interval types.Interval, key string, value float64) {
+	values, ok := snapshot[interval]
+	if !ok {
+		values = make(map[string]IndicatorValue)
+		snapshot[interval] = values
+	}
+
+	values[key] = IndicatorValue(value)
+}
+
+type lastValueReader interface {
+	Last(i int) float64
+	Length() int
+}
+
+// Snapshot returns a copy of the latest values of the allocated indicators,
+// the indicators without any value are skipped.
+func (s *StandardIndicatorSet) Snapshot() IndicatorSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot := make(IndicatorSnapshot, len(s.snapshot))
+	for interval, values := range s.snapshot {
+		copied := make(map[string]IndicatorValue, len(values))
+		for key, value := range values {
+			copied[key] = value
+		}
+
+		snapshot[interval] = copied
+	}
+
+	return snapshot
+}
@@ -0,0 +1,126 @@
+package bbgo
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestStandardIndicatorSet_Snapshot(t *testing.T) {
+	symbol := "BTCUSDT"
+	store := NewMarketDataStore(symbol)
+	stream := types.NewStandardStream()
+	set := NewStandardIndicatorSet(symbol, &stream, store)
+
+	iw := types.IntervalWindow{Interval: types.Interval1m, Window: 3}
+	set.SMA(iw)
+	set.EWMA(types.IntervalWindow{Interval: types.Interval5m, Window: 3})
+	set.BOLL(iw, 2.0)
+
+	// indicators without values are not included
+	assert.Empty(t, set.Snapshot())
+
+	for _, price := range []float64{100.0, 101.0, 102.0, 103.0} {
+		stream.EmitKLineClosed(types.KLine{
+			Symbol:   symbol,
+			Interval: types.Interval1m,
+			Close:    number(price),
+			Closed:   true,
+		})
+	}
+
+	data, err := json.Marshal(set.Snapshot())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var snapshot map[string]map[string]float64
+	if !assert.NoError(t, json.Unmarshal(data, &snapshot)) {
+		return
+	}
+
+	values, ok := snapshot["1m"]
+	if assert.True(t, ok) {
+		assert.InDelta(t, 102.0, values["sma(3)"], 1e-9)
+		assert.InDelta(t, 102.0, values["boll(3,2).sma"], 1e-9)
+		assert.Greater(t, values["boll(3,2).up"], 102.0)
+		assert.Less(t, values["boll(3,2).down"], 102.0)
+	}
+
+	_, ok = snapshot["5m"]
+	assert.False(t, ok, "5m indicator has no kline pushed")
+}
+
+func TestStandardIndicatorSet_BOLL_BandWidth(t *testing.T) {
+	symbol := "BTCUSDT"
+	store := NewMarketDataStore(symbol)
+	stream := types.NewStandardStream()
+	set := NewStandardIndicatorSet(symbol, &stream, store)
+
+	iw := types.IntervalWindow{Interval: types.Interval1m, Window: 3}
+	boll2 := set.BOLL(iw, 2.0)
+	boll25 := set.BOLL(iw, 2.5)
+	assert.NotSame(t, boll2, boll25)
+	assert.Equal(t, 2.0, boll2.K)
+	assert.Equal(t, 2.5, boll25.K)
+	assert.Same(t, boll2, set.BOLL(iw, 2.0), "the same bandwidth should return the cached indicator")
+
+	for _, price := range []float64{100.0, 101.0, 102.0, 103.0} {
+		stream.EmitKLineClosed(types.KLine{
+			Symbol:   symbol,
+			Interval: types.Interval1m,
+			Close:    number(price),
+			Closed:   true,
+		})
+	}
+
+	assert.InDelta(t, boll2.SMA.Last(0), boll25.SMA.Last(0), 1e-9)
+
+	width2 := boll2.UpBand.Last(0) - boll2.DownBand.Last(0)
+	width25 := boll25.UpBand.Last(0) - boll25.DownBand.Last(0)
+	assert.Greater(t, width2, 0.0)
+	assert.InDelta(t, width2*2.5/2.0, width25, 1e-9)
+}
+
+func TestStandardIndicatorSet_UpdateCallback(t *testing.T) {
+	symbol := "BTCUSDT"
+	store := NewMarketDataStore(symbol)
+	stream := types.NewStandardStream()
+	set := NewStandardIndicatorSet(symbol, &stream, store)
+
+	iw := types.IntervalWindow{Interval: types.Interval1m, Window: 3}
+	var snapshots []IndicatorSnapshot
+	set.SMA(iw).OnUpdate(func(value float64) {
+		// the callback can call back into the set without a deadlock
+		set.EWMA(iw)
+		snapshots = append(snapshots, set.Snapshot())
+	})
+
+	for _, price := range []float64{100.0, 101.0, 102.0, 103.0} {
+		stream.EmitKLineClosed(types.KLine{
+			Symbol:   symbol,
+			Interval: types.Interval1m,
+			Close:    number(price),
+			Closed:   true,
+		})
+	}
+
+	assert.NotEmpty(t, snapshots)
+	assert.InDelta(t, 102.0, float64(set.Snapshot()[types.Interval1m]["sma(3)"]), 1e-9)
+}
+
+func TestIndicatorSnapshot_MarshalJSON(t *testing.T) {
+	snapshot := make(IndicatorSnapshot)
+	snapshot.set(types.Interval1m, "sma(3)", 100.0)
+	snapshot.set(types.Interval1m, "ewma(3)", math.NaN())
+	snapshot.set(types.Interval1m, "atr(3)", math.Inf(1))
+
+	data, err := json.Marshal(snapshot)
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{"1m": {"sma(3)": 100, "ewma(3)": null, "atr(3)": null}}`, string(data))
+	}
+}
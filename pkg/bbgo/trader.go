@@ -326,6 +326,9 @@ func (trader *Trader) Run(ctx context.Context) error {
 	// trader.environment.Connect will call interact.Start
 	interact.AddCustomInteraction(NewCoreInteraction(trader.environment, trader))
 
+	// the strategies are stopped by the environment shutdown as well
+	ctx = trader.environment.withShutdown(ctx)
+
 	if err := trader.injectFieldsAndSubscribe(ctx); err != nil {
 		return err
 	}
@@ -0,0 +1,132 @@
+package bbgo
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var ErrOutsideTradingWindow = errors.New("outside of the trading window")
+
+// TimeOfDay is the time of a day in UTC, it's parsed from the "15:04" format
+type TimeOfDay struct {
+	Hour, Minute int
+}
+
+func (t TimeOfDay) minutes() int {
+	return t.Hour*60 + t.Minute
+}
+
+func (t TimeOfDay) String() string {
+	return fmt.Sprintf("%02d:%02d", t.Hour, t.Minute)
+}
+
+func (t TimeOfDay) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+func (t *TimeOfDay) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	tt, err := time.Parse("15:04", s)
+	if err != nil {
+		return fmt.Errorf("invalid time of day %q, valid format: HH:MM", s)
+	}
+
+	t.Hour, t.Minute = tt.Hour(), tt.Minute()
+	return nil
+}
+
+// TimeOfDayRange is a time range of a day, the range spans midnight when End is before Start.
+// The End time is excluded.
+type TimeOfDayRange struct {
+	Start TimeOfDay `json:"start"`
+	End   TimeOfDay `json:"end"`
+}
+
+// Weekday is the time.Weekday that can be parsed from the weekday name, e.g. "monday" or "mon"
+type Weekday time.Weekday
+
+func (d Weekday) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strings.ToLower(time.Weekday(d).String()))
+}
+
+func (d *Weekday) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	s = strings.ToLower(s)
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		name := strings.ToLower(wd.String())
+		if s == name || s == name[:3] {
+			*d = Weekday(wd)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid weekday %q", s)
+}
+
+// TradingWindow defines the allowed trading hours in UTC.
+// When Weekdays is given, a time range is only open on the listed weekdays,
+// for the range spans midnight, the weekday is the day the range starts.
+type TradingWindow struct {
+	Ranges   []TimeOfDayRange `json:"ranges"`
+	Weekdays []Weekday        `json:"weekdays,omitempty"`
+}
+
+// IsOpen returns true if the given time is inside the trading window
+func (w *TradingWindow) IsOpen(t time.Time) bool {
+	t = t.UTC()
+	minutes := t.Hour()*60 + t.Minute()
+
+	for _, r := range w.Ranges {
+		start, end := r.Start.minutes(), r.End.minutes()
+		switch {
+		case start == end:
+			// the whole day
+			if w.allowWeekday(t.Weekday()) {
+				return true
+			}
+
+		case start < end:
+			if minutes >= start && minutes < end && w.allowWeekday(t.Weekday()) {
+				return true
+			}
+
+		default:
+			// the range spans midnight
+			if minutes >= start && w.allowWeekday(t.Weekday()) {
+				return true
+			}
+
+			if minutes < end && w.allowWeekday(t.AddDate(0, 0, -1).Weekday()) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (w *TradingWindow) allowWeekday(wd time.Weekday) bool {
+	if len(w.Weekdays) == 0 {
+		return true
+	}
+
+	for _, d := range w.Weekdays {
+		if time.Weekday(d) == wd {
+			return true
+		}
+	}
+
+	return false
+}
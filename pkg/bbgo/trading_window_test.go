@@ -0,0 +1,204 @@
+package bbgo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/types/mocks"
+)
+
+func TestTradingWindow_Overnight(t *testing.T) {
+	var window TradingWindow
+	err := json.Unmarshal([]byte(`{"ranges": [{"start": "22:00", "end": "02:30"}]}`), &window)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// 2024-01-01 is Monday
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.True(t, window.IsOpen(day.Add(22*time.Hour)))
+	assert.True(t, window.IsOpen(day.Add(23*time.Hour+59*time.Minute)))
+	assert.True(t, window.IsOpen(day.Add(24*time.Hour)))
+	assert.True(t, window.IsOpen(day.Add(26*time.Hour+29*time.Minute)))
+	assert.False(t, window.IsOpen(day.Add(26*time.Hour+30*time.Minute)))
+	assert.False(t, window.IsOpen(day.Add(12*time.Hour)))
+	assert.False(t, window.IsOpen(day.Add(21*time.Hour+59*time.Minute)))
+
+	// non-UTC time is converted to UTC
+	taipei := time.FixedZone("Asia/Taipei", 8*60*60)
+	assert.True(t, window.IsOpen(time.Date(2024, 1, 2, 6, 0, 0, 0, taipei)))
+}
+
+func TestTradingWindow_Weekdays(t *testing.T) {
+	var window TradingWindow
+	err := json.Unmarshal([]byte(`{
+		"ranges": [{"start": "08:00", "end": "16:00"}, {"start": "23:00", "end": "01:00"}],
+		"weekdays": ["monday", "fri"]
+	}`), &window)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	monday := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tuesday := monday.AddDate(0, 0, 1)
+	friday := monday.AddDate(0, 0, 4)
+	saturday := monday.AddDate(0, 0, 5)
+	sunday := monday.AddDate(0, 0, 6)
+
+	assert.True(t, window.IsOpen(monday.Add(8*time.Hour)))
+	assert.False(t, window.IsOpen(tuesday.Add(8*time.Hour)))
+	assert.True(t, window.IsOpen(friday.Add(15*time.Hour)))
+	assert.False(t, window.IsOpen(saturday.Add(10*time.Hour)))
+
+	// the overnight range follows the weekday it starts
+	assert.True(t, window.IsOpen(tuesday.Add(30*time.Minute)))
+	assert.True(t, window.IsOpen(saturday.Add(30*time.Minute)))
+	assert.False(t, window.IsOpen(sunday.Add(30*time.Minute)))
+	assert.False(t, window.IsOpen(monday.Add(30*time.Minute)))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"weekdays": ["someday"]}`), &window))
+	assert.Error(t, json.Unmarshal([]byte(`{"ranges": [{"start": "25:00"}]}`), &window))
+}
+
+func TestGeneralOrderExecutor_TradingWindow(t *testing.T) {
+	market := getTestMarket()
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+
+	session := NewExchangeSession("test", mockEx)
+	session.markets[market.Symbol] = market
+
+	position := types.NewPositionFromMarket(market)
+	orderExecutor := NewGeneralOrderExecutor(session, "BTCUSDT", "test", "test-01", position)
+
+	// a window without any range is always closed
+	orderExecutor.SetTradingWindow(&TradingWindow{})
+
+	_, err := orderExecutor.SubmitOrders(context.Background(), types.SubmitOrder{
+		Symbol:   "BTCUSDT",
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimit,
+		Market:   market,
+		Price:    fixedpoint.NewFromFloat(20000.0),
+		Quantity: fixedpoint.One,
+	})
+	assert.True(t, errors.Is(err, ErrOutsideTradingWindow))
+}
+
+func TestGeneralOrderExecutor_TradingWindow_ReducingOrders(t *testing.T) {
+	market := getTestMarket()
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+
+	session := NewExchangeSession("test", mockEx)
+	session.markets[market.Symbol] = market
+
+	position := types.NewPositionFromMarket(market)
+	position.AverageCost = fixedpoint.NewFromFloat(20000.0)
+	position.Base = fixedpoint.One
+
+	orderExecutor := NewGeneralOrderExecutor(session, "BTCUSDT", "test", "test-01", position)
+	orderExecutor.SetTradingWindow(&TradingWindow{})
+
+	newOrder := func(side types.SideType, quantity fixedpoint.Value) types.SubmitOrder {
+		return types.SubmitOrder{
+			Symbol:   "BTCUSDT",
+			Side:     side,
+			Type:     types.OrderTypeStopLimit,
+			Market:   market,
+			Price:    fixedpoint.NewFromFloat(19900.0),
+			Quantity: quantity,
+		}
+	}
+
+	ctx := context.Background()
+
+	// the protective stop order reduces the position, it's allowed outside the trading window
+	stopOrder := newOrder(types.SideTypeSell, fixedpoint.MustNewFromString("0.5"))
+	mockEx.EXPECT().SubmitOrder(gomock.Any(), stopOrder).Return(&types.Order{SubmitOrder: stopOrder}, nil)
+	_, err := orderExecutor.SubmitOrders(ctx, stopOrder)
+	assert.NoError(t, err)
+
+	// the reduce-only order is allowed
+	reduceOnlyOrder := newOrder(types.SideTypeBuy, fixedpoint.One)
+	reduceOnlyOrder.ReduceOnly = true
+	mockEx.EXPECT().SubmitOrder(gomock.Any(), reduceOnlyOrder).Return(&types.Order{SubmitOrder: reduceOnlyOrder}, nil)
+	_, err = orderExecutor.SubmitOrders(ctx, reduceOnlyOrder)
+	assert.NoError(t, err)
+
+	// flipping the position is not reducing
+	_, err = orderExecutor.SubmitOrders(ctx, newOrder(types.SideTypeSell, fixedpoint.NewFromFloat(1.5)))
+	assert.True(t, errors.Is(err, ErrOutsideTradingWindow))
+
+	_, err = orderExecutor.SubmitOrders(ctx, newOrder(types.SideTypeBuy, fixedpoint.One))
+	assert.True(t, errors.Is(err, ErrOutsideTradingWindow))
+}
+
+func TestGeneralOrderExecutor_TradingWindow_BackTest(t *testing.T) {
+	IsBackTesting = true
+	defer func() {
+		IsBackTesting = false
+	}()
+
+	market := getTestMarket()
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+
+	session := NewExchangeSession("test", mockEx)
+	session.markets[market.Symbol] = market
+
+	position := types.NewPositionFromMarket(market)
+	orderExecutor := NewGeneralOrderExecutor(session, "BTCUSDT", "test", "test-01", position)
+	orderExecutor.SetTradingWindow(&TradingWindow{
+		Ranges: []TimeOfDayRange{{Start: TimeOfDay{Hour: 9}, End: TimeOfDay{Hour: 10}}},
+	})
+
+	order := types.SubmitOrder{
+		Symbol:   "BTCUSDT",
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimit,
+		Market:   market,
+		Price:    fixedpoint.NewFromFloat(20000.0),
+		Quantity: fixedpoint.One,
+	}
+
+	emitKLine := func(endTime time.Time) {
+		session.MarketDataStream.(*types.StandardStream).EmitKLineClosed(types.KLine{
+			Symbol:   "BTCUSDT",
+			Interval: types.Interval1m,
+			EndTime:  types.Time(endTime),
+		})
+	}
+
+	ctx := context.Background()
+
+	// the trading window is checked against the kline time instead of the wall clock
+	emitKLine(time.Date(2024, 1, 1, 9, 30, 0, 0, time.UTC))
+	mockEx.EXPECT().SubmitOrder(gomock.Any(), order).Return(&types.Order{SubmitOrder: order}, nil)
+	_, err := orderExecutor.SubmitOrders(ctx, order)
+	assert.NoError(t, err)
+
+	emitKLine(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	_, err = orderExecutor.SubmitOrders(ctx, order)
+	assert.True(t, errors.Is(err, ErrOutsideTradingWindow))
+}
@@ -0,0 +1,85 @@
+package bbgo
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/indicator"
+)
+
+var ErrZeroATR = errors.New("atr is zero, the volatility is not ready")
+
+// VolatilitySizer sizes the order quantity inversely proportional to the recent volatility (ATR),
+// so that an ATR move of each position risks the same fraction of the equity.
+//
+// The quantity is computed as riskBudget / atr, where riskBudget = equity * RiskRatio,
+// which is equivalent to riskBudget / (atrp * price) with the normalized ATR atrp = atr / price.
+// The equity is the total balance of the quote currency in the session.
+type VolatilitySizer struct {
+	// RiskRatio is the fraction of the equity to risk per ATR, e.g. 0.01 means risking 1% of the equity per ATR move
+	RiskRatio fixedpoint.Value `json:"riskRatio"`
+
+	session *ExchangeSession
+	atrs    map[string]*indicator.ATR
+}
+
+func (s *VolatilitySizer) Validate() error {
+	if s.RiskRatio.Sign() <= 0 {
+		return fmt.Errorf("volatilitySizer: riskRatio should be positive")
+	}
+
+	return nil
+}
+
+// Bind binds the sizer to the session for querying the markets, the balances and the last prices
+func (s *VolatilitySizer) Bind(session *ExchangeSession) {
+	s.session = session
+}
+
+// BindATR binds the ATR indicator of the given symbol, the ATR should be updated by the kline stream
+func (s *VolatilitySizer) BindATR(symbol string, atr *indicator.ATR) {
+	if s.atrs == nil {
+		s.atrs = make(map[string]*indicator.ATR)
+	}
+
+	s.atrs[symbol] = atr
+}
+
+// QuantityFor returns the order quantity of the given symbol with the current ATR and the session balance,
+// the quantity is capped to the quantity that the equity can buy at the last price.
+func (s *VolatilitySizer) QuantityFor(symbol string) (fixedpoint.Value, error) {
+	if s.session == nil {
+		return fixedpoint.Zero, errors.New("volatilitySizer: session is not bound")
+	}
+
+	market, ok := s.session.Market(symbol)
+	if !ok {
+		return fixedpoint.Zero, fmt.Errorf("volatilitySizer: market %s not found", symbol)
+	}
+
+	atrIndicator, ok := s.atrs[symbol]
+	if !ok {
+		return fixedpoint.Zero, fmt.Errorf("volatilitySizer: atr of %s is not bound", symbol)
+	}
+
+	atr := fixedpoint.NewFromFloat(atrIndicator.Last(0))
+	if atr.Sign() <= 0 {
+		return fixedpoint.Zero, ErrZeroATR
+	}
+
+	balance, ok := s.session.GetAccount().Balance(market.QuoteCurrency)
+	if !ok {
+		return fixedpoint.Zero, fmt.Errorf("volatilitySizer: %s balance not found", market.QuoteCurrency)
+	}
+
+	equity := balance.Total()
+	riskBudget := equity.Mul(s.RiskRatio)
+	quantity := riskBudget.Div(atr)
+
+	if price, ok := s.session.LastPrice(symbol); ok && price.Sign() > 0 {
+		quantity = fixedpoint.Min(quantity, equity.Div(price))
+	}
+
+	return quantity, nil
+}
@@ -0,0 +1,62 @@
+package bbgo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/indicator"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/types/mocks"
+)
+
+func TestVolatilitySizer_QuantityFor(t *testing.T) {
+	market := getTestMarket()
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().NewStream().Return(&types.StandardStream{}).Times(2)
+
+	session := NewExchangeSession("test", mockEx)
+	session.markets[market.Symbol] = market
+	session.Account.UpdateBalances(types.BalanceMap{
+		"USDT": {Currency: "USDT", Available: fixedpoint.NewFromFloat(10000.0)},
+	})
+
+	sizer := &VolatilitySizer{RiskRatio: fixedpoint.MustNewFromString("0.01")}
+	sizer.Bind(session)
+
+	atr := &indicator.ATR{IntervalWindow: types.IntervalWindow{Interval: types.Interval1h, Window: 3}}
+	sizer.BindATR("BTCUSDT", atr)
+
+	// the ATR is not ready yet
+	_, err := sizer.QuantityFor("BTCUSDT")
+	assert.True(t, errors.Is(err, ErrZeroATR))
+
+	// the true range is 100 for each kline, so the ATR is 100
+	for i := 0; i < 5; i++ {
+		atr.Update(20050.0, 19950.0, 20000.0)
+	}
+	assert.InDelta(t, 100.0, atr.Last(0), 1e-9)
+
+	// risk budget = 10000 * 1% = 100, quantity = 100 / 100
+	quantity, err := sizer.QuantityFor("BTCUSDT")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "1", quantity.String())
+	}
+
+	// the quantity is capped to what the equity can buy, 10000 / 20000
+	session.lastPrices["BTCUSDT"] = fixedpoint.NewFromFloat(20000.0)
+	quantity, err = sizer.QuantityFor("BTCUSDT")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "0.5", quantity.String())
+	}
+
+	_, err = sizer.QuantityFor("ETHUSDT")
+	assert.Error(t, err)
+}
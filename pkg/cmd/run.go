@@ -175,6 +175,8 @@ func runConfig(basectx context.Context, cmd *cobra.Command, userConfig *bbgo.Con
 		return err
 	}
 
+	environ.StartBalanceSnapshots(tradingCtx, userConfig.Environment)
+
 	if enableWebServer {
 		go func() {
 			s := &server.Server{
@@ -213,17 +215,11 @@ func runConfig(basectx context.Context, cmd *cobra.Command, userConfig *bbgo.Con
 		log.WithError(err).Errorf("can not save strategy persistence states")
 	}
 
-	cancelShutdown()
-
-	for _, session := range environ.Sessions() {
-		if err := session.MarketDataStream.Close(); err != nil {
-			log.WithError(err).Errorf("[%s] market data stream close error", session.Name)
-		}
-		if err := session.UserDataStream.Close(); err != nil {
-			log.WithError(err).Errorf("[%s] user data stream close error", session.Name)
-		}
+	if err := environ.Shutdown(shtCtx); err != nil {
+		log.WithError(err).Errorf("environment shutdown error")
 	}
 
+	cancelShutdown()
 	return nil
 }
 
@@ -182,6 +182,47 @@ func (s Slice) Addr() *Slice {
 	return &s
 }
 
+// SMA returns the simple moving average series of the slice,
+// the first value is the average of the first window, so the result has len(s) - window + 1 values.
+// An empty slice is returned if the window is larger than the slice.
+func (s Slice) SMA(window int) (values Slice) {
+	if window <= 0 || window > len(s) {
+		return values
+	}
+
+	sum := 0.0
+	for i, v := range s {
+		sum += v
+		if i >= window {
+			sum -= s[i-window]
+		}
+
+		if i >= window-1 {
+			values.Push(sum / float64(window))
+		}
+	}
+
+	return values
+}
+
+// EMA returns the exponential moving average series of the slice with the multiplier 2 / (window + 1),
+// the first value of the slice is used as the initial average, so the result has the same length as the slice.
+func (s Slice) EMA(window int) (values Slice) {
+	if window <= 0 || len(s) == 0 {
+		return values
+	}
+
+	multiplier := 2.0 / float64(window+1)
+	ema := s[0]
+	values.Push(ema)
+	for _, v := range s[1:] {
+		ema = (1-multiplier)*ema + multiplier*v
+		values.Push(ema)
+	}
+
+	return values
+}
+
 // Last, Index, Length implements the types.Series interface
 func (s Slice) Last(i int) float64 {
 	length := len(s)
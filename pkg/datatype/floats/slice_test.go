@@ -31,3 +31,21 @@ func TestAdd(t *testing.T) {
 	assert.Equal(t, 5, len(c))
 	assert.Equal(t, 5, c.Length())
 }
+
+func TestSlice_SMA(t *testing.T) {
+	a := New(1, 2, 3, 4, 5)
+	assert.Equal(t, Slice{2, 3, 4}, a.SMA(3))
+	assert.Equal(t, Slice{3}, a.SMA(5))
+	assert.Empty(t, a.SMA(6))
+	assert.Empty(t, a.SMA(0))
+	assert.Equal(t, Slice{1, 2, 3, 4, 5}, a, "the original slice should not be modified")
+}
+
+func TestSlice_EMA(t *testing.T) {
+	a := New(1, 2, 3)
+	// multiplier = 2 / (3 + 1) = 0.5
+	assert.Equal(t, Slice{1, 1.5, 2.25}, a.EMA(3))
+	assert.Len(t, a.EMA(10), 3)
+	assert.Empty(t, a.EMA(0))
+	assert.Empty(t, Slice{}.EMA(3))
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/c9s/bbgo/pkg/exchange/binance/binanceapi"
 	"github.com/c9s/bbgo/pkg/fixedpoint"
 	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/util"
 )
 
 func (e *Exchange) queryFuturesClosedOrders(ctx context.Context, symbol string, since, until time.Time, lastOrderID uint64) (orders []types.Order, err error) {
@@ -334,6 +335,50 @@ func (e *Exchange) QueryFuturesPositionRisks(ctx context.Context, symbol string)
 	return nil
 }
 
+// QueryPositions queries the futures positions of the given symbols, all the positions are returned if no symbol is given.
+// The base of the position is the net position amount of all the position sides (for the hedge mode).
+func (e *Exchange) QueryPositions(ctx context.Context, symbols ...string) ([]types.Position, error) {
+	if !e.IsFutures {
+		return nil, fmt.Errorf("binance: QueryPositions is only supported in the futures mode")
+	}
+
+	req := e.futuresClient.NewGetPositionRiskService()
+	if len(symbols) == 1 {
+		req.Symbol(symbols[0])
+	}
+
+	risks, err := req.Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var positions []types.Position
+	var indexes = map[string]int{}
+	for _, risk := range risks {
+		if len(symbols) > 0 && !util.StringSliceContains(symbols, risk.Symbol) {
+			continue
+		}
+
+		amount, err := fixedpoint.NewFromString(risk.PositionAmt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s position amount %q: %w", risk.Symbol, risk.PositionAmt, err)
+		}
+
+		if i, ok := indexes[risk.Symbol]; ok {
+			positions[i].Base = positions[i].Base.Add(amount)
+			continue
+		}
+
+		indexes[risk.Symbol] = len(positions)
+		positions = append(positions, types.Position{
+			Symbol: risk.Symbol,
+			Base:   amount,
+		})
+	}
+
+	return positions, nil
+}
+
 // BBGO is a futures broker on Binance
 const futuresBrokerID = "gBhMvywy"
 
@@ -0,0 +1,42 @@
+package binance
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExchange_QueryPositions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/fapi/v2/positionRisk", r.URL.Path)
+		_, _ = w.Write([]byte(`[
+			{"symbol":"BTCUSDT","positionAmt":"0.5","entryPrice":"20000","positionSide":"LONG"},
+			{"symbol":"BTCUSDT","positionAmt":"-0.2","entryPrice":"21000","positionSide":"SHORT"},
+			{"symbol":"ETHUSDT","positionAmt":"-1.5","entryPrice":"1500","positionSide":"BOTH"}
+		]`))
+	}))
+	defer server.Close()
+
+	e := New("", "")
+	e.futuresClient.BaseURL = server.URL
+
+	_, err := e.QueryPositions(context.Background(), "BTCUSDT")
+	assert.Error(t, err, "the positions can only be queried in the futures mode")
+
+	e.UseFutures()
+
+	positions, err := e.QueryPositions(context.Background(), "BTCUSDT")
+	if assert.NoError(t, err) && assert.Len(t, positions, 1) {
+		assert.Equal(t, "BTCUSDT", positions[0].Symbol)
+		assert.Equal(t, "0.3", positions[0].Base.String())
+	}
+
+	positions, err = e.QueryPositions(context.Background())
+	if assert.NoError(t, err) && assert.Len(t, positions, 2) {
+		assert.Equal(t, "ETHUSDT", positions[1].Symbol)
+		assert.Equal(t, "-1.5", positions[1].Base.String())
+	}
+}
@@ -0,0 +1,57 @@
+package bitgetapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlaceOrderRequest_GetParametersJSON(t *testing.T) {
+	req := NewClient().NewPlaceOrderRequest().
+		Symbol("BTCUSDT_SPBL").
+		OrderType(OrderTypeLimit).
+		Side(OrderSideBuy).
+		Force(OrderForceGTC).
+		Price("28000.5").
+		Quantity("0.001").
+		ClientOrderId("bbgo-1234")
+
+	body, err := req.GetParametersJSON()
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{
+			"symbol": "BTCUSDT_SPBL",
+			"orderType": "limit",
+			"side": "buy",
+			"force": "normal",
+			"price": "28000.5",
+			"quantity": "0.001",
+			"clientOrderId": "bbgo-1234"
+		}`, string(body))
+	}
+}
+
+func TestCancelOrderRequest_GetParametersJSON(t *testing.T) {
+	req := NewClient().NewCancelOrderRequest().
+		Symbol("BTCUSDT_SPBL").
+		OrderId("1098394857477234688")
+
+	body, err := req.GetParametersJSON()
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{
+			"symbol": "BTCUSDT_SPBL",
+			"orderId": "1098394857477234688"
+		}`, string(body))
+	}
+
+	req = NewClient().NewCancelOrderRequest().
+		Symbol("BTCUSDT_SPBL").
+		ClientOrderId("bbgo-1234")
+
+	body, err = req.GetParametersJSON()
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{
+			"symbol": "BTCUSDT_SPBL",
+			"clientOid": "bbgo-1234"
+		}`, string(body))
+	}
+}
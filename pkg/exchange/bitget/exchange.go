@@ -291,18 +291,18 @@ func (e *Exchange) SubmitOrder(ctx context.Context, order types.SubmitOrder) (cr
 	req.Side(side)
 
 	// set quantity
-	qty := order.Quantity
 	// if the order is market buy, the quantity is quote coin, instead of base coin. so we need to convert it.
+	// the quote amount is formatted with the quote precision, FormatQuantity truncates it to the base step size.
 	if order.Type == types.OrderTypeMarket && order.Side == types.SideTypeBuy {
 		ticker, err := e.QueryTicker(ctx, order.Market.Symbol)
 		if err != nil {
 			return nil, err
 		}
-		qty = order.Quantity.Mul(ticker.Buy)
+		req.Size(order.Quantity.Mul(ticker.Buy).FormatString(order.Market.PricePrecision))
+	} else {
+		req.Size(order.Market.FormatQuantity(order.Quantity))
 	}
 
-	req.Size(order.Market.FormatQuantity(qty))
-
 	// set TimeInForce
 	// we only support GTC/PostOnly, because:
 	// 1. we only support SPOT trading.
@@ -0,0 +1,162 @@
+package bitget
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestExchange_QueryMarkets(t *testing.T) {
+	e := New("", "", "")
+	e.client.HttpClient = httptesting.HttpClientFromFile("testdata/symbols.json")
+
+	markets, err := e.QueryMarkets(context.Background())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Len(t, markets, 1)
+
+	market, ok := markets["BTCUSDT"]
+	if assert.True(t, ok) {
+		assert.Equal(t, "BTC", market.BaseCurrency)
+		assert.Equal(t, "USDT", market.QuoteCurrency)
+		assert.Equal(t, 2, market.PricePrecision)
+		assert.Equal(t, 4, market.VolumePrecision)
+		assert.Equal(t, "0.0001", market.MinQuantity.String())
+		assert.Equal(t, "5", market.MinNotional.String())
+		assert.Equal(t, "0.0001", market.StepSize.String())
+		assert.Equal(t, "0.01", market.TickSize.String())
+	}
+
+	_, ok = markets["OFFUSDT"]
+	assert.False(t, ok, "offline symbols should be ignored")
+}
+
+func TestExchange_QueryAccountBalances(t *testing.T) {
+	e := New("key", "secret", "passphrase")
+	e.client.HttpClient = httptesting.HttpClientFromFile("testdata/account_assets.json")
+
+	balances, err := e.QueryAccountBalances(context.Background())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Len(t, balances, 2)
+
+	usdt, ok := balances["USDT"]
+	if assert.True(t, ok) {
+		assert.Equal(t, "1200.5", usdt.Available.String())
+		assert.Equal(t, "120.5", usdt.Locked.String()) // frozen + locked
+	}
+
+	btc, ok := balances["BTC"]
+	if assert.True(t, ok) {
+		assert.Equal(t, "0.0123", btc.Available.String())
+		assert.Equal(t, "0.001", btc.Locked.String())
+	}
+}
+
+func TestExchange_QueryKLines(t *testing.T) {
+	content, err := os.ReadFile("testdata/candles.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var req *http.Request
+	e := New("", "", "")
+	e.client.HttpClient = httptesting.HttpClientSaver(&req, string(content))
+
+	startTime := time.UnixMilli(1691486100000)
+	kLines, err := e.QueryKLines(context.Background(), "BTCUSDT", types.Interval15m, types.KLineQueryOptions{
+		StartTime: &startTime,
+		Limit:     2,
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	query := req.URL.Query()
+	assert.Equal(t, "/api/v2/spot/market/candles", req.URL.Path)
+	assert.Equal(t, "15min", query.Get("granularity"))
+	assert.Equal(t, "2", query.Get("limit"))
+	assert.Equal(t, "1691486100000", query.Get("startTime"))
+
+	if assert.Len(t, kLines, 2) {
+		// the klines are sorted in ascending order
+		k := kLines[0]
+		assert.Equal(t, types.ExchangeBitget, k.Exchange)
+		assert.Equal(t, "BTCUSDT", k.Symbol)
+		assert.Equal(t, types.Interval15m, k.Interval)
+		assert.Equal(t, int64(1691486100000), k.StartTime.Time().UnixMilli())
+		assert.Equal(t, int64(1691486100000+15*60*1000-1), k.EndTime.Time().UnixMilli())
+		assert.Equal(t, "29045.3", k.Open.String())
+		assert.Equal(t, "29228.56", k.High.String())
+		assert.Equal(t, "29045.3", k.Low.String())
+		assert.Equal(t, "29228.56", k.Close.String())
+		assert.Equal(t, "9.265593", k.Volume.String())
+		assert.Equal(t, "270447.43520753", k.QuoteVolume.String())
+
+		assert.Equal(t, int64(1691487000000), kLines[1].StartTime.Time().UnixMilli())
+		assert.Equal(t, "29045.3", kLines[1].Close.String())
+	}
+}
+
+func TestExchange_SubmitOrder_MarketBuy(t *testing.T) {
+	var sizes []string
+	transport := &httptesting.MockTransport{}
+	transport.GET("/api/v2/spot/market/tickers", func(req *http.Request) (*http.Response, error) {
+		return httptesting.BuildResponseString(http.StatusOK, `{"code":"00000","msg":"success","data":[{"symbol":"BTCUSDT","bidPr":"30123.456","askPr":"30124"}]}`), nil
+	})
+	transport.POST("/api/v2/spot/trade/place-order", func(req *http.Request) (*http.Response, error) {
+		var params map[string]interface{}
+		if err := json.NewDecoder(req.Body).Decode(&params); err != nil {
+			return nil, err
+		}
+
+		sizes = append(sizes, params["size"].(string))
+		return httptesting.BuildResponseString(http.StatusOK, `{"code":"43012","msg":"Insufficient balance","data":null}`), nil
+	})
+
+	e := New("key", "secret", "passphrase")
+	e.client.HttpClient = &http.Client{Transport: transport}
+
+	market := types.Market{
+		Symbol:          "BTCUSDT",
+		PricePrecision:  2,
+		VolumePrecision: 4,
+		StepSize:        fixedpoint.MustNewFromString("0.0001"),
+		TickSize:        fixedpoint.MustNewFromString("0.01"),
+	}
+
+	// the quote amount of the market buy is formatted with the quote precision
+	// instead of being truncated to the base step size
+	_, err := e.SubmitOrder(context.Background(), types.SubmitOrder{
+		Symbol:   "BTCUSDT",
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeMarket,
+		Market:   market,
+		Quantity: fixedpoint.MustNewFromString("0.001"),
+	})
+	assert.Error(t, err)
+
+	_, err = e.SubmitOrder(context.Background(), types.SubmitOrder{
+		Symbol:   "BTCUSDT",
+		Side:     types.SideTypeSell,
+		Type:     types.OrderTypeMarket,
+		Market:   market,
+		Quantity: fixedpoint.MustNewFromString("0.00123"),
+	})
+	assert.Error(t, err)
+
+	assert.Equal(t, []string{"30.12", "0.0012"}, sizes)
+}
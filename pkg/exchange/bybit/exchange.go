@@ -280,16 +280,17 @@ func (e *Exchange) SubmitOrder(ctx context.Context, order types.SubmitOrder) (*t
 	req.Side(side)
 
 	// set quantity
-	orderQty := order.Quantity
 	// if the order is market buy, the quantity is quote coin, instead of base coin. so we need to convert it.
+	// the quote amount is formatted with the quote precision, FormatQuantity truncates it to the base step size.
 	if order.Type == types.OrderTypeMarket && order.Side == types.SideTypeBuy {
 		ticker, err := e.QueryTicker(ctx, order.Market.Symbol)
 		if err != nil {
 			return nil, err
 		}
-		orderQty = order.Quantity.Mul(ticker.Buy)
+		req.Qty(order.Quantity.Mul(ticker.Buy).FormatString(order.Market.PricePrecision))
+	} else {
+		req.Qty(order.Market.FormatQuantity(order.Quantity))
 	}
-	req.Qty(order.Market.FormatQuantity(orderQty))
 
 	// set price
 	switch order.Type {
@@ -0,0 +1,67 @@
+package bybit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestExchange_SubmitOrder_MarketBuy(t *testing.T) {
+	var quantities []string
+	transport := &httptesting.MockTransport{}
+	transport.GET("/v5/market/tickers", func(req *http.Request) (*http.Response, error) {
+		return httptesting.BuildResponseString(http.StatusOK, `{"retCode":0,"retMsg":"OK","result":{"category":"spot","list":[{"symbol":"BTCUSDT","bid1Price":"30123.456","ask1Price":"30124"}]},"retExtInfo":{},"time":1702617474601}`), nil
+	})
+	transport.POST("/v5/order/create", func(req *http.Request) (*http.Response, error) {
+		var params map[string]interface{}
+		if err := json.NewDecoder(req.Body).Decode(&params); err != nil {
+			return nil, err
+		}
+
+		quantities = append(quantities, params["qty"].(string))
+		return httptesting.BuildResponseString(http.StatusOK, `{"retCode":170131,"retMsg":"Insufficient balance.","result":{},"retExtInfo":{},"time":1702617474601}`), nil
+	})
+
+	e, err := New("key", "secret")
+	if !assert.NoError(t, err) {
+		return
+	}
+	e.client.HttpClient = &http.Client{Transport: transport}
+
+	market := types.Market{
+		Symbol:          "BTCUSDT",
+		PricePrecision:  2,
+		VolumePrecision: 4,
+		StepSize:        fixedpoint.MustNewFromString("0.0001"),
+		TickSize:        fixedpoint.MustNewFromString("0.01"),
+	}
+
+	// the quote amount of the market buy is formatted with the quote precision
+	// instead of being truncated to the base step size
+	_, err = e.SubmitOrder(context.Background(), types.SubmitOrder{
+		Symbol:   "BTCUSDT",
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeMarket,
+		Market:   market,
+		Quantity: fixedpoint.MustNewFromString("0.001"),
+	})
+	assert.Error(t, err)
+
+	_, err = e.SubmitOrder(context.Background(), types.SubmitOrder{
+		Symbol:   "BTCUSDT",
+		Side:     types.SideTypeSell,
+		Type:     types.OrderTypeMarket,
+		Market:   market,
+		Quantity: fixedpoint.MustNewFromString("0.00123"),
+	})
+	assert.Error(t, err)
+
+	assert.Equal(t, []string{"30.12", "0.0012"}, quantities)
+}
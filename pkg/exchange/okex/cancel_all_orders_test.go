@@ -0,0 +1,116 @@
+package okex
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/multierr"
+
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+)
+
+func newOpenOrdersResponse(n int) map[string]interface{} {
+	return newOpenOrdersPageResponse(1000, n)
+}
+
+// newOpenOrdersPageResponse builds a page of n open orders with the order ids descending from startID,
+// the same order as the api returns.
+func newOpenOrdersPageResponse(startID int, n int) map[string]interface{} {
+	var data []map[string]interface{}
+	for i := 0; i < n; i++ {
+		data = append(data, map[string]interface{}{
+			"instType":  "SPOT",
+			"instId":    "BTC-USDT",
+			"ordId":     strconv.Itoa(startID - i),
+			"clOrdId":   "",
+			"px":        "20000",
+			"sz":        "0.001",
+			"accFillSz": "0",
+			"ordType":   "limit",
+			"side":      "buy",
+			"state":     "live",
+			"cTime":     "1700000000000",
+			"uTime":     "1700000000000",
+		})
+	}
+
+	return map[string]interface{}{
+		"code": "0",
+		"msg":  "",
+		"data": data,
+	}
+}
+
+func Test_CancelAllOrders(t *testing.T) {
+	ctx := context.Background()
+
+	newExchange := func(numOrders int, failedBatch int) (*Exchange, *[]int) {
+		var batchSizes []int
+
+		transport := &httptesting.MockTransport{}
+		transport.GET("/api/v5/trade/orders-pending", func(req *http.Request) (*http.Response, error) {
+			return httptesting.BuildResponseJson(http.StatusOK, newOpenOrdersResponse(numOrders)), nil
+		})
+		transport.POST("/api/v5/trade/cancel-batch-orders", func(req *http.Request) (*http.Response, error) {
+			var params []map[string]interface{}
+			if err := json.NewDecoder(req.Body).Decode(&params); err != nil {
+				return nil, err
+			}
+
+			batchSizes = append(batchSizes, len(params))
+			if len(batchSizes) == failedBatch {
+				return httptesting.BuildResponseString(http.StatusInternalServerError, `{"code":"50000","msg":"internal error","data":[]}`), nil
+			}
+
+			var data []map[string]interface{}
+			for _, p := range params {
+				data = append(data, map[string]interface{}{
+					"ordId": p["ordId"],
+					"sCode": "0",
+					"sMsg":  "",
+				})
+			}
+			return httptesting.BuildResponseJson(http.StatusOK, map[string]interface{}{
+				"code": "0",
+				"msg":  "",
+				"data": data,
+			}), nil
+		})
+
+		e := New("key", "secret", "passphrase")
+		e.client.HttpClient = &http.Client{Transport: transport}
+		return e, &batchSizes
+	}
+
+	t.Run("chunked by batch limit", func(t *testing.T) {
+		e, batchSizes := newExchange(maxBatchCancelOrderSize*2+5, 0)
+
+		err := e.CancelAllOrders(ctx, "BTCUSDT")
+		if assert.NoError(t, err) {
+			assert.Equal(t, []int{maxBatchCancelOrderSize, maxBatchCancelOrderSize, 5}, *batchSizes)
+		}
+	})
+
+	t.Run("aggregate batch errors", func(t *testing.T) {
+		e, batchSizes := newExchange(maxBatchCancelOrderSize+5, 1)
+
+		err := e.CancelAllOrders(ctx, "BTCUSDT")
+		if assert.Error(t, err) {
+			assert.Len(t, multierr.Errors(err), 1)
+			assert.Contains(t, err.Error(), fmt.Sprintf("[0:%d]", maxBatchCancelOrderSize))
+		}
+
+		// the remaining batch should still be canceled
+		assert.Equal(t, []int{maxBatchCancelOrderSize, 5}, *batchSizes)
+	})
+
+	t.Run("symbol required", func(t *testing.T) {
+		e, _ := newExchange(0, 0)
+		assert.ErrorIs(t, e.CancelAllOrders(ctx, ""), ErrSymbolRequired)
+	})
+}
@@ -0,0 +1,40 @@
+package okex
+
+import (
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// lastClientOrderID is the last generated client order id, it's used to keep the generated ids increasing
+var lastClientOrderID int64
+
+// newClientOrderID returns the original client order id if it's given, otherwise it generates a unique one.
+//
+// OKX accepts the clOrdId of up to 32 case-sensitive alphanumerics, and this exchange requires the client order id
+// to be numeric, so the generated id is the nanosecond timestamp which is increased by one on collision,
+// it fits in int64 and has 19 digits.
+func newClientOrderID(originalID string) string {
+	// skip blank client order ID
+	if originalID == types.NoClientOrderID {
+		return ""
+	}
+
+	if originalID != "" {
+		return originalID
+	}
+
+	for {
+		last := atomic.LoadInt64(&lastClientOrderID)
+		id := time.Now().UnixNano()
+		if id <= last {
+			id = last + 1
+		}
+
+		if atomic.CompareAndSwapInt64(&lastClientOrderID, last, id) {
+			return strconv.FormatInt(id, 10)
+		}
+	}
+}
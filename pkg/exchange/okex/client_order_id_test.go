@@ -0,0 +1,143 @@
+package okex
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// okxClientOrderIDRegExp is the clOrdId constraint of OKX, up to 32 case-sensitive alphanumerics
+var okxClientOrderIDRegExp = regexp.MustCompile(`^[a-zA-Z0-9]{1,32}$`)
+
+func Test_newClientOrderID(t *testing.T) {
+	assert.Equal(t, "123456", newClientOrderID("123456"))
+	assert.Equal(t, "", newClientOrderID(types.NoClientOrderID))
+
+	seen := map[string]struct{}{}
+	for i := 0; i < 1000; i++ {
+		id := newClientOrderID("")
+		assert.Regexp(t, okxClientOrderIDRegExp, id)
+
+		_, ok := seen[id]
+		assert.False(t, ok, "duplicated client order id %s", id)
+		seen[id] = struct{}{}
+	}
+}
+
+func TestExchange_SubmitOrder_clientOrderID(t *testing.T) {
+	market := types.Market{
+		Symbol:          "BTCUSDT",
+		LocalSymbol:     "BTC-USDT",
+		PricePrecision:  1,
+		VolumePrecision: 8,
+		QuoteCurrency:   "USDT",
+		BaseCurrency:    "BTC",
+		MinNotional:     fixedpoint.NewFromFloat(10.0),
+		MinQuantity:     fixedpoint.NewFromFloat(0.0001),
+		StepSize:        fixedpoint.NewFromFloat(0.0001),
+		TickSize:        fixedpoint.NewFromFloat(0.1),
+	}
+
+	var body map[string]interface{}
+	transport := &httptesting.MockTransport{}
+	transport.POST("/api/v5/trade/order", func(req *http.Request) (*http.Response, error) {
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			return nil, err
+		}
+
+		return httptesting.BuildResponseString(http.StatusOK, `{"code":"0","msg":"","data":[
+			{"ordId":"312269865356374016","clOrdId":"`+body["clOrdId"].(string)+`","tag":"","sCode":"0","sMsg":""}
+		]}`), nil
+	})
+	transport.GET("/api/v5/trade/order", func(req *http.Request) (*http.Response, error) {
+		clientOrderID := req.URL.Query().Get("clOrdId")
+		return httptesting.BuildResponseString(http.StatusOK, `{"code":"0","msg":"","data":[
+			{"instType":"SPOT","instId":"BTC-USDT","ordId":"312269865356374016","clOrdId":"`+clientOrderID+`",
+			"px":"20000","sz":"0.001","ordType":"limit","side":"buy","state":"live","accFillSz":"0",
+			"cTime":"1597026383085","uTime":"1597026383085"}
+		]}`), nil
+	})
+
+	e := New("key", "secret", "passphrase")
+	e.client.HttpClient = &http.Client{Transport: transport}
+
+	order, err := e.SubmitOrder(context.Background(), types.SubmitOrder{
+		Symbol:   "BTCUSDT",
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimit,
+		Market:   market,
+		Price:    fixedpoint.NewFromFloat(20000.0),
+		Quantity: fixedpoint.MustNewFromString("0.001"),
+	})
+	if assert.NoError(t, err) {
+		clientOrderID, ok := body["clOrdId"].(string)
+		if assert.True(t, ok) {
+			assert.Regexp(t, okxClientOrderIDRegExp, clientOrderID)
+			assert.Equal(t, clientOrderID, order.ClientOrderID)
+		}
+		assert.Equal(t, uint64(312269865356374016), order.OrderID)
+	}
+}
+
+func TestExchange_SubmitOrder_clientOrderIDOnError(t *testing.T) {
+	market := types.Market{
+		Symbol:          "BTCUSDT",
+		LocalSymbol:     "BTC-USDT",
+		PricePrecision:  1,
+		VolumePrecision: 8,
+		QuoteCurrency:   "USDT",
+		BaseCurrency:    "BTC",
+		MinNotional:     fixedpoint.NewFromFloat(10.0),
+		MinQuantity:     fixedpoint.NewFromFloat(0.0001),
+		StepSize:        fixedpoint.NewFromFloat(0.0001),
+		TickSize:        fixedpoint.NewFromFloat(0.1),
+	}
+
+	var sentClientOrderIDs []string
+	transport := &httptesting.MockTransport{}
+	transport.POST("/api/v5/trade/order", func(req *http.Request) (*http.Response, error) {
+		var body map[string]interface{}
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			return nil, err
+		}
+
+		sentClientOrderIDs = append(sentClientOrderIDs, body["clOrdId"].(string))
+		return httptesting.BuildResponseString(http.StatusGatewayTimeout, `gateway timeout`), nil
+	})
+
+	e := New("key", "secret", "passphrase")
+	e.client.HttpClient = &http.Client{Transport: transport}
+
+	submitOrder := types.SubmitOrder{
+		Symbol:   "BTCUSDT",
+		Side:     types.SideTypeBuy,
+		Type:     types.OrderTypeLimit,
+		Market:   market,
+		Price:    fixedpoint.NewFromFloat(20000.0),
+		Quantity: fixedpoint.MustNewFromString("0.001"),
+	}
+
+	_, err := e.SubmitOrder(context.Background(), submitOrder)
+	var submitOrderErr *types.SubmitOrderError
+	if !assert.Error(t, err) || !assert.True(t, errors.As(err, &submitOrderErr)) || !assert.Len(t, sentClientOrderIDs, 1) {
+		return
+	}
+
+	assert.Equal(t, sentClientOrderIDs[0], submitOrderErr.ClientOrderID())
+
+	// the retry with the returned client order id sends the same id
+	submitOrder.ClientOrderID = submitOrderErr.ClientOrderID()
+	_, err = e.SubmitOrder(context.Background(), submitOrder)
+	if assert.Error(t, err) && assert.Len(t, sentClientOrderIDs, 2) {
+		assert.Equal(t, sentClientOrderIDs[0], sentClientOrderIDs[1])
+	}
+}
@@ -29,6 +29,25 @@ func toLocalSymbol(symbol string) string {
 	return symbol
 }
 
+// toLocalPerpetualSymbol converts the global symbol to the okex perpetual swap instrument id, e.g. BTCUSDT -> BTC-USDT-SWAP
+func toLocalPerpetualSymbol(symbol string) string {
+	return toLocalSymbol(symbol) + "-SWAP"
+}
+
+// toGlobalPerpetualSymbol converts the okex perpetual swap instrument id to the global symbol, e.g. BTC-USDT-SWAP -> BTCUSDT
+func toGlobalPerpetualSymbol(instrumentID string) string {
+	return toGlobalSymbol(strings.TrimSuffix(instrumentID, "-SWAP"))
+}
+
+func toGlobalFundingRate(rate okexapi.FundingRateHistory) types.FundingRate {
+	return types.FundingRate{
+		FundingRate:  rate.FundingRate,
+		RealizedRate: rate.RealizedRate,
+		FundingTime:  rate.FundingTime.Time(),
+		Time:         rate.FundingTime.Time(),
+	}
+}
+
 func toGlobalTicker(marketTicker okexapi.MarketTicker) *types.Ticker {
 	return &types.Ticker{
 		Time:   marketTicker.Timestamp.Time(),
@@ -42,6 +61,35 @@ func toGlobalTicker(marketTicker okexapi.MarketTicker) *types.Ticker {
 	}
 }
 
+func toGlobalMarket(instrument okexapi.InstrumentInfo) types.Market {
+	return types.Market{
+		Symbol:      toGlobalSymbol(instrument.InstrumentID),
+		LocalSymbol: instrument.InstrumentID,
+
+		QuoteCurrency: instrument.QuoteCurrency,
+		BaseCurrency:  instrument.BaseCurrency,
+
+		// convert tick size OKEx to precision
+		PricePrecision:  instrument.TickSize.NumFractionalDigits(),
+		VolumePrecision: instrument.LotSize.NumFractionalDigits(),
+
+		// TickSize: OKEx's price tick, for BTC-USDT it's "0.1"
+		TickSize: instrument.TickSize,
+
+		// Quantity step size, for BTC-USDT, it's "0.00000001"
+		StepSize: instrument.LotSize,
+
+		// for BTC-USDT, it's "0.00001"
+		MinQuantity: instrument.MinSize,
+
+		// OKEx does not offer minimal notional, use 1 USD here.
+		MinNotional: fixedpoint.One,
+		MinAmount:   fixedpoint.One,
+
+		State: string(instrument.State),
+	}
+}
+
 func toGlobalBalance(account *okexapi.Account) types.BalanceMap {
 	var balanceMap = types.BalanceMap{}
 	for _, balanceDetail := range account.Details {
@@ -54,6 +102,11 @@ func toGlobalBalance(account *okexapi.Account) types.BalanceMap {
 	return balanceMap
 }
 
+// toGlobalTotalEquity returns the total equity of the account, okex reports the total equity in USD
+func toGlobalTotalEquity(account *okexapi.Account) (fixedpoint.Value, string) {
+	return account.TotalEquityInUSD, "USD"
+}
+
 type WebsocketSubscription struct {
 	Channel        Channel `json:"channel"`
 	InstrumentID   string  `json:"instId,omitempty"`
@@ -110,11 +163,18 @@ func convertSubscription(s types.Subscription) (WebsocketSubscription, error) {
 			Channel:      ChannelBook5,
 			InstrumentID: toLocalSymbol(s.Symbol),
 		}, nil
-	case types.MarketTradeChannel:
+	case types.MarketTradeChannel, types.AggTradeChannel:
+		// the okex trades channel aggregates the trades of the same taker order and price,
+		// so it's used for both the market trade and the agg trade
 		return WebsocketSubscription{
 			Channel:      ChannelMarketTrades,
 			InstrumentID: toLocalSymbol(s.Symbol),
 		}, nil
+	case types.MarkPriceChannel:
+		return WebsocketSubscription{
+			Channel:      ChannelMarkPrice,
+			InstrumentID: toLocalPerpetualSymbol(s.Symbol),
+		}, nil
 	}
 
 	return WebsocketSubscription{}, fmt.Errorf("unsupported public stream channel %s", s.Channel)
@@ -210,11 +270,6 @@ func orderDetailToGlobal(order *okexapi.OrderDetail) (*types.Order, error) {
 		timeInForce = types.TimeInForceIOC
 	}
 
-	orderStatus, err := toGlobalOrderStatus(order.State)
-	if err != nil {
-		return nil, err
-	}
-
 	size := order.Size
 	if order.Side == okexapi.SideTypeBuy &&
 		order.OrderType == okexapi.OrderTypeMarket &&
@@ -226,6 +281,11 @@ func orderDetailToGlobal(order *okexapi.OrderDetail) (*types.Order, error) {
 		}
 	}
 
+	orderStatus, err := toGlobalOrderStatus(order.State, order.AccumulatedFillSize, size)
+	if err != nil {
+		return nil, err
+	}
+
 	return &types.Order{
 		SubmitOrder: types.SubmitOrder{
 			ClientOrderID: order.ClientOrderId,
@@ -265,11 +325,17 @@ func toGlobalOrders(orderDetails []okexapi.OrderDetails) ([]types.Order, error)
 	return orders, err
 }
 
-func toGlobalOrderStatus(state okexapi.OrderState) (types.OrderStatus, error) {
+// toGlobalOrderStatus converts the okex order state to the global order status,
+// the executed quantity is used for detecting the partially filled order that is still reported as live.
+// The partially canceled order (canceled with fills) is mapped to canceled, the filled part is kept in ExecutedQuantity.
+func toGlobalOrderStatus(state okexapi.OrderState, executedQuantity, quantity fixedpoint.Value) (types.OrderStatus, error) {
 	switch state {
-	case okexapi.OrderStateCanceled:
+	case okexapi.OrderStateCanceled, okexapi.OrderStateMMPCanceled:
 		return types.OrderStatusCanceled, nil
 	case okexapi.OrderStateLive:
+		if executedQuantity.Sign() > 0 && executedQuantity.Compare(quantity) < 0 {
+			return types.OrderStatusPartiallyFilled, nil
+		}
 		return types.OrderStatusNew, nil
 	case okexapi.OrderStatePartiallyFilled:
 		return types.OrderStatusPartiallyFilled, nil
@@ -359,7 +425,7 @@ func toGlobalOrder(okexOrder *okexapi.OrderDetails) (*types.Order, error) {
 		timeInForce = types.TimeInForceIOC
 	}
 
-	orderStatus, err := toGlobalOrderStatus(okexOrder.State)
+	orderStatus, err := toGlobalOrderStatus(okexOrder.State, okexOrder.FilledQuantity, okexOrder.Quantity)
 	if err != nil {
 		return nil, err
 	}
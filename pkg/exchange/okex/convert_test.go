@@ -243,3 +243,56 @@ func Test_processMarketBuyQuantity(t *testing.T) {
 		assert.ErrorContains(err, "unexpected")
 	})
 }
+
+func Test_toGlobalOrderStatus(t *testing.T) {
+	quantity := fixedpoint.NewFromFloat(1.0)
+
+	tests := []struct {
+		state            okexapi.OrderState
+		executedQuantity fixedpoint.Value
+		expected         types.OrderStatus
+		expectErr        bool
+	}{
+		{state: okexapi.OrderStateLive, executedQuantity: fixedpoint.Zero, expected: types.OrderStatusNew},
+		{state: okexapi.OrderStateLive, executedQuantity: fixedpoint.NewFromFloat(0.4), expected: types.OrderStatusPartiallyFilled},
+		{state: okexapi.OrderStatePartiallyFilled, executedQuantity: fixedpoint.NewFromFloat(0.4), expected: types.OrderStatusPartiallyFilled},
+		{state: okexapi.OrderStateFilled, executedQuantity: quantity, expected: types.OrderStatusFilled},
+		{state: okexapi.OrderStateCanceled, executedQuantity: fixedpoint.Zero, expected: types.OrderStatusCanceled},
+		{state: okexapi.OrderStateCanceled, executedQuantity: fixedpoint.NewFromFloat(0.4), expected: types.OrderStatusCanceled},
+		{state: okexapi.OrderStateMMPCanceled, executedQuantity: fixedpoint.Zero, expected: types.OrderStatusCanceled},
+		{state: "unknown", executedQuantity: fixedpoint.Zero, expectErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s/%s", test.state, test.executedQuantity), func(t *testing.T) {
+			status, err := toGlobalOrderStatus(test.state, test.executedQuantity, quantity)
+			if test.expectErr {
+				assert.Error(t, err)
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assert.Equal(t, test.expected, status)
+			}
+		})
+	}
+}
+
+func Test_toGlobalOrder_PartiallyFilled(t *testing.T) {
+	order, err := toGlobalOrder(&okexapi.OrderDetails{
+		InstrumentType: okexapi.InstrumentTypeSpot,
+		InstrumentID:   "BTC-USDT",
+		Price:          fixedpoint.NewFromFloat(20000.0),
+		Quantity:       fixedpoint.NewFromFloat(1.0),
+		OrderID:        "665576973905014786",
+		OrderType:      okexapi.OrderTypeLimit,
+		Side:           okexapi.SideTypeBuy,
+		FilledQuantity: fixedpoint.NewFromFloat(0.25),
+		State:          okexapi.OrderStatePartiallyFilled,
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, types.OrderStatusPartiallyFilled, order.Status)
+		assert.Equal(t, "0.25", order.ExecutedQuantity.String())
+		assert.True(t, order.IsWorking)
+	}
+}
@@ -3,7 +3,10 @@ package okex
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -17,9 +20,9 @@ import (
 )
 
 // Okex rate limit list in each api document
-// The default order limiter apply 30 requests per second and a 5 initial bucket
-// this includes QueryOrder, QueryOrderTrades, SubmitOrder, QueryOpenOrders, CancelOrders
-// Market data limiter means public api, this includes QueryMarkets, QueryTicker, QueryTickers, QueryKLines
+// Each REST method waits on its own limiter before sending the request, the limiters are shared by all the exchange instances.
+// The order limiters apply 30 requests per second, this includes QueryOrder, SubmitOrder, QueryOpenOrders
+// Market data limiter means public api, this includes QueryKLines
 var (
 	marketDataLimiter = rate.NewLimiter(rate.Every(100*time.Millisecond), 5)
 
@@ -30,8 +33,13 @@ var (
 	placeOrderLimiter           = rate.NewLimiter(rate.Every(30*time.Millisecond), 30)
 	batchCancelOrderLimiter     = rate.NewLimiter(rate.Every(5*time.Millisecond), 200)
 	queryOpenOrderLimiter       = rate.NewLimiter(rate.Every(30*time.Millisecond), 30)
+	queryOrderLimiter           = rate.NewLimiter(rate.Every(30*time.Millisecond), 30)
 	queryClosedOrderRateLimiter = rate.NewLimiter(rate.Every(100*time.Millisecond), 10)
 	queryTradeLimiter           = rate.NewLimiter(rate.Every(100*time.Millisecond), 10)
+	queryFundingRateLimiter     = rate.NewLimiter(rate.Every(200*time.Millisecond), 10)
+	querySubAccountLimiter      = rate.NewLimiter(rate.Every(time.Second), 2)
+	queryServerTimeLimiter      = rate.NewLimiter(rate.Every(200*time.Millisecond), 10)
+	withdrawLimiter             = rate.NewLimiter(rate.Every(time.Second/6), 6)
 )
 
 const (
@@ -42,9 +50,15 @@ const (
 
 	defaultQueryLimit = 100
 
+	// maxBatchCancelOrderSize is the maximum number of orders that can be canceled in one batch request
+	maxBatchCancelOrderSize = 20
+
 	maxHistoricalDataQueryPeriod = 90 * 24 * time.Hour
 )
 
+// serverTimeSyncInterval is the interval of re-measuring the clock skew
+var serverTimeSyncInterval = time.Hour
+
 var log = logrus.WithFields(logrus.Fields{
 	"exchange": ID,
 })
@@ -52,9 +66,16 @@ var log = logrus.WithFields(logrus.Fields{
 var ErrSymbolRequired = errors.New("symbol is a required parameter")
 
 type Exchange struct {
+	types.FuturesSettings
+
 	key, secret, passphrase string
 
 	client *okexapi.RestClient
+
+	// includeNonLiveMarkets includes the suspended, pre-open and expired instruments in QueryMarkets
+	includeNonLiveMarkets bool
+
+	serverTimeSyncOnce sync.Once
 }
 
 func New(key, secret, passphrase string) *Exchange {
@@ -72,6 +93,12 @@ func New(key, secret, passphrase string) *Exchange {
 	}
 }
 
+// IncludeNonLiveMarkets makes QueryMarkets return the instruments that are not in the live state,
+// the state is stored in types.Market.State.
+func (e *Exchange) IncludeNonLiveMarkets(include bool) {
+	e.includeNonLiveMarkets = include
+}
+
 func (e *Exchange) Name() types.ExchangeName {
 	return types.ExchangeOKEx
 }
@@ -88,32 +115,12 @@ func (e *Exchange) QueryMarkets(ctx context.Context) (types.MarketMap, error) {
 
 	markets := types.MarketMap{}
 	for _, instrument := range instruments {
-		symbol := toGlobalSymbol(instrument.InstrumentID)
-		market := types.Market{
-			Symbol:      symbol,
-			LocalSymbol: instrument.InstrumentID,
-
-			QuoteCurrency: instrument.QuoteCurrency,
-			BaseCurrency:  instrument.BaseCurrency,
-
-			// convert tick size OKEx to precision
-			PricePrecision:  instrument.TickSize.NumFractionalDigits(),
-			VolumePrecision: instrument.LotSize.NumFractionalDigits(),
-
-			// TickSize: OKEx's price tick, for BTC-USDT it's "0.1"
-			TickSize: instrument.TickSize,
-
-			// Quantity step size, for BTC-USDT, it's "0.00000001"
-			StepSize: instrument.LotSize,
-
-			// for BTC-USDT, it's "0.00001"
-			MinQuantity: instrument.MinSize,
-
-			// OKEx does not offer minimal notional, use 1 USD here.
-			MinNotional: fixedpoint.One,
-			MinAmount:   fixedpoint.One,
+		if instrument.State != okexapi.InstrumentStateLive && !e.includeNonLiveMarkets {
+			continue
 		}
-		markets[symbol] = market
+
+		market := toGlobalMarket(instrument)
+		markets[market.Symbol] = market
 	}
 
 	return markets, nil
@@ -200,7 +207,283 @@ func (e *Exchange) QueryAccountBalances(ctx context.Context) (types.BalanceMap,
 	return toGlobalBalance(&accountBalances[0]), nil
 }
 
+// Withdraw submits an on-chain withdrawal from the funding account.
+// The chain of the multi-chain assets is required, it's given by options.Network, e.g., "TRC20" or "USDT-TRC20".
+// The minimal withdrawal fee of the chain is used as the transaction fee.
+func (e *Exchange) Withdraw(
+	ctx context.Context, asset string, amount fixedpoint.Value, address string, options *types.WithdrawalOptions,
+) error {
+	if err := withdrawLimiter.Wait(ctx); err != nil {
+		return fmt.Errorf("withdraw rate limiter wait error: %w", err)
+	}
+
+	if amount.Sign() <= 0 {
+		return fmt.Errorf("invalid withdrawal amount: %s", amount.String())
+	}
+
+	var network, addressTag string
+	if options != nil {
+		network = options.Network
+		addressTag = options.AddressTag
+	}
+
+	currencies, err := e.client.AssetCurrencies(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to query asset currencies: %w", err)
+	}
+
+	currency, err := selectWithdrawalChain(currencies, asset, network)
+	if err != nil {
+		return err
+	}
+
+	if currency.MinWithdrawalThreshold.Sign() > 0 && amount.Compare(currency.MinWithdrawalThreshold) < 0 {
+		return fmt.Errorf("withdrawal amount %s is less than the minimal withdrawal amount %s of %s",
+			amount.String(), currency.MinWithdrawalThreshold.String(), currency.Chain)
+	}
+
+	balances, err := e.client.AssetBalances(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to query funding account balances: %w", err)
+	}
+
+	available := fixedpoint.Zero
+	for _, balance := range balances {
+		if balance.Currency == asset {
+			available = balance.Available
+			break
+		}
+	}
+
+	// the fee is charged from the funding account in addition to the withdrawal amount
+	if required := amount.Add(currency.MinWithdrawalFee); available.Compare(required) < 0 {
+		return fmt.Errorf("insufficient %s funding balance for withdrawal, available: %s, required: %s",
+			asset, available.String(), required.String())
+	}
+
+	toAddress := address
+	if addressTag != "" {
+		toAddress = address + ":" + addressTag
+	}
+
+	resp, err := e.client.NewWithdrawRequest().
+		Currency(asset).
+		Amount(amount.String()).
+		Destination(okexapi.WithdrawalDestinationOnChain).
+		ToAddress(toAddress).
+		Chain(currency.Chain).
+		Fee(currency.MinWithdrawalFee.String()).
+		Do(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to submit withdrawal: %w", err)
+	}
+
+	log.Infof("withdrawal request sent, response: %+v", resp)
+	return nil
+}
+
+// selectWithdrawalChain selects the withdrawable chain of the asset by the network,
+// the network can be omitted only if the asset has one withdrawable chain.
+func selectWithdrawalChain(currencies []okexapi.AssetCurrency, asset, network string) (*okexapi.AssetCurrency, error) {
+	chain := network
+	if chain != "" && !strings.Contains(chain, "-") {
+		chain = asset + "-" + chain
+	}
+
+	var candidates []okexapi.AssetCurrency
+	for _, c := range currencies {
+		if c.Currency != asset || !c.CanWithdraw {
+			continue
+		}
+
+		if chain != "" && !strings.EqualFold(c.Chain, chain) {
+			continue
+		}
+
+		candidates = append(candidates, c)
+	}
+
+	switch len(candidates) {
+	case 0:
+		if chain != "" {
+			return nil, fmt.Errorf("%s is not withdrawable on chain %s", asset, chain)
+		}
+
+		return nil, fmt.Errorf("%s is not withdrawable", asset)
+
+	case 1:
+		return &candidates[0], nil
+
+	default:
+		var chains []string
+		for _, c := range candidates {
+			chains = append(chains, c.Chain)
+		}
+
+		return nil, fmt.Errorf("%s is a multi-chain asset, the network is required, available chains: %v", asset, chains)
+	}
+}
+
+// QueryServerTime returns the okex server time
+func (e *Exchange) QueryServerTime(ctx context.Context) (time.Time, error) {
+	if err := queryServerTimeLimiter.Wait(ctx); err != nil {
+		return time.Time{}, fmt.Errorf("server time rate limiter wait error: %w", err)
+	}
+
+	serverTime, err := e.client.NewGetSystemTimeRequest().Do(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return serverTime.Timestamp.Time(), nil
+}
+
+// SyncServerTime measures the clock skew with the server time and offsets the timestamp of the signed requests,
+// okex rejects the signed requests whose timestamp differs from the server time by more than 30 seconds.
+// The clock skew is re-measured every serverTimeSyncInterval until the context is done,
+// even if the first measurement fails, so that a temporary failure on startup is recovered.
+func (e *Exchange) SyncServerTime(ctx context.Context) error {
+	interval := serverTimeSyncInterval
+	e.serverTimeSyncOnce.Do(func() {
+		go func() {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+
+				case <-ticker.C:
+					if err := e.setServerTimeOffset(ctx); err != nil {
+						log.WithError(err).Error("can not set server time offset")
+					}
+				}
+			}
+		}()
+	})
+
+	return e.setServerTimeOffset(ctx)
+}
+
+func (e *Exchange) setServerTimeOffset(ctx context.Context) error {
+	if err := queryServerTimeLimiter.Wait(ctx); err != nil {
+		return fmt.Errorf("server time rate limiter wait error: %w", err)
+	}
+
+	if _, err := e.client.SetTimeOffsetFromServer(ctx, okexapi.DefaultMaxClockSkew); err != nil {
+		return fmt.Errorf("failed to set server time offset: %w", err)
+	}
+
+	return nil
+}
+
+// Ping checks the connectivity and the api credentials with the account balance api, which is a lightweight authenticated api
+func (e *Exchange) Ping(ctx context.Context) error {
+	if err := queryAccountLimiter.Wait(ctx); err != nil {
+		return fmt.Errorf("account rate limiter wait error: %w", err)
+	}
+
+	if _, err := e.client.NewGetAccountInfoRequest().Do(ctx); err != nil {
+		return fmt.Errorf("okex ping error: %w", err)
+	}
+
+	return nil
+}
+
+// QuerySubAccounts returns the sub-accounts of the master account, this API requires the master account api key.
+func (e *Exchange) QuerySubAccounts(ctx context.Context) ([]okexapi.SubAccount, error) {
+	if err := querySubAccountLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("sub-account rate limiter wait error: %w", err)
+	}
+
+	subAccounts, err := e.client.NewGetSubAccountsRequest().Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sub-accounts: %w", err)
+	}
+
+	return subAccounts, nil
+}
+
+// QuerySubAccountBalances returns the trading account balances of the given sub-account,
+// this API requires the master account api key.
+func (e *Exchange) QuerySubAccountBalances(ctx context.Context, name string) (types.BalanceMap, error) {
+	if err := querySubAccountLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("sub-account rate limiter wait error: %w", err)
+	}
+
+	accountBalances, err := e.client.NewGetSubAccountBalancesRequest().SubAccount(name).Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sub-account %s balances: %w", name, err)
+	}
+
+	if len(accountBalances) != 1 {
+		return nil, fmt.Errorf("unexpected length of sub-account balances: %v", accountBalances)
+	}
+
+	return toGlobalBalance(&accountBalances[0]), nil
+}
+
+// QueryTotalEquity returns the total equity of the account and its currency, this is the figure
+// calculated by okex across all currencies, which is more accurate than summing the balances at spot prices
+// for the multi-currency margin and portfolio margin accounts.
+func (e *Exchange) QueryTotalEquity(ctx context.Context) (fixedpoint.Value, string, error) {
+	if err := queryAccountLimiter.Wait(ctx); err != nil {
+		return fixedpoint.Zero, "", fmt.Errorf("account rate limiter wait error: %w", err)
+	}
+
+	accounts, err := e.client.NewGetAccountInfoRequest().Do(ctx)
+	if err != nil {
+		return fixedpoint.Zero, "", err
+	}
+
+	if len(accounts) != 1 {
+		return fixedpoint.Zero, "", fmt.Errorf("unexpected length of balances: %v", accounts)
+	}
+
+	equity, currency := toGlobalTotalEquity(&accounts[0])
+	return equity, currency, nil
+}
+
+// validateSubmitOrder checks the order against the market filters before sending it to the API,
+// the validation is skipped if the market is not given.
+func validateSubmitOrder(order types.SubmitOrder) error {
+	market := order.Market
+	if len(market.Symbol) == 0 {
+		return nil
+	}
+
+	if market.MinQuantity.Sign() > 0 && order.Quantity.Compare(market.MinQuantity) < 0 {
+		return fmt.Errorf("%s order quantity %s is less than the min quantity %s", order.Symbol, order.Quantity, market.MinQuantity)
+	}
+
+	if order.Quantity.Sign() <= 0 {
+		return fmt.Errorf("%s order quantity %s should be positive", order.Symbol, order.Quantity)
+	}
+
+	if market.StepSize.Sign() > 0 {
+		steps := order.Quantity.Div(market.StepSize).Round(0, fixedpoint.HalfUp)
+		if steps.Mul(market.StepSize).Compare(order.Quantity) != 0 {
+			return fmt.Errorf("%s order quantity %s is not a multiple of the step size %s", order.Symbol, order.Quantity, market.StepSize)
+		}
+	}
+
+	// market orders don't have a price, we can't check the notional here
+	if market.MinNotional.Sign() > 0 && order.Price.Sign() > 0 {
+		notional := order.Quantity.Mul(order.Price)
+		if notional.Compare(market.MinNotional) < 0 {
+			return fmt.Errorf("%s order notional %s is less than the min notional %s", order.Symbol, notional, market.MinNotional)
+		}
+	}
+
+	return nil
+}
+
 func (e *Exchange) SubmitOrder(ctx context.Context, order types.SubmitOrder) (*types.Order, error) {
+	if err := validateSubmitOrder(order); err != nil {
+		return nil, err
+	}
+
 	orderReq := e.client.NewPlaceOrderRequest()
 
 	orderReq.InstrumentID(toLocalSymbol(order.Symbol))
@@ -240,15 +523,20 @@ func (e *Exchange) SubmitOrder(ctx context.Context, order types.SubmitOrder) (*t
 		return nil, fmt.Errorf("place order rate limiter wait error: %w", err)
 	}
 
-	_, err = strconv.ParseInt(order.ClientOrderID, 10, 64)
-	if err != nil {
-		return nil, fmt.Errorf("client order id should be numberic: %s, err: %w", order.ClientOrderID, err)
+	// the client order id is generated if it's not given, so that the order can be found by it after a timeout
+	clientOrderID := newClientOrderID(order.ClientOrderID)
+	if len(clientOrderID) > 0 {
+		_, err = strconv.ParseInt(clientOrderID, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("client order id should be numberic: %s, err: %w", clientOrderID, err)
+		}
+		orderReq.ClientOrderID(clientOrderID)
 	}
-	orderReq.ClientOrderID(order.ClientOrderID)
 
+	// the generated client order id is returned with the error, so that the retry can reuse it
 	orders, err := orderReq.Do(ctx)
 	if err != nil {
-		return nil, err
+		return nil, types.NewSubmitOrderError(fmt.Errorf("failed to place order, clientOrderId: %s, err: %w", clientOrderID, err), clientOrderID)
 	}
 
 	if len(orders) != 1 {
@@ -261,7 +549,11 @@ func (e *Exchange) SubmitOrder(ctx context.Context, order types.SubmitOrder) (*t
 		ClientOrderID: orders[0].ClientOrderID,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to query order by id: %s, clientOrderId: %s, err: %w", orders[0].OrderID, orders[0].ClientOrderID, err)
+		return nil, types.NewSubmitOrderError(fmt.Errorf("failed to query order by id: %s, clientOrderId: %s, err: %w", orders[0].OrderID, orders[0].ClientOrderID, err), clientOrderID)
+	}
+
+	if len(orderRes.ClientOrderID) == 0 {
+		orderRes.ClientOrderID = clientOrderID
 	}
 
 	return orderRes, nil
@@ -298,8 +590,9 @@ func (e *Exchange) SubmitOrder(ctx context.Context, order types.SubmitOrder) (*t
 	*/
 }
 
-// QueryOpenOrders retrieves the pending orders. The data returned is ordered by createdTime, and we utilized the
-// `After` parameter to acquire all orders.
+// QueryOpenOrders retrieves the pending orders. The data returned is ordered by createdTime (newest first),
+// the api returns at most 100 orders per page, so we utilized the `After` parameter with the last order id
+// of the page to acquire all orders until a page is not full.
 func (e *Exchange) QueryOpenOrders(ctx context.Context, symbol string) (orders []types.Order, err error) {
 	instrumentID := toLocalSymbol(symbol)
 
@@ -311,7 +604,11 @@ func (e *Exchange) QueryOpenOrders(ctx context.Context, symbol string) (orders [
 
 		req := e.client.NewGetOpenOrdersRequest().
 			InstrumentID(instrumentID).
-			After(strconv.FormatInt(nextCursor, 10))
+			Limit(strconv.Itoa(defaultQueryLimit))
+		if nextCursor > 0 {
+			req.After(strconv.FormatInt(nextCursor, 10))
+		}
+
 		openOrders, err := req.Do(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("failed to query open orders: %w", err)
@@ -374,13 +671,42 @@ func (e *Exchange) CancelOrders(ctx context.Context, orders ...types.Order) erro
 	return err
 }
 
+// CancelAllOrders queries the pending orders of the symbol and cancels them in batches,
+// the errors of the failed batches are aggregated into a multierr.
+func (e *Exchange) CancelAllOrders(ctx context.Context, symbol string) error {
+	if len(symbol) == 0 {
+		return ErrSymbolRequired
+	}
+
+	orders, err := e.QueryOpenOrders(ctx, symbol)
+	if err != nil {
+		return err
+	}
+
+	var errs error
+	for start := 0; start < len(orders); start += maxBatchCancelOrderSize {
+		end := start + maxBatchCancelOrderSize
+		if end > len(orders) {
+			end = len(orders)
+		}
+
+		if err := e.CancelOrders(ctx, orders[start:end]...); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("failed to cancel orders [%d:%d]: %w", start, end, err))
+		}
+	}
+
+	return errs
+}
+
 func (e *Exchange) NewStream() types.Stream {
-	return NewStream(e.client, e)
+	stream := NewStream(e.client, e)
+	stream.FuturesSettings = e.FuturesSettings
+	return stream
 }
 
 func (e *Exchange) QueryKLines(ctx context.Context, symbol string, interval types.Interval, options types.KLineQueryOptions) ([]types.KLine, error) {
 	if err := marketDataLimiter.Wait(ctx); err != nil {
-		return nil, err
+		return nil, fmt.Errorf("kline rate limiter wait error: %w", err)
 	}
 
 	intervalParam, err := toLocalInterval(interval)
@@ -422,6 +748,11 @@ func (e *Exchange) QueryKLines(ctx context.Context, symbol string, interval type
 		})
 	}
 
+	// OKEx returns the candles in the newest-first order, sort them in the chronological order
+	sort.Slice(klines, func(i, j int) bool {
+		return klines[i].StartTime.Before(klines[j].StartTime.Time())
+	})
+
 	return klines, nil
 
 }
@@ -433,10 +764,22 @@ func (e *Exchange) QueryOrder(ctx context.Context, q types.OrderQuery) (*types.O
 	if len(q.OrderID) == 0 && len(q.ClientOrderID) == 0 {
 		return nil, errors.New("okex.QueryOrder: OrderId or ClientOrderId is required parameter")
 	}
+
+	if err := queryOrderLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("query order rate limiter wait error: %w", err)
+	}
+
 	req := e.client.NewGetOrderDetailsRequest()
-	req.InstrumentID(toLocalSymbol(q.Symbol)).
-		OrderID(q.OrderID).
-		ClientOrderID(q.ClientOrderID)
+	req.InstrumentID(toLocalSymbol(q.Symbol))
+
+	// the order id takes precedence over the client order id, so set the one that is given
+	if len(q.OrderID) != 0 {
+		req.OrderID(q.OrderID)
+	}
+
+	if len(q.ClientOrderID) != 0 {
+		req.ClientOrderID(q.ClientOrderID)
+	}
 
 	var order *okexapi.OrderDetails
 	order, err := req.Do(ctx)
@@ -449,9 +792,23 @@ func (e *Exchange) QueryOrder(ctx context.Context, q types.OrderQuery) (*types.O
 }
 
 // QueryOrderTrades quires order trades can query trades in last 3 months.
+// OKEx does not support searching for trades by the client order id, so if only the ClientOrderID is given,
+// the order id is resolved by QueryOrder first.
 func (e *Exchange) QueryOrderTrades(ctx context.Context, q types.OrderQuery) (trades []types.Trade, err error) {
-	if len(q.ClientOrderID) != 0 {
-		log.Warn("!!!OKEX EXCHANGE API NOTICE!!! Okex does not support searching for trades using OrderClientId.")
+	if len(q.OrderID) == 0 && len(q.ClientOrderID) != 0 {
+		if len(q.Symbol) == 0 {
+			return nil, fmt.Errorf("can not resolve the order id of client order id %s: %w", q.ClientOrderID, ErrSymbolRequired)
+		}
+
+		order, err := e.QueryOrder(ctx, types.OrderQuery{
+			Symbol:        q.Symbol,
+			ClientOrderID: q.ClientOrderID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve the order id of client order id %s, err: %w", q.ClientOrderID, err)
+		}
+
+		q.OrderID = strconv.FormatUint(order.OrderID, 10)
 	}
 
 	req := e.client.NewGetTransactionHistoryRequest()
@@ -599,6 +956,59 @@ func (e *Exchange) QueryTrades(ctx context.Context, symbol string, options *type
 	return trades, nil
 }
 
+// QueryFundingRateHistory queries the funding rate history of the perpetual swap of the symbol in [since, until],
+// the returned funding rates are sorted by the funding time in ascending order.
+func (e *Exchange) QueryFundingRateHistory(ctx context.Context, symbol string, since, until time.Time) ([]types.FundingRate, error) {
+	if len(symbol) == 0 {
+		return nil, ErrSymbolRequired
+	}
+
+	if until.IsZero() {
+		until = time.Now()
+	}
+
+	instrumentID := toLocalPerpetualSymbol(symbol)
+
+	var rates []types.FundingRate
+	// the records are returned in descending order, we paginate backward from the until time
+	after := until.Add(time.Millisecond)
+	for {
+		if err := queryFundingRateLimiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("query funding rate history rate limiter wait error: %w", err)
+		}
+
+		req := e.client.NewGetFundingRateHistoryRequest().
+			InstrumentID(instrumentID).
+			After(after).
+			Limit(defaultQueryLimit)
+
+		if !since.IsZero() {
+			req.Before(since.Add(-time.Millisecond))
+		}
+
+		res, err := req.Do(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query funding rate history: %w", err)
+		}
+
+		for _, rate := range res {
+			rates = append(rates, toGlobalFundingRate(rate))
+		}
+
+		if len(res) < defaultQueryLimit {
+			break
+		}
+
+		after = res[len(res)-1].FundingTime.Time()
+	}
+
+	sort.Slice(rates, func(i, j int) bool {
+		return rates[i].FundingTime.Before(rates[j].FundingTime)
+	})
+
+	return rates, nil
+}
+
 func (e *Exchange) SupportedInterval() map[types.Interval]int {
 	return SupportedIntervals
 }
@@ -10,11 +10,13 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/c9s/bbgo/pkg/fixedpoint"
 	"github.com/c9s/requestgen"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 )
 
 const defaultHTTPTimeout = time.Second * 15
@@ -22,6 +24,10 @@ const RestBaseURL = "https://www.okex.com/"
 const PublicWebSocketURL = "wss://ws.okex.com:8443/ws/v5/public"
 const PrivateWebSocketURL = "wss://ws.okex.com:8443/ws/v5/private"
 
+// DefaultMaxClockSkew is the default threshold of the local clock skew,
+// okex rejects the signed requests whose timestamp differs from the server time by more than 30 seconds.
+const DefaultMaxClockSkew = 5 * time.Second
+
 type SideType string
 
 const (
@@ -56,6 +62,8 @@ const (
 	OrderStateLive            OrderState = "live"
 	OrderStatePartiallyFilled OrderState = "partially_filled"
 	OrderStateFilled          OrderState = "filled"
+	// OrderStateMMPCanceled is the order canceled by the market maker protection
+	OrderStateMMPCanceled OrderState = "mmp_canceled"
 )
 
 func (o OrderState) IsWorking() bool {
@@ -66,6 +74,10 @@ type RestClient struct {
 	requestgen.BaseAPIClient
 
 	Key, Secret, Passphrase string
+
+	// timeOffset is the measured clock skew (local time - server time) in milliseconds,
+	// it's subtracted from the timestamp of the signed requests
+	timeOffset int64
 }
 
 var parsedBaseURL *url.URL
@@ -123,7 +135,7 @@ func (c *RestClient) NewAuthenticatedRequest(ctx context.Context, method, refURL
 	}
 
 	// set location to UTC so that it outputs "2020-12-08T09:08:57.715Z"
-	t := time.Now().In(time.UTC)
+	t := time.Now().Add(-c.TimeOffset()).In(time.UTC)
 	timestamp := t.Format("2006-01-02T15:04:05.999Z07:00")
 
 	var body []byte
@@ -161,6 +173,42 @@ func (c *RestClient) NewAuthenticatedRequest(ctx context.Context, method, refURL
 	return req, nil
 }
 
+// TimeOffset returns the offset subtracted from the timestamp of the signed requests
+func (c *RestClient) TimeOffset() time.Duration {
+	return time.Duration(atomic.LoadInt64(&c.timeOffset)) * time.Millisecond
+}
+
+// ClockSkew queries the server time and returns the skew of the local clock,
+// a positive skew means the local clock is ahead of the server clock.
+// The local time is taken at the middle of the round trip to compensate the network latency.
+func (c *RestClient) ClockSkew(ctx context.Context) (time.Duration, error) {
+	start := time.Now()
+	serverTime, err := c.NewGetSystemTimeRequest().Do(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	rtt := time.Since(start)
+	localTime := start.Add(rtt / 2)
+	return localTime.Sub(serverTime.Timestamp.Time()), nil
+}
+
+// SetTimeOffsetFromServer measures the clock skew and offsets the timestamp of the signed requests by the skew,
+// a warning is logged when the skew exceeds maxSkew.
+func (c *RestClient) SetTimeOffsetFromServer(ctx context.Context, maxSkew time.Duration) (time.Duration, error) {
+	skew, err := c.ClockSkew(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if maxSkew > 0 && (skew > maxSkew || skew < -maxSkew) {
+		logrus.Warnf("okex: local clock skew %s exceeds %s, please sync the system clock, the request timestamps are offset by the skew", skew, maxSkew)
+	}
+
+	atomic.StoreInt64(&c.timeOffset, skew.Milliseconds())
+	return skew, nil
+}
+
 type AssetBalance struct {
 	Currency  string           `json:"ccy"`
 	Balance   fixedpoint.Value `json:"bal"`
@@ -0,0 +1,66 @@
+package okexapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+)
+
+func TestClient_SetTimeOffsetFromServer(t *testing.T) {
+	hook := test.NewGlobal()
+	defer hook.Reset()
+
+	// the local clock is 10 seconds ahead of the server clock
+	serverTime := time.Now().Add(-10 * time.Second)
+
+	var req *http.Request
+	client := NewClient()
+	client.Auth("key", "secret", "passphrase")
+	client.HttpClient = httptesting.HttpClientSaver(&req, fmt.Sprintf(`{"code":"0","msg":"","data":[{"ts":"%d"}]}`, serverTime.UnixMilli()))
+
+	skew, err := client.SetTimeOffsetFromServer(context.Background(), DefaultMaxClockSkew)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, "/api/v5/public/time", req.URL.Path)
+	assert.InDelta(t, (10 * time.Second).Seconds(), skew.Seconds(), 1.0)
+	assert.Equal(t, skew.Milliseconds(), client.TimeOffset().Milliseconds())
+
+	if entry := hook.LastEntry(); assert.NotNil(t, entry) {
+		assert.Equal(t, logrus.WarnLevel, entry.Level)
+		assert.Contains(t, entry.Message, "clock skew")
+	}
+
+	// the signed request timestamp is offset by the skew
+	signedReq, err := client.NewAuthenticatedRequest(context.Background(), "GET", "/api/v5/account/balance", nil, nil)
+	if assert.NoError(t, err) {
+		ts, err := time.Parse("2006-01-02T15:04:05.999Z07:00", signedReq.Header.Get("OK-ACCESS-TIMESTAMP"))
+		if assert.NoError(t, err) {
+			assert.InDelta(t, 0, time.Since(ts.Add(skew)).Seconds(), 1.0)
+			assert.InDelta(t, 0, ts.Sub(serverTime).Seconds(), 1.0)
+		}
+	}
+}
+
+func TestClient_SetTimeOffsetFromServer_WithinThreshold(t *testing.T) {
+	hook := test.NewGlobal()
+	defer hook.Reset()
+
+	client := NewClient()
+	client.HttpClient = httptesting.HttpClientWithContent(fmt.Sprintf(`{"code":"0","msg":"","data":[{"ts":"%d"}]}`, time.Now().UnixMilli()))
+
+	skew, err := client.SetTimeOffsetFromServer(context.Background(), DefaultMaxClockSkew)
+	if assert.NoError(t, err) {
+		assert.Less(t, skew.Abs(), time.Second)
+		assert.Nil(t, hook.LastEntry())
+	}
+}
@@ -9,6 +9,15 @@ import (
 //go:generate -command GetRequest requestgen -method GET -responseType .APIResponse -responseDataField Data
 //go:generate -command PostRequest requestgen -method POST -responseType .APIResponse -responseDataField Data
 
+type InstrumentState string
+
+const (
+	InstrumentStateLive    InstrumentState = "live"
+	InstrumentStateSuspend InstrumentState = "suspend"
+	InstrumentStatePreOpen InstrumentState = "preopen"
+	InstrumentStateExpired InstrumentState = "expired"
+)
+
 type InstrumentInfo struct {
 	InstrumentType        string                     `json:"instType"`
 	InstrumentID          string                     `json:"instId"`
@@ -27,14 +36,14 @@ type InstrumentInfo struct {
 	MinSize fixedpoint.Value `json:"minSz"`
 
 	// instrument status
-	State string `json:"state"`
+	State InstrumentState `json:"state"`
 }
 
 //go:generate GetRequest -url "/api/v5/public/instruments" -type GetInstrumentsInfoRequest -responseDataType []InstrumentInfo
 type GetInstrumentsInfoRequest struct {
 	client requestgen.APIClient
 
-	instType InstrumentType `param:"instType,query" validValues:"SPOT"`
+	instType InstrumentType `param:"instType,query" validValues:"SPOT,SWAP"`
 
 	instId *string `param:"instId,query"`
 }
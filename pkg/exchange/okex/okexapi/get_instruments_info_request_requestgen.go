@@ -29,7 +29,7 @@ func (g *GetInstrumentsInfoRequest) GetQueryParameters() (url.Values, error) {
 
 	// TEMPLATE check-valid-values
 	switch instType {
-	case "SPOT":
+	case "SPOT", "SWAP":
 		params["instType"] = instType
 
 	default:
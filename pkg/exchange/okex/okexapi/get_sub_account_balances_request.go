@@ -0,0 +1,21 @@
+package okexapi
+
+import (
+	"github.com/c9s/requestgen"
+)
+
+//go:generate -command GetRequest requestgen -method GET -responseType .APIResponse -responseDataField Data
+//go:generate -command PostRequest requestgen -method POST -responseType .APIResponse -responseDataField Data
+
+//go:generate GetRequest -url "/api/v5/account/subaccount/balances" -type GetSubAccountBalancesRequest -responseDataType []Account
+type GetSubAccountBalancesRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	subAccount string `param:"subAcct,query"`
+}
+
+func (c *RestClient) NewGetSubAccountBalancesRequest() *GetSubAccountBalancesRequest {
+	return &GetSubAccountBalancesRequest{
+		client: c,
+	}
+}
@@ -0,0 +1,40 @@
+package okexapi
+
+import (
+	"github.com/c9s/requestgen"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+//go:generate -command GetRequest requestgen -method GET -responseType .APIResponse -responseDataField Data
+//go:generate -command PostRequest requestgen -method POST -responseType .APIResponse -responseDataField Data
+
+type SubAccount struct {
+	SubAccount  string                     `json:"subAcct"`
+	Label       string                     `json:"label"`
+	Type        string                     `json:"type"`
+	UID         string                     `json:"uid"`
+	Enable      bool                       `json:"enable"`
+	CanTransOut bool                       `json:"canTransOut"`
+	CreatedTime types.MillisecondTimestamp `json:"ts"`
+}
+
+//go:generate GetRequest -url "/api/v5/users/subaccount/list" -type GetSubAccountsRequest -responseDataType []SubAccount
+type GetSubAccountsRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	enable     *bool   `param:"enable,query"`
+	subAccount *string `param:"subAcct,query"`
+
+	// Pagination of data to return records earlier than the requested ts
+	after *string `param:"after,query"`
+	// Pagination of data to return records newer than the requested ts
+	before *string `param:"before,query"`
+	limit  *string `param:"limit,query"`
+}
+
+func (c *RestClient) NewGetSubAccountsRequest() *GetSubAccountsRequest {
+	return &GetSubAccountsRequest{
+		client: c,
+	}
+}
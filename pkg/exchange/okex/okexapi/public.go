@@ -4,12 +4,56 @@ import (
 	"context"
 	"encoding/json"
 	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/c9s/bbgo/pkg/fixedpoint"
 	"github.com/c9s/bbgo/pkg/types"
 	"github.com/pkg/errors"
 )
 
+func (s *RestClient) NewGetSystemTimeRequest() *GetSystemTimeRequest {
+	return &GetSystemTimeRequest{
+		client: s,
+	}
+}
+
+type SystemTime struct {
+	Timestamp types.MillisecondTimestamp `json:"ts"`
+}
+
+type GetSystemTimeRequest struct {
+	client *RestClient
+}
+
+func (r *GetSystemTimeRequest) Do(ctx context.Context) (*SystemTime, error) {
+	req, err := r.client.NewRequest(ctx, "GET", "/api/v5/public/time", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := r.client.SendRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiResponse APIResponse
+	if err := response.DecodeJSON(&apiResponse); err != nil {
+		return nil, err
+	}
+
+	var data []SystemTime
+	if err := json.Unmarshal(apiResponse.Data, &data); err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		return nil, errors.New("empty system time data")
+	}
+
+	return &data[0], nil
+}
+
 func (s *RestClient) NewGetFundingRate() *GetFundingRateRequest {
 	return &GetFundingRateRequest{
 		client: s,
@@ -65,3 +109,91 @@ func (r *GetFundingRateRequest) Do(ctx context.Context) (*FundingRate, error) {
 
 	return &data[0], nil
 }
+
+func (s *RestClient) NewGetFundingRateHistoryRequest() *GetFundingRateHistoryRequest {
+	return &GetFundingRateHistoryRequest{
+		client: s,
+	}
+}
+
+type FundingRateHistory struct {
+	InstrumentType InstrumentType             `json:"instType"`
+	InstrumentID   string                     `json:"instId"`
+	FundingRate    fixedpoint.Value           `json:"fundingRate"`
+	RealizedRate   fixedpoint.Value           `json:"realizedRate"`
+	FundingTime    types.MillisecondTimestamp `json:"fundingTime"`
+	Method         string                     `json:"method"`
+}
+
+type GetFundingRateHistoryRequest struct {
+	client *RestClient
+
+	instId string
+
+	// before and after are the fundingTime pagination,
+	// after returns the records earlier than the given time, before returns the records newer than the given time
+	before, after *time.Time
+
+	limit int
+}
+
+func (r *GetFundingRateHistoryRequest) InstrumentID(instId string) *GetFundingRateHistoryRequest {
+	r.instId = instId
+	return r
+}
+
+func (r *GetFundingRateHistoryRequest) Before(before time.Time) *GetFundingRateHistoryRequest {
+	r.before = &before
+	return r
+}
+
+func (r *GetFundingRateHistoryRequest) After(after time.Time) *GetFundingRateHistoryRequest {
+	r.after = &after
+	return r
+}
+
+// Limit sets the number of results per request, the maximum is 100, the default is 100.
+func (r *GetFundingRateHistoryRequest) Limit(limit int) *GetFundingRateHistoryRequest {
+	r.limit = limit
+	return r
+}
+
+// Do returns the funding rate history in descending order of the funding time
+func (r *GetFundingRateHistoryRequest) Do(ctx context.Context) ([]FundingRateHistory, error) {
+	var params = url.Values{}
+	params.Add("instId", r.instId)
+
+	if r.before != nil {
+		params.Add("before", strconv.FormatInt(r.before.UnixMilli(), 10))
+	}
+
+	if r.after != nil {
+		params.Add("after", strconv.FormatInt(r.after.UnixMilli(), 10))
+	}
+
+	if r.limit > 0 {
+		params.Add("limit", strconv.Itoa(r.limit))
+	}
+
+	req, err := r.client.NewRequest(ctx, "GET", "/api/v5/public/funding-rate-history", params, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := r.client.SendRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiResponse APIResponse
+	if err := response.DecodeJSON(&apiResponse); err != nil {
+		return nil, err
+	}
+
+	var data []FundingRateHistory
+	if err := json.Unmarshal(apiResponse.Data, &data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
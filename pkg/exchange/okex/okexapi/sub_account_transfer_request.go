@@ -0,0 +1,46 @@
+package okexapi
+
+import (
+	"github.com/c9s/requestgen"
+)
+
+//go:generate -command GetRequest requestgen -method GET -responseType .APIResponse -responseDataField Data
+//go:generate -command PostRequest requestgen -method POST -responseType .APIResponse -responseDataField Data
+
+type AccountType string
+
+const (
+	AccountTypeFunding AccountType = "6"
+	AccountTypeTrading AccountType = "18"
+)
+
+type SubAccountTransferResponse struct {
+	TransferID string `json:"transId"`
+}
+
+//go:generate PostRequest -url "/api/v5/asset/subaccount/transfer" -type SubAccountTransferRequest -responseDataType []SubAccountTransferResponse
+type SubAccountTransferRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	currency string `param:"ccy"`
+	amount   string `param:"amt"`
+
+	// from is the account type of the source, 6: funding account, 18: trading account
+	from AccountType `param:"from" validValues:"6,18"`
+	// to is the account type of the destination, 6: funding account, 18: trading account
+	to AccountType `param:"to" validValues:"6,18"`
+
+	fromSubAccount string `param:"fromSubAccount"`
+	toSubAccount   string `param:"toSubAccount"`
+
+	// loanTransfer indicates whether the borrowed coins can be transferred out under Multi-currency margin/Portfolio margin
+	loanTransfer *bool `param:"loanTrans"`
+}
+
+func (c *RestClient) NewSubAccountTransferRequest() *SubAccountTransferRequest {
+	return &SubAccountTransferRequest{
+		client: c,
+		from:   AccountTypeTrading,
+		to:     AccountTypeTrading,
+	}
+}
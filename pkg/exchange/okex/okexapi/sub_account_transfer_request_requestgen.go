@@ -0,0 +1,254 @@
+// Code generated by "requestgen -method POST -responseType .APIResponse -responseDataField Data -url /api/v5/asset/subaccount/transfer -type SubAccountTransferRequest -responseDataType []SubAccountTransferResponse"; DO NOT EDIT.
+
+package okexapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+)
+
+func (s *SubAccountTransferRequest) Currency(currency string) *SubAccountTransferRequest {
+	s.currency = currency
+	return s
+}
+
+func (s *SubAccountTransferRequest) Amount(amount string) *SubAccountTransferRequest {
+	s.amount = amount
+	return s
+}
+
+func (s *SubAccountTransferRequest) From(from AccountType) *SubAccountTransferRequest {
+	s.from = from
+	return s
+}
+
+func (s *SubAccountTransferRequest) To(to AccountType) *SubAccountTransferRequest {
+	s.to = to
+	return s
+}
+
+func (s *SubAccountTransferRequest) FromSubAccount(fromSubAccount string) *SubAccountTransferRequest {
+	s.fromSubAccount = fromSubAccount
+	return s
+}
+
+func (s *SubAccountTransferRequest) ToSubAccount(toSubAccount string) *SubAccountTransferRequest {
+	s.toSubAccount = toSubAccount
+	return s
+}
+
+func (s *SubAccountTransferRequest) LoanTransfer(loanTransfer bool) *SubAccountTransferRequest {
+	s.loanTransfer = &loanTransfer
+	return s
+}
+
+// GetQueryParameters builds and checks the query parameters and returns url.Values
+func (s *SubAccountTransferRequest) GetQueryParameters() (url.Values, error) {
+	var params = map[string]interface{}{}
+
+	query := url.Values{}
+	for _k, _v := range params {
+		query.Add(_k, fmt.Sprintf("%v", _v))
+	}
+
+	return query, nil
+}
+
+// GetParameters builds and checks the parameters and return the result in a map object
+func (s *SubAccountTransferRequest) GetParameters() (map[string]interface{}, error) {
+	var params = map[string]interface{}{}
+	// check currency field -> json key ccy
+	currency := s.currency
+
+	// assign parameter of currency
+	params["ccy"] = currency
+	// check amount field -> json key amt
+	amount := s.amount
+
+	// assign parameter of amount
+	params["amt"] = amount
+	// check from field -> json key from
+	from := s.from
+
+	// TEMPLATE check-valid-values
+	switch from {
+	case "6", "18":
+		params["from"] = from
+
+	default:
+		return nil, fmt.Errorf("from value %v is invalid", from)
+
+	}
+	// END TEMPLATE check-valid-values
+
+	// assign parameter of from
+	params["from"] = from
+	// check to field -> json key to
+	to := s.to
+
+	// TEMPLATE check-valid-values
+	switch to {
+	case "6", "18":
+		params["to"] = to
+
+	default:
+		return nil, fmt.Errorf("to value %v is invalid", to)
+
+	}
+	// END TEMPLATE check-valid-values
+
+	// assign parameter of to
+	params["to"] = to
+	// check fromSubAccount field -> json key fromSubAccount
+	fromSubAccount := s.fromSubAccount
+
+	// assign parameter of fromSubAccount
+	params["fromSubAccount"] = fromSubAccount
+	// check toSubAccount field -> json key toSubAccount
+	toSubAccount := s.toSubAccount
+
+	// assign parameter of toSubAccount
+	params["toSubAccount"] = toSubAccount
+	// check loanTransfer field -> json key loanTrans
+	if s.loanTransfer != nil {
+		loanTransfer := *s.loanTransfer
+
+		// assign parameter of loanTransfer
+		params["loanTrans"] = loanTransfer
+	} else {
+	}
+
+	return params, nil
+}
+
+// GetParametersQuery converts the parameters from GetParameters into the url.Values format
+func (s *SubAccountTransferRequest) GetParametersQuery() (url.Values, error) {
+	query := url.Values{}
+
+	params, err := s.GetParameters()
+	if err != nil {
+		return query, err
+	}
+
+	for _k, _v := range params {
+		if s.isVarSlice(_v) {
+			s.iterateSlice(_v, func(it interface{}) {
+				query.Add(_k+"[]", fmt.Sprintf("%v", it))
+			})
+		} else {
+			query.Add(_k, fmt.Sprintf("%v", _v))
+		}
+	}
+
+	return query, nil
+}
+
+// GetParametersJSON converts the parameters from GetParameters into the JSON format
+func (s *SubAccountTransferRequest) GetParametersJSON() ([]byte, error) {
+	params, err := s.GetParameters()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(params)
+}
+
+// GetSlugParameters builds and checks the slug parameters and return the result in a map object
+func (s *SubAccountTransferRequest) GetSlugParameters() (map[string]interface{}, error) {
+	var params = map[string]interface{}{}
+
+	return params, nil
+}
+
+func (s *SubAccountTransferRequest) applySlugsToUrl(url string, slugs map[string]string) string {
+	for _k, _v := range slugs {
+		needleRE := regexp.MustCompile(":" + _k + "\\b")
+		url = needleRE.ReplaceAllString(url, _v)
+	}
+
+	return url
+}
+
+func (s *SubAccountTransferRequest) iterateSlice(slice interface{}, _f func(it interface{})) {
+	sliceValue := reflect.ValueOf(slice)
+	for _i := 0; _i < sliceValue.Len(); _i++ {
+		it := sliceValue.Index(_i).Interface()
+		_f(it)
+	}
+}
+
+func (s *SubAccountTransferRequest) isVarSlice(_v interface{}) bool {
+	rt := reflect.TypeOf(_v)
+	switch rt.Kind() {
+	case reflect.Slice:
+		return true
+	}
+	return false
+}
+
+func (s *SubAccountTransferRequest) GetSlugsMap() (map[string]string, error) {
+	slugs := map[string]string{}
+	params, err := s.GetSlugParameters()
+	if err != nil {
+		return slugs, nil
+	}
+
+	for _k, _v := range params {
+		slugs[_k] = fmt.Sprintf("%v", _v)
+	}
+
+	return slugs, nil
+}
+
+// GetPath returns the request path of the API
+func (s *SubAccountTransferRequest) GetPath() string {
+	return "/api/v5/asset/subaccount/transfer"
+}
+
+// Do generates the request object and send the request object to the API endpoint
+func (s *SubAccountTransferRequest) Do(ctx context.Context) ([]SubAccountTransferResponse, error) {
+
+	params, err := s.GetParameters()
+	if err != nil {
+		return nil, err
+	}
+	query := url.Values{}
+
+	var apiURL string
+
+	apiURL = s.GetPath()
+
+	req, err := s.client.NewAuthenticatedRequest(ctx, "POST", apiURL, query, params)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.client.SendRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiResponse APIResponse
+	if err := response.DecodeJSON(&apiResponse); err != nil {
+		return nil, err
+	}
+
+	type responseValidator interface {
+		Validate() error
+	}
+	validator, ok := interface{}(apiResponse).(responseValidator)
+	if ok {
+		if err := validator.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	var data []SubAccountTransferResponse
+	if err := json.Unmarshal(apiResponse.Data, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
@@ -0,0 +1,54 @@
+package okexapi
+
+import (
+	"github.com/c9s/requestgen"
+)
+
+//go:generate -command GetRequest requestgen -method GET -responseType .APIResponse -responseDataField Data
+//go:generate -command PostRequest requestgen -method POST -responseType .APIResponse -responseDataField Data
+
+type WithdrawalDestination string
+
+const (
+	// WithdrawalDestinationInternal is the internal transfer to another okex account
+	WithdrawalDestinationInternal WithdrawalDestination = "3"
+	// WithdrawalDestinationOnChain is the on-chain withdrawal
+	WithdrawalDestinationOnChain WithdrawalDestination = "4"
+)
+
+type WithdrawResponse struct {
+	Currency     string `json:"ccy"`
+	Chain        string `json:"chain"`
+	Amount       string `json:"amt"`
+	WithdrawalID string `json:"wdId"`
+	ClientID     string `json:"clientId"`
+}
+
+//go:generate PostRequest -url "/api/v5/asset/withdrawal" -type WithdrawRequest -responseDataType []WithdrawResponse
+type WithdrawRequest struct {
+	client requestgen.AuthenticatedAPIClient
+
+	currency string `param:"ccy"`
+	amount   string `param:"amt"`
+
+	// destination is the withdrawal method, 3: internal transfer, 4: on-chain withdrawal
+	destination WithdrawalDestination `param:"dest" validValues:"3,4"`
+
+	// toAddress is the destination address, the address tag is appended with ":" for the assets that need it, e.g., "ARDOR-7JF3-8F2E-QUWZ-CAN7F:123456"
+	toAddress string `param:"toAddr"`
+
+	// fee is the transaction fee of the on-chain withdrawal
+	fee *string `param:"fee"`
+
+	// chain is the chain name of the multi-chain assets, e.g., USDT-TRC20, USDT-ERC20
+	chain *string `param:"chain"`
+
+	clientID *string `param:"clientId"`
+}
+
+func (c *RestClient) NewWithdrawRequest() *WithdrawRequest {
+	return &WithdrawRequest{
+		client:      c,
+		destination: WithdrawalDestinationOnChain,
+	}
+}
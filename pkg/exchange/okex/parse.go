@@ -22,6 +22,8 @@ const (
 	ChannelAccount      Channel = "account"
 	ChannelMarketTrades Channel = "trades"
 	ChannelOrderTrades  Channel = "orders"
+	ChannelMarkPrice    Channel = "mark-price"
+	ChannelPositions    Channel = "positions"
 )
 
 type ActionType string
@@ -67,6 +69,22 @@ func parseWebSocketEvent(in []byte) (interface{}, error) {
 		}
 		return trade, nil
 
+	case ChannelMarkPrice:
+		var markPrices []MarkPriceEvent
+		err = json.Unmarshal(event.Data, &markPrices)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal data into MarkPriceEvent: %+v, err: %w", string(event.Data), err)
+		}
+		return markPrices, nil
+
+	case ChannelPositions:
+		var positions []PositionEvent
+		err = json.Unmarshal(event.Data, &positions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal data into PositionEvent: %+v, err: %w", string(event.Data), err)
+		}
+		return positions, nil
+
 	case ChannelOrderTrades:
 		var orderTrade []OrderTradeEvent
 		err := json.Unmarshal(event.Data, &orderTrade)
@@ -503,3 +521,77 @@ func (m *MarketTradeEvent) toGlobalTrade() (types.Trade, error) {
 		FeeCurrency:   "",              // not supported
 	}, nil
 }
+
+type MarkPriceEvent struct {
+	InstrumentType okexapi.InstrumentType     `json:"instType"`
+	InstrumentID   string                     `json:"instId"`
+	MarkPrice      fixedpoint.Value           `json:"markPx"`
+	Timestamp      types.MillisecondTimestamp `json:"ts"`
+}
+
+func (m *MarkPriceEvent) toGlobalMarkPrice() types.MarkPrice {
+	return types.MarkPrice{
+		Symbol:    toGlobalPerpetualSymbol(m.InstrumentID),
+		MarkPrice: m.MarkPrice,
+		Time:      m.Timestamp.Time(),
+	}
+}
+
+type PositionSide string
+
+const (
+	PositionSideLong  PositionSide = "long"
+	PositionSideShort PositionSide = "short"
+	PositionSideNet   PositionSide = "net"
+)
+
+type PositionEvent struct {
+	InstrumentType   okexapi.InstrumentType     `json:"instType"`
+	InstrumentID     string                     `json:"instId"`
+	MarginMode       string                     `json:"mgnMode"`
+	PositionID       string                     `json:"posId"`
+	PositionSide     PositionSide               `json:"posSide"`
+	Position         fixedpoint.Value           `json:"pos"`
+	AveragePrice     fixedpoint.Value           `json:"avgPx"`
+	UnrealizedPnL    fixedpoint.Value           `json:"upl"`
+	Leverage         fixedpoint.Value           `json:"lever"`
+	LiquidationPrice fixedpoint.Value           `json:"liqPx"`
+	MarkPrice        fixedpoint.Value           `json:"markPx"`
+	UpdatedTime      types.MillisecondTimestamp `json:"uTime"`
+}
+
+// futuresPositionKey returns the key of the position in the FuturesPositionMap,
+// the long and the short legs of the long/short (hedge) mode are keyed separately, e.g., BTCUSDT:long
+func (p *PositionEvent) futuresPositionKey() string {
+	symbol := toGlobalPerpetualSymbol(p.InstrumentID)
+	if p.PositionSide == PositionSideLong || p.PositionSide == PositionSideShort {
+		return symbol + ":" + string(p.PositionSide)
+	}
+
+	return symbol
+}
+
+// toGlobalFuturesPosition converts the swap position, the position of okex is counted in contracts,
+// so it's multiplied by the contract value to get the base quantity.
+func (p *PositionEvent) toGlobalFuturesPosition(contractValue fixedpoint.Value) types.FuturesPosition {
+	base := p.Position.Mul(contractValue)
+
+	// in the long/short mode, the position quantity is always positive
+	if p.PositionSide == PositionSideShort {
+		base = base.Abs().Neg()
+	}
+
+	return types.FuturesPosition{
+		Symbol:      toGlobalPerpetualSymbol(p.InstrumentID),
+		Base:        base,
+		Quote:       base.Mul(p.AveragePrice).Neg(),
+		AverageCost: p.AveragePrice,
+		Isolated:    p.MarginMode == "isolated",
+		UpdateTime:  p.UpdatedTime.Time().UnixMilli(),
+		PositionRisk: &types.PositionRisk{
+			Leverage:         p.Leverage,
+			LiquidationPrice: p.LiquidationPrice,
+			UnrealizedProfit: p.UnrealizedPnL,
+		},
+	}
+}
@@ -1,6 +1,7 @@
 package okex
 
 import (
+	"os"
 	"testing"
 	"time"
 
@@ -1139,3 +1140,171 @@ func TestOrderTradeEvent_toGlobalTrade1(t *testing.T) {
 	})
 
 }
+
+func Test_parseWebSocketEvent_markPrice(t *testing.T) {
+	in := `
+{
+  "arg": {
+    "channel": "mark-price",
+    "instId": "BTC-USDT-SWAP"
+  },
+  "data": [
+    {
+      "instType": "SWAP",
+      "instId": "BTC-USDT-SWAP",
+      "markPx": "42310.6",
+      "ts": "1630049139746"
+    }
+  ]
+}
+`
+	res, err := parseWebSocketEvent([]byte(in))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	events, ok := res.([]MarkPriceEvent)
+	if assert.True(t, ok) && assert.Len(t, events, 1) {
+		assert.Equal(t, MarkPriceEvent{
+			InstrumentType: okexapi.InstrumentTypeSwap,
+			InstrumentID:   "BTC-USDT-SWAP",
+			MarkPrice:      fixedpoint.NewFromFloat(42310.6),
+			Timestamp:      types.NewMillisecondTimestampFromInt(1630049139746),
+		}, events[0])
+	}
+
+	stream := NewStream(okexapi.NewClient(), nil)
+
+	var markPrice types.MarkPrice
+	stream.OnMarkPriceUpdate(func(m types.MarkPrice) {
+		markPrice = m
+	})
+	stream.dispatchEvent(res)
+
+	assert.Equal(t, "BTCUSDT", markPrice.Symbol)
+	assert.Equal(t, fixedpoint.NewFromFloat(42310.6), markPrice.MarkPrice)
+	assert.Equal(t, time.UnixMilli(1630049139746), markPrice.Time)
+
+	sub, err := convertSubscription(types.Subscription{Channel: types.MarkPriceChannel, Symbol: "BTCUSDT"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, WebsocketSubscription{Channel: ChannelMarkPrice, InstrumentID: "BTC-USDT-SWAP"}, sub)
+	}
+}
+
+func Test_parseWebSocketEvent_positions(t *testing.T) {
+	newStream := func() *Stream {
+		stream := NewStream(okexapi.NewClient(), nil)
+		stream.contractValues = map[string]fixedpoint.Value{
+			"BTC-USDT-SWAP": fixedpoint.MustNewFromString("0.01"),
+		}
+		return stream
+	}
+
+	t.Run("swap positions in the long/short mode", func(t *testing.T) {
+		in, err := os.ReadFile("testdata/positions_swap.json")
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		res, err := parseWebSocketEvent(in)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		events, ok := res.([]PositionEvent)
+		if assert.True(t, ok) && assert.Len(t, events, 2) {
+			assert.Equal(t, PositionEvent{
+				InstrumentType:   okexapi.InstrumentTypeSwap,
+				InstrumentID:     "BTC-USDT-SWAP",
+				MarginMode:       "isolated",
+				PositionID:       "307173036051017730",
+				PositionSide:     PositionSideLong,
+				Position:         fixedpoint.NewFromFloat(3),
+				AveragePrice:     fixedpoint.MustNewFromString("42300.5"),
+				UnrealizedPnL:    fixedpoint.MustNewFromString("0.3033"),
+				Leverage:         fixedpoint.NewFromFloat(10),
+				LiquidationPrice: fixedpoint.MustNewFromString("38500.2"),
+				MarkPrice:        fixedpoint.MustNewFromString("42310.6"),
+				UpdatedTime:      types.NewMillisecondTimestampFromInt(1619507761462),
+			}, events[0])
+		}
+
+		stream := newStream()
+
+		var positions types.FuturesPositionMap
+		stream.OnFuturesPositionUpdate(func(m types.FuturesPositionMap) {
+			positions = m
+		})
+		stream.dispatchEvent(res)
+
+		if assert.Len(t, positions, 2) {
+			long, ok := positions["BTCUSDT:long"]
+			if assert.True(t, ok) {
+				assert.Equal(t, "BTCUSDT", long.Symbol)
+				assert.Equal(t, "0.03", long.Base.String())
+				assert.Equal(t, fixedpoint.MustNewFromString("42300.5"), long.AverageCost)
+				assert.True(t, long.Isolated)
+				assert.Equal(t, int64(1619507761462), long.UpdateTime)
+				assert.Equal(t, fixedpoint.MustNewFromString("0.3033"), long.PositionRisk.UnrealizedProfit)
+				assert.Equal(t, fixedpoint.MustNewFromString("38500.2"), long.PositionRisk.LiquidationPrice)
+				assert.Equal(t, fixedpoint.NewFromFloat(10), long.PositionRisk.Leverage)
+			}
+
+			short, ok := positions["BTCUSDT:short"]
+			if assert.True(t, ok) {
+				assert.Equal(t, "BTCUSDT", short.Symbol)
+				assert.Equal(t, "-0.02", short.Base.String())
+				assert.Equal(t, "848", short.Quote.String())
+			}
+		}
+	})
+
+	t.Run("net mode", func(t *testing.T) {
+		event := PositionEvent{
+			InstrumentType: okexapi.InstrumentTypeSwap,
+			InstrumentID:   "BTC-USDT-SWAP",
+			PositionSide:   PositionSideNet,
+			Position:       fixedpoint.NewFromFloat(-5),
+			AveragePrice:   fixedpoint.NewFromFloat(40000),
+		}
+		assert.Equal(t, "BTCUSDT", event.futuresPositionKey())
+		assert.Equal(t, "-0.05", event.toGlobalFuturesPosition(fixedpoint.MustNewFromString("0.01")).Base.String())
+	})
+
+	t.Run("non-swap positions are skipped", func(t *testing.T) {
+		in, err := os.ReadFile("testdata/positions_futures.json")
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		res, err := parseWebSocketEvent(in)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		stream := newStream()
+
+		called := false
+		stream.OnFuturesPositionUpdate(func(m types.FuturesPositionMap) {
+			called = true
+		})
+		stream.dispatchEvent(res)
+		assert.False(t, called)
+	})
+
+	t.Run("unknown contract value", func(t *testing.T) {
+		stream := NewStream(okexapi.NewClient(), nil)
+
+		called := false
+		stream.OnFuturesPositionUpdate(func(m types.FuturesPositionMap) {
+			called = true
+		})
+		stream.handlePositionEvent([]PositionEvent{{
+			InstrumentType: okexapi.InstrumentTypeSwap,
+			InstrumentID:   "BTC-USDT-SWAP",
+			PositionSide:   PositionSideNet,
+			Position:       fixedpoint.NewFromFloat(1),
+		}})
+		assert.False(t, called)
+	})
+}
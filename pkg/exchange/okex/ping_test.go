@@ -0,0 +1,31 @@
+package okex
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+)
+
+func Test_Ping(t *testing.T) {
+	content, err := os.ReadFile("testdata/account_balance.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var req *http.Request
+	e := New("key", "secret", "passphrase")
+	e.client.HttpClient = httptesting.HttpClientSaver(&req, string(content))
+
+	if assert.NoError(t, e.Ping(context.Background())) {
+		assert.Equal(t, "/api/v5/account/balance", req.URL.Path)
+	}
+
+	e.client.HttpClient = httptesting.HttpClientWithError(errors.New("connection refused"))
+	assert.Error(t, e.Ping(context.Background()))
+}
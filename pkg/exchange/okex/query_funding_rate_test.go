@@ -0,0 +1,52 @@
+package okex
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+)
+
+func Test_QueryFundingRateHistory(t *testing.T) {
+	data, err := os.ReadFile("testdata/funding_rate_history.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	e := New("", "", "")
+
+	var savedReq *http.Request
+	e.client.HttpClient = httptesting.HttpClientSaver(&savedReq, string(data))
+
+	since := time.UnixMilli(1705449600000)
+	until := time.UnixMilli(1705507200000)
+	rates, err := e.QueryFundingRateHistory(context.Background(), "BTCUSDT", since, until)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	if assert.NotNil(t, savedReq) {
+		query := savedReq.URL.Query()
+		assert.Equal(t, "/api/v5/public/funding-rate-history", savedReq.URL.Path)
+		assert.Equal(t, "BTC-USDT-SWAP", query.Get("instId"))
+		assert.Equal(t, "1705507200001", query.Get("after"))
+		assert.Equal(t, "1705449599999", query.Get("before"))
+	}
+
+	if assert.Len(t, rates, 3) {
+		// sorted by the funding time in ascending order
+		assert.Equal(t, time.UnixMilli(1705449600000), rates[0].FundingTime)
+		assert.InDelta(t, -0.0000123184563946, rates[0].FundingRate.Float64(), 1e-8)
+
+		assert.Equal(t, time.UnixMilli(1705478400000), rates[1].FundingTime)
+		assert.InDelta(t, 0.0000824395478318, rates[1].FundingRate.Float64(), 1e-8)
+		assert.InDelta(t, 0.0000823011450981, rates[1].RealizedRate.Float64(), 1e-8)
+
+		assert.Equal(t, time.UnixMilli(1705507200000), rates[2].FundingTime)
+	}
+}
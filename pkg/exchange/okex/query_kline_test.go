@@ -2,9 +2,13 @@ package okex
 
 import (
 	"context"
+	"net/http"
+	"os"
 	"testing"
 	"time"
 
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
 	"github.com/c9s/bbgo/pkg/testutil"
 	"github.com/c9s/bbgo/pkg/types"
 	"github.com/stretchr/testify/assert"
@@ -83,3 +87,32 @@ func Test_QueryKlines(t *testing.T) {
 		assert.Empty(t, klineDetail)
 	}
 }
+
+func Test_QueryKLines_Chronological(t *testing.T) {
+	content, err := os.ReadFile("testdata/candles_newest_first.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var req *http.Request
+	e := New("key", "secret", "passphrase")
+	e.client.HttpClient = httptesting.HttpClientSaver(&req, string(content))
+
+	klines, err := e.QueryKLines(context.Background(), "BTCUSDT", types.Interval5m, types.KLineQueryOptions{})
+	if !assert.NoError(t, err) || !assert.Len(t, klines, 3) {
+		return
+	}
+
+	assert.Equal(t, "/api/v5/market/candles", req.URL.Path)
+	assert.Equal(t, "5m", req.URL.Query().Get("bar"))
+
+	for i := 1; i < len(klines); i++ {
+		assert.True(t, klines[i-1].StartTime.Before(klines[i].StartTime.Time()), "klines should be sorted in ascending order")
+	}
+
+	assert.Equal(t, time.UnixMilli(1700000000000), klines[0].StartTime.Time())
+	assert.Equal(t, fixedpoint.MustNewFromString("36975"), klines[0].Open)
+	assert.Equal(t, fixedpoint.MustNewFromString("36990.4"), klines[0].Close)
+	assert.Equal(t, time.UnixMilli(1700000600000), klines[2].StartTime.Time())
+	assert.Equal(t, fixedpoint.MustNewFromString("37020.5"), klines[2].Close)
+}
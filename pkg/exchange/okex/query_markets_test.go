@@ -0,0 +1,43 @@
+package okex
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+)
+
+func Test_QueryMarkets_State(t *testing.T) {
+	ctx := context.Background()
+
+	e := New("", "", "")
+	e.client.HttpClient = httptesting.HttpClientFromFile("testdata/instruments.json")
+
+	markets, err := e.QueryMarkets(ctx)
+	if assert.NoError(t, err) {
+		assert.Len(t, markets, 1)
+
+		market, ok := markets["BTCUSDT"]
+		if assert.True(t, ok) {
+			assert.Equal(t, "BTC-USDT", market.LocalSymbol)
+			assert.Equal(t, "live", market.State)
+			assert.Equal(t, "0.1", market.TickSize.String())
+		}
+
+		_, ok = markets["ETHUSDT"]
+		assert.False(t, ok, "suspended instrument should be excluded by default")
+	}
+
+	e.IncludeNonLiveMarkets(true)
+	markets, err = e.QueryMarkets(ctx)
+	if assert.NoError(t, err) {
+		assert.Len(t, markets, 2)
+
+		market, ok := markets["ETHUSDT"]
+		if assert.True(t, ok) {
+			assert.Equal(t, "suspend", market.State)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package okex
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+)
+
+func TestExchange_QueryOpenOrders(t *testing.T) {
+	var afterParams []string
+	transport := &httptesting.MockTransport{}
+	transport.GET("/api/v5/trade/orders-pending", func(req *http.Request) (*http.Response, error) {
+		query := req.URL.Query()
+		assert.Equal(t, "BTC-USDT", query.Get("instId"))
+		assert.Equal(t, "100", query.Get("limit"))
+
+		after := query.Get("after")
+		afterParams = append(afterParams, after)
+
+		switch after {
+		case "":
+			// the first page is full, the last order id is 1901
+			return httptesting.BuildResponseJson(http.StatusOK, newOpenOrdersPageResponse(2000, defaultQueryLimit)), nil
+		case "1901":
+			return httptesting.BuildResponseJson(http.StatusOK, newOpenOrdersPageResponse(1900, 30)), nil
+		}
+
+		return httptesting.BuildResponseString(http.StatusBadRequest, `{"code":"51000","msg":"unexpected cursor","data":[]}`), nil
+	})
+
+	e := New("key", "secret", "passphrase")
+	e.client.HttpClient = &http.Client{Transport: transport}
+
+	orders, err := e.QueryOpenOrders(context.Background(), "BTCUSDT")
+	if assert.NoError(t, err) && assert.Len(t, orders, defaultQueryLimit+30) {
+		assert.Equal(t, uint64(2000), orders[0].OrderID)
+		assert.Equal(t, uint64(1901), orders[defaultQueryLimit-1].OrderID)
+		assert.Equal(t, uint64(1900), orders[defaultQueryLimit].OrderID)
+		assert.Equal(t, uint64(1871), orders[len(orders)-1].OrderID)
+	}
+
+	assert.Equal(t, []string{"", "1901"}, afterParams)
+}
@@ -2,9 +2,11 @@ package okex
 
 import (
 	"context"
+	"net/http"
 	"testing"
 	"time"
 
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
 	"github.com/c9s/bbgo/pkg/testutil"
 	"github.com/c9s/bbgo/pkg/types"
 	"github.com/stretchr/testify/assert"
@@ -38,3 +40,67 @@ func Test_QueryOrderTrades(t *testing.T) {
 	}
 	t.Logf("transaction detail: %+v", transactionDetail)
 }
+
+func Test_QueryOrderTrades_ClientOrderID(t *testing.T) {
+	ctx := context.Background()
+
+	newExchange := func(orderResponse string) (*Exchange, *string) {
+		var tradeOrderID string
+		transport := &httptesting.MockTransport{}
+		transport.GET("/api/v5/trade/order", func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "BTC-USDT", req.URL.Query().Get("instId"))
+			assert.Equal(t, "1700000000000000001", req.URL.Query().Get("clOrdId"))
+			return httptesting.BuildResponseString(http.StatusOK, orderResponse), nil
+		})
+		transport.GET("/api/v5/trade/fills-history", func(req *http.Request) (*http.Response, error) {
+			tradeOrderID = req.URL.Query().Get("ordId")
+			return httptesting.BuildResponseString(http.StatusOK, `{"code":"0","msg":"","data":[
+				{"instType":"SPOT","instId":"BTC-USDT","tradeId":"123","ordId":"312269865356374016","clOrdId":"1700000000000000001",
+				"billId":"456","tag":"","fillPx":"20000","fillSz":"0.001","side":"buy","execType":"T","feeCcy":"BTC","fee":"-0.000001",
+				"ts":"1597026383085","fillTime":"1597026383085"}
+			]}`), nil
+		})
+
+		e := New("key", "secret", "passphrase")
+		e.client.HttpClient = &http.Client{Transport: transport}
+		return e, &tradeOrderID
+	}
+
+	t.Run("resolve order id by client order id", func(t *testing.T) {
+		e, tradeOrderID := newExchange(`{"code":"0","msg":"","data":[
+			{"instType":"SPOT","instId":"BTC-USDT","ordId":"312269865356374016","clOrdId":"1700000000000000001",
+			"px":"20000","sz":"0.001","ordType":"limit","side":"buy","state":"filled","accFillSz":"0.001",
+			"cTime":"1597026383085","uTime":"1597026383085"}
+		]}`)
+
+		trades, err := e.QueryOrderTrades(ctx, types.OrderQuery{
+			Symbol:        "BTCUSDT",
+			ClientOrderID: "1700000000000000001",
+		})
+		if assert.NoError(t, err) && assert.Len(t, trades, 1) {
+			assert.Equal(t, uint64(312269865356374016), trades[0].OrderID)
+			assert.Equal(t, "0.001", trades[0].Quantity.String())
+		}
+		assert.Equal(t, "312269865356374016", *tradeOrderID)
+	})
+
+	t.Run("order not found", func(t *testing.T) {
+		e, tradeOrderID := newExchange(`{"code":"51603","msg":"Order does not exist","data":[]}`)
+
+		_, err := e.QueryOrderTrades(ctx, types.OrderQuery{
+			Symbol:        "BTCUSDT",
+			ClientOrderID: "1700000000000000001",
+		})
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "failed to resolve the order id")
+		}
+		assert.Empty(t, *tradeOrderID)
+	})
+
+	t.Run("symbol is required", func(t *testing.T) {
+		e, _ := newExchange("")
+
+		_, err := e.QueryOrderTrades(ctx, types.OrderQuery{ClientOrderID: "1700000000000000001"})
+		assert.ErrorIs(t, err, ErrSymbolRequired)
+	})
+}
@@ -0,0 +1,74 @@
+package okex
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+)
+
+func Test_QueryServerTime(t *testing.T) {
+	var req *http.Request
+	e := New("key", "secret", "passphrase")
+	e.client.HttpClient = httptesting.HttpClientSaver(&req, `{"code":"0","msg":"","data":[{"ts":"1597026383085"}]}`)
+
+	serverTime, err := e.QueryServerTime(context.Background())
+	if assert.NoError(t, err) {
+		assert.Equal(t, "/api/v5/public/time", req.URL.Path)
+		assert.Equal(t, time.UnixMilli(1597026383085), serverTime)
+	}
+}
+
+func TestExchange_SyncServerTime(t *testing.T) {
+	// the local clock is 40 seconds ahead of the server clock
+	serverTime := time.Now().Add(-40 * time.Second)
+
+	var req *http.Request
+	e := New("key", "secret", "passphrase")
+	e.client.HttpClient = httptesting.HttpClientSaver(&req, fmt.Sprintf(`{"code":"0","msg":"","data":[{"ts":"%d"}]}`, serverTime.UnixMilli()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if assert.NoError(t, e.SyncServerTime(ctx)) {
+		assert.Equal(t, "/api/v5/public/time", req.URL.Path)
+		assert.InDelta(t, 40.0, e.client.TimeOffset().Seconds(), 1.0)
+	}
+}
+
+func TestExchange_SyncServerTime_FirstSyncFailed(t *testing.T) {
+	interval := serverTimeSyncInterval
+	serverTimeSyncInterval = 10 * time.Millisecond
+	defer func() { serverTimeSyncInterval = interval }()
+
+	// the local clock is 40 seconds ahead of the server clock
+	serverTime := time.Now().Add(-40 * time.Second)
+
+	var requests int32
+	transport := &httptesting.MockTransport{}
+	transport.GET("/api/v5/public/time", func(req *http.Request) (*http.Response, error) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			return httptesting.BuildResponseString(http.StatusServiceUnavailable, `{"code":"50001","msg":"Service temporarily unavailable","data":[]}`), nil
+		}
+
+		return httptesting.BuildResponseString(http.StatusOK, fmt.Sprintf(`{"code":"0","msg":"","data":[{"ts":"%d"}]}`, serverTime.UnixMilli())), nil
+	})
+
+	e := New("key", "secret", "passphrase")
+	e.client.HttpClient = &http.Client{Transport: transport}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the first sync fails, the clock skew is re-measured in the background
+	assert.Error(t, e.SyncServerTime(ctx))
+	assert.Eventually(t, func() bool {
+		return e.client.TimeOffset() > 39*time.Second
+	}, time.Second, 10*time.Millisecond)
+}
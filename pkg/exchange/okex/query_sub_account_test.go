@@ -0,0 +1,57 @@
+package okex
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+)
+
+func Test_QuerySubAccounts(t *testing.T) {
+	e := New("key", "secret", "passphrase")
+	e.client.HttpClient = httptesting.HttpClientFromFile("testdata/sub_accounts.json")
+
+	subAccounts, err := e.QuerySubAccounts(context.Background())
+	if !assert.NoError(t, err) || !assert.Len(t, subAccounts, 2) {
+		return
+	}
+
+	assert.Equal(t, "mm-desk-1", subAccounts[0].SubAccount)
+	assert.Equal(t, "market-making", subAccounts[0].Label)
+	assert.True(t, subAccounts[0].Enable)
+	assert.True(t, subAccounts[0].CanTransOut)
+	assert.Equal(t, int64(1676516312032), subAccounts[0].CreatedTime.Time().UnixMilli())
+
+	assert.Equal(t, "fund-2", subAccounts[1].SubAccount)
+	assert.False(t, subAccounts[1].Enable)
+}
+
+func Test_QuerySubAccountBalances(t *testing.T) {
+	content, err := os.ReadFile("testdata/sub_account_balances.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var req *http.Request
+	e := New("key", "secret", "passphrase")
+	e.client.HttpClient = httptesting.HttpClientSaver(&req, string(content))
+
+	balances, err := e.QuerySubAccountBalances(context.Background(), "mm-desk-1")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, "/api/v5/account/subaccount/balances", req.URL.Path)
+	assert.Equal(t, "mm-desk-1", req.URL.Query().Get("subAcct"))
+
+	if assert.Len(t, balances, 2) {
+		assert.Equal(t, "2", balances["BTC"].Available.String())
+		assert.Equal(t, "0.5", balances["BTC"].Locked.String())
+		assert.Equal(t, "12000", balances["USDT"].Available.String())
+		assert.Equal(t, "0", balances["USDT"].Locked.String())
+	}
+}
@@ -0,0 +1,42 @@
+package okex
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/exchange/okex/okexapi"
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+)
+
+func Test_toGlobalTotalEquity(t *testing.T) {
+	data, err := os.ReadFile("testdata/account_balance.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var resp struct {
+		Data []okexapi.Account `json:"data"`
+	}
+	if !assert.NoError(t, json.Unmarshal(data, &resp)) || !assert.Len(t, resp.Data, 1) {
+		return
+	}
+
+	equity, currency := toGlobalTotalEquity(&resp.Data[0])
+	assert.InDelta(t, 11172.992469, equity.Float64(), 1e-6)
+	assert.Equal(t, "USD", currency)
+}
+
+func Test_QueryTotalEquity(t *testing.T) {
+	e := New("key", "secret", "passphrase")
+	e.client.HttpClient = httptesting.HttpClientFromFile("testdata/account_balance.json")
+
+	equity, currency, err := e.QueryTotalEquity(context.Background())
+	if assert.NoError(t, err) {
+		assert.InDelta(t, 11172.992469, equity.Float64(), 1e-6)
+		assert.Equal(t, "USD", currency)
+	}
+}
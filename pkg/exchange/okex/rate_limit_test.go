@@ -0,0 +1,63 @@
+package okex
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func Test_RateLimiter_SerializeCalls(t *testing.T) {
+	content, err := os.ReadFile("testdata/candles_newest_first.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	origLimiter := marketDataLimiter
+	defer func() { marketDataLimiter = origLimiter }()
+
+	// 1 request per 50ms without burst
+	marketDataLimiter = rate.NewLimiter(rate.Every(50*time.Millisecond), 1)
+
+	var req *http.Request
+	e := New("key", "secret", "passphrase")
+	e.client.HttpClient = httptesting.HttpClientSaver(&req, string(content))
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err := e.QueryKLines(context.Background(), "BTCUSDT", types.Interval5m, types.KLineQueryOptions{})
+		assert.NoError(t, err)
+	}
+
+	// the first call takes the initial token, the following 2 calls wait for 50ms each
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+}
+
+func Test_RateLimiter_ContextCanceled(t *testing.T) {
+	origLimiter := queryOrderLimiter
+	defer func() { queryOrderLimiter = origLimiter }()
+
+	queryOrderLimiter = rate.NewLimiter(rate.Every(time.Hour), 1)
+	queryOrderLimiter.Allow()
+
+	var req *http.Request
+	e := New("key", "secret", "passphrase")
+	e.client.HttpClient = httptesting.HttpClientSaver(&req, "{}")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := e.QueryOrder(ctx, types.OrderQuery{Symbol: "BTCUSDT", OrderID: "609869603774656544"})
+	if assert.Error(t, err) {
+		assert.True(t, errors.Is(err, context.Canceled), err.Error())
+	}
+	assert.Nil(t, req, "the request should not be sent")
+}
@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"golang.org/x/time/rate"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/c9s/bbgo/pkg/exchange/okex/okexapi"
 	"github.com/c9s/bbgo/pkg/exchange/retry"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
 	"github.com/c9s/bbgo/pkg/types"
 )
 
@@ -18,6 +20,14 @@ var (
 	// pingInterval the connection will break automatically if the subscription is not established or data has not been
 	// pushed for more than 30 seconds. Therefore, we set it to 20 seconds.
 	pingInterval = 20 * time.Second
+	// staleTimeout the connection is re-connected if no message other than the pong arrives within 3 ping intervals
+	staleTimeout = 3 * pingInterval
+
+	// the re-connect delay starts from 1 second and doubles on each failure up to 1 minute,
+	// it never gives up since a live bot can not run without the market data and the user data,
+	// e.g., the re-connect keeps failing during the okx maintenance window.
+	reconnectInitialCoolDown = time.Second
+	reconnectMaxCoolDown     = time.Minute
 )
 
 type WebsocketOp struct {
@@ -35,16 +45,26 @@ type WebsocketLogin struct {
 //go:generate callbackgen -type Stream -interface
 type Stream struct {
 	types.StandardStream
+	types.FuturesSettings
 
 	client          *okexapi.RestClient
 	balanceProvider types.ExchangeAccountService
 
+	// contractValues is the contract value of the swap instruments keyed by the instrument id,
+	// it's loaded before subscribing the positions channel in the futures mode.
+	contractValuesMutex sync.Mutex
+	contractValues      map[string]fixedpoint.Value
+
 	// public callbacks
 	kLineEventCallbacks       []func(candle KLineEvent)
 	bookEventCallbacks        []func(book BookEvent)
 	accountEventCallbacks     []func(account okexapi.Account)
 	orderTradesEventCallbacks []func(orderTrades []OrderTradeEvent)
 	marketTradeEventCallbacks []func(tradeDetail []MarketTradeEvent)
+	markPriceEventCallbacks   []func(markPrices []MarkPriceEvent)
+	positionEventCallbacks    []func(positions []PositionEvent)
+
+	markPriceUpdateCallbacks []func(markPrice types.MarkPrice)
 }
 
 func NewStream(client *okexapi.RestClient, balanceProvider types.ExchangeAccountService) *Stream {
@@ -58,12 +78,16 @@ func NewStream(client *okexapi.RestClient, balanceProvider types.ExchangeAccount
 	stream.SetDispatcher(stream.dispatchEvent)
 	stream.SetEndpointCreator(stream.createEndpoint)
 	stream.SetPingInterval(pingInterval)
+	stream.SetStaleTimeout(staleTimeout)
+	stream.SetReconnectBackoff(reconnectInitialCoolDown, reconnectMaxCoolDown, 0)
 
 	stream.OnKLineEvent(stream.handleKLineEvent)
 	stream.OnBookEvent(stream.handleBookEvent)
 	stream.OnAccountEvent(stream.handleAccountEvent)
 	stream.OnMarketTradeEvent(stream.handleMarketTradeEvent)
 	stream.OnOrderTradesEvent(stream.handleOrderDetailsEvent)
+	stream.OnMarkPriceEvent(stream.handleMarkPriceEvent)
+	stream.OnPositionEvent(stream.handlePositionEvent)
 	stream.OnConnect(stream.handleConnect)
 	stream.OnAuth(stream.subscribePrivateChannels(stream.emitBalanceSnapshot))
 	return stream
@@ -83,7 +107,7 @@ func (s *Stream) syncSubscriptions(opType WsEventType) error {
 			return err
 		}
 
-		topics = append(topics, topic)
+		topics = appendUniqueSubscription(topics, topic)
 	}
 
 	logger.Infof("%s channels: %+v", opType, topics)
@@ -98,6 +122,18 @@ func (s *Stream) syncSubscriptions(opType WsEventType) error {
 	return nil
 }
 
+// appendUniqueSubscription appends the subscription if it's not in the list,
+// different global channels can be mapped to the same okex channel, e.g., market trade and agg trade.
+func appendUniqueSubscription(subs []WebsocketSubscription, sub WebsocketSubscription) []WebsocketSubscription {
+	for _, existing := range subs {
+		if existing == sub {
+			return subs
+		}
+	}
+
+	return append(subs, sub)
+}
+
 func (s *Stream) Unsubscribe() {
 	// errors are handled in the syncSubscriptions, so they are skipped here.
 	_ = s.syncSubscriptions(WsEventTypeUnsubscribe)
@@ -107,6 +143,51 @@ func (s *Stream) Unsubscribe() {
 	})
 }
 
+// UnsubscribeChannel sends the unsubscribe request of the channel and removes it from the subscriptions without re-connecting.
+// The okex channel is kept if it's still used by the other subscriptions, e.g., market trade and agg trade.
+func (s *Stream) UnsubscribeChannel(channel types.Channel, symbol string, options types.SubscribeOptions) error {
+	sub := types.Subscription{
+		Channel: channel,
+		Symbol:  symbol,
+		Options: options,
+	}
+
+	if !s.RemoveSubscription(sub) {
+		return nil
+	}
+
+	topic, err := convertSubscription(sub)
+	if err != nil {
+		return err
+	}
+
+	for _, subscription := range s.GetSubscriptions() {
+		if existing, err := convertSubscription(subscription); err == nil && existing == topic {
+			return nil
+		}
+	}
+
+	s.ConnLock.Lock()
+	conn := s.Conn
+	s.ConnLock.Unlock()
+
+	// not connected yet, the remaining subscriptions are sent on connect
+	if conn == nil {
+		return nil
+	}
+
+	log.Infof("%s channels: %+v", WsEventTypeUnsubscribe, topic)
+	if err := conn.WriteJSON(WebsocketOp{
+		Op:   WsEventTypeUnsubscribe,
+		Args: []WebsocketSubscription{topic},
+	}); err != nil {
+		log.WithError(err).Error("failed to send request")
+		return err
+	}
+
+	return nil
+}
+
 func (s *Stream) handleConnect() {
 	if s.PublicOnly {
 		var subs []WebsocketSubscription
@@ -117,7 +198,7 @@ func (s *Stream) handleConnect() {
 				continue
 			}
 
-			subs = append(subs, sub)
+			subs = appendUniqueSubscription(subs, sub)
 		}
 		if len(subs) == 0 {
 			return
@@ -133,23 +214,9 @@ func (s *Stream) handleConnect() {
 			log.WithError(err).Error("subscribe error")
 		}
 	} else {
-		// login as private channel
-		// sign example:
-		// sign=CryptoJS.enc.Base64.Stringify(CryptoJS.HmacSHA256(timestamp +'GET'+'/users/self/verify', secretKey))
-		msTimestamp := strconv.FormatFloat(float64(time.Now().UnixNano())/float64(time.Second), 'f', -1, 64)
-		payload := msTimestamp + "GET" + "/users/self/verify"
-		sign := okexapi.Sign(payload, s.client.Secret)
-		op := WebsocketOp{
-			Op: "login",
-			Args: []WebsocketLogin{
-				{
-					Key:        s.client.Key,
-					Passphrase: s.client.Passphrase,
-					Timestamp:  msTimestamp,
-					Sign:       sign,
-				},
-			},
-		}
+		// login as private channel, the login is sent on every (re)connect with a fresh timestamp
+		// since okex rejects the login request whose timestamp is older than 30 seconds
+		op := s.newLoginOp(time.Now())
 
 		log.Infof("sending okex login request")
 		err := s.Conn.WriteJSON(op)
@@ -159,6 +226,27 @@ func (s *Stream) handleConnect() {
 	}
 }
 
+// newLoginOp builds the login request, the timestamp is offset by the measured clock skew of the rest client.
+// sign example:
+// sign=CryptoJS.enc.Base64.Stringify(CryptoJS.HmacSHA256(timestamp +'GET'+'/users/self/verify', secretKey))
+func (s *Stream) newLoginOp(now time.Time) WebsocketOp {
+	now = now.Add(-s.client.TimeOffset())
+	msTimestamp := strconv.FormatFloat(float64(now.UnixNano())/float64(time.Second), 'f', -1, 64)
+	payload := msTimestamp + "GET" + "/users/self/verify"
+	sign := okexapi.Sign(payload, s.client.Secret)
+	return WebsocketOp{
+		Op: "login",
+		Args: []WebsocketLogin{
+			{
+				Key:        s.client.Key,
+				Passphrase: s.client.Passphrase,
+				Timestamp:  msTimestamp,
+				Sign:       sign,
+			},
+		},
+	}
+}
+
 func (s *Stream) subscribePrivateChannels(next func()) func() {
 	return func() {
 		var subs = []WebsocketSubscription{
@@ -166,6 +254,14 @@ func (s *Stream) subscribePrivateChannels(next func()) func() {
 			{Channel: "orders", InstrumentType: string(okexapi.InstrumentTypeSpot)},
 		}
 
+		if s.IsFutures {
+			if err := s.loadContractValues(context.Background()); err != nil {
+				log.WithError(err).Error("failed to load the contract values of the swap instruments")
+			}
+
+			subs = append(subs, WebsocketSubscription{Channel: ChannelPositions, InstrumentType: string(okexapi.InstrumentTypeSwap)})
+		}
+
 		log.Infof("subscribing private channels: %+v", subs)
 		err := s.Conn.WriteJSON(WebsocketOp{
 			Op:   "subscribe",
@@ -247,7 +343,81 @@ func (s *Stream) handleMarketTradeEvent(data []MarketTradeEvent) {
 		}
 
 		s.EmitMarketTrade(trade)
+		// the trade is aggregated by the taker order and the price, Count is the number of the aggregated trades
+		s.EmitAggTrade(trade)
+	}
+}
+
+func (s *Stream) handleMarkPriceEvent(data []MarkPriceEvent) {
+	for _, event := range data {
+		s.EmitMarkPriceUpdate(event.toGlobalMarkPrice())
+	}
+}
+
+// loadContractValues queries the contract values of the swap instruments,
+// the positions channel reports the position size in contracts.
+func (s *Stream) loadContractValues(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	var instruments []okexapi.InstrumentInfo
+	err := retry.GeneralBackoff(ctx, func() (err error) {
+		instruments, err = s.client.NewGetInstrumentsInfoRequest().InstType(okexapi.InstrumentTypeSwap).Do(ctx)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	contractValues := make(map[string]fixedpoint.Value, len(instruments))
+	for _, instrument := range instruments {
+		contractValue, err := fixedpoint.NewFromString(instrument.ContractValue)
+		if err != nil {
+			log.WithError(err).Warnf("invalid contract value %q of %s", instrument.ContractValue, instrument.InstrumentID)
+			continue
+		}
+
+		contractValues[instrument.InstrumentID] = contractValue
 	}
+
+	s.contractValuesMutex.Lock()
+	s.contractValues = contractValues
+	s.contractValuesMutex.Unlock()
+	return nil
+}
+
+func (s *Stream) getContractValue(instrumentID string) (fixedpoint.Value, bool) {
+	s.contractValuesMutex.Lock()
+	defer s.contractValuesMutex.Unlock()
+
+	contractValue, ok := s.contractValues[instrumentID]
+	return contractValue, ok
+}
+
+// handlePositionEvent emits the futures position update of the swap instruments,
+// the long and the short legs of the long/short mode are keyed separately, see PositionEvent.futuresPositionKey
+func (s *Stream) handlePositionEvent(data []PositionEvent) {
+	positions := make(types.FuturesPositionMap, len(data))
+	for _, event := range data {
+		// the futures, margin and option positions are not supported
+		if event.InstrumentType != okexapi.InstrumentTypeSwap {
+			continue
+		}
+
+		contractValue, ok := s.getContractValue(event.InstrumentID)
+		if !ok {
+			log.Warnf("contract value of %s is not found, skip the position update", event.InstrumentID)
+			continue
+		}
+
+		positions[event.futuresPositionKey()] = event.toGlobalFuturesPosition(contractValue)
+	}
+
+	if len(positions) == 0 {
+		return
+	}
+
+	s.EmitFuturesPositionUpdate(positions)
 }
 
 func (s *Stream) handleKLineEvent(k KLineEvent) {
@@ -300,5 +470,11 @@ func (s *Stream) dispatchEvent(e interface{}) {
 	case []MarketTradeEvent:
 		s.EmitMarketTradeEvent(et)
 
+	case []MarkPriceEvent:
+		s.EmitMarkPriceEvent(et)
+
+	case []PositionEvent:
+		s.EmitPositionEvent(et)
+
 	}
 }
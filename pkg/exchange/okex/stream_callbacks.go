@@ -4,6 +4,7 @@ package okex
 
 import (
 	"github.com/c9s/bbgo/pkg/exchange/okex/okexapi"
+	"github.com/c9s/bbgo/pkg/types"
 )
 
 func (s *Stream) OnKLineEvent(cb func(candle KLineEvent)) {
@@ -56,6 +57,36 @@ func (s *Stream) EmitMarketTradeEvent(tradeDetail []MarketTradeEvent) {
 	}
 }
 
+func (s *Stream) OnMarkPriceEvent(cb func(markPrices []MarkPriceEvent)) {
+	s.markPriceEventCallbacks = append(s.markPriceEventCallbacks, cb)
+}
+
+func (s *Stream) EmitMarkPriceEvent(markPrices []MarkPriceEvent) {
+	for _, cb := range s.markPriceEventCallbacks {
+		cb(markPrices)
+	}
+}
+
+func (s *Stream) OnPositionEvent(cb func(positions []PositionEvent)) {
+	s.positionEventCallbacks = append(s.positionEventCallbacks, cb)
+}
+
+func (s *Stream) EmitPositionEvent(positions []PositionEvent) {
+	for _, cb := range s.positionEventCallbacks {
+		cb(positions)
+	}
+}
+
+func (s *Stream) OnMarkPriceUpdate(cb func(markPrice types.MarkPrice)) {
+	s.markPriceUpdateCallbacks = append(s.markPriceUpdateCallbacks, cb)
+}
+
+func (s *Stream) EmitMarkPriceUpdate(markPrice types.MarkPrice) {
+	for _, cb := range s.markPriceUpdateCallbacks {
+		cb(markPrice)
+	}
+}
+
 type StreamEventHub interface {
 	OnKLineEvent(cb func(candle KLineEvent))
 
@@ -66,4 +97,10 @@ type StreamEventHub interface {
 	OnOrderTradesEvent(cb func(orderTrades []OrderTradeEvent))
 
 	OnMarketTradeEvent(cb func(tradeDetail []MarketTradeEvent))
+
+	OnMarkPriceEvent(cb func(markPrices []MarkPriceEvent))
+
+	OnPositionEvent(cb func(positions []PositionEvent))
+
+	OnMarkPriceUpdate(cb func(markPrice types.MarkPrice))
 }
@@ -2,13 +2,23 @@ package okex
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/c9s/bbgo/pkg/exchange/okex/okexapi"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
 	"github.com/c9s/bbgo/pkg/testutil"
 	"github.com/c9s/bbgo/pkg/types"
 )
@@ -158,3 +168,340 @@ func TestStream(t *testing.T) {
 		<-c
 	})
 }
+
+func TestStream_privateOrderPush(t *testing.T) {
+	e := New("key", "secret", "passphrase")
+	s := NewStream(e.client, e)
+
+	var orders []types.Order
+	var trades []types.Trade
+	s.OnOrderUpdate(func(order types.Order) {
+		orders = append(orders, order)
+	})
+	s.OnTradeUpdate(func(trade types.Trade) {
+		trades = append(trades, trade)
+	})
+
+	// recorded partially filled push of a market buy order
+	push := `{"arg":{"channel":"orders","instType":"SPOT","uid":"530315546680502420"},"data":[{"accFillSz":"1.84","algoClOrdId":"","algoId":"","amendResult":"","amendSource":"","attachAlgoClOrdId":"","attachAlgoOrds":[],"avgPx":"54","cTime":"1705384184502","cancelSource":"","category":"normal","ccy":"","clOrdId":"","code":"0","execType":"T","fee":"-0.001844337","feeCcy":"OKB","fillFee":"-0.001844337","fillFeeCcy":"OKB","fillFwdPx":"","fillMarkPx":"","fillMarkVol":"","fillNotionalUsd":"99.9","fillPnl":"0","fillPx":"54","fillPxUsd":"","fillPxVol":"","fillSz":"1.844337","fillTime":"1705384184503","instId":"OKB-USDT","instType":"SPOT","lastPx":"54","lever":"0","msg":"","notionalUsd":"99.929","ordId":"667364871905857536","ordType":"market","pnl":"0","posSide":"","px":"","pxType":"","pxUsd":"","pxVol":"","quickMgnType":"","rebate":"0","rebateCcy":"USDT","reduceOnly":"false","reqId":"","side":"buy","slOrdPx":"","slTriggerPx":"","slTriggerPxType":"","source":"","state":"partially_filled","stpId":"","stpMode":"","sz":"100","tag":"","tdMode":"cash","tgtCcy":"quote_ccy","tpOrdPx":"","tpTriggerPx":"","tpTriggerPxType":"","tradeId":"590957341","uTime":"1705384184503"}]}`
+
+	evt, err := parseWebSocketEvent([]byte(push))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	s.dispatchEvent(evt)
+
+	if assert.Len(t, orders, 1) {
+		order := orders[0]
+		assert.Equal(t, "OKBUSDT", order.Symbol)
+		assert.Equal(t, uint64(667364871905857536), order.OrderID)
+		assert.Equal(t, types.SideTypeBuy, order.Side)
+		assert.Equal(t, types.OrderTypeMarket, order.Type)
+		assert.Equal(t, types.OrderStatusPartiallyFilled, order.Status)
+		assert.Equal(t, fixedpoint.MustNewFromString("1.84"), order.ExecutedQuantity)
+		assert.Equal(t, types.ExchangeOKEx, order.Exchange)
+	}
+
+	if assert.Len(t, trades, 1) {
+		trade := trades[0]
+		assert.Equal(t, uint64(590957341), trade.ID)
+		assert.Equal(t, uint64(667364871905857536), trade.OrderID)
+		assert.Equal(t, fixedpoint.MustNewFromString("54"), trade.Price)
+		assert.Equal(t, fixedpoint.MustNewFromString("1.844337"), trade.Quantity)
+	}
+}
+
+func TestStream_newLoginOp(t *testing.T) {
+	client := okexapi.NewClient()
+	client.Auth("key", "secret", "passphrase")
+
+	now := time.Unix(1705384184, 0)
+	s := NewStream(client, nil)
+
+	op := s.newLoginOp(now)
+	args, ok := op.Args.([]WebsocketLogin)
+	if assert.True(t, ok) && assert.Len(t, args, 1) {
+		assert.Equal(t, WsEventType("login"), op.Op)
+		assert.Equal(t, "1705384184", args[0].Timestamp)
+		assert.Equal(t, okexapi.Sign("1705384184GET/users/self/verify", "secret"), args[0].Sign)
+	}
+
+	// the local clock is 10 seconds ahead of the server, the login timestamp is offset by the skew
+	client.HttpClient = httptesting.HttpClientWithContent(fmt.Sprintf(`{"code":"0","msg":"","data":[{"ts":"%d"}]}`, time.Now().Add(-10*time.Second).UnixMilli()))
+	if _, err := client.SetTimeOffsetFromServer(context.Background(), 0); !assert.NoError(t, err) {
+		return
+	}
+
+	op = s.newLoginOp(now)
+	args = op.Args.([]WebsocketLogin)
+	ts, err := strconv.ParseFloat(args[0].Timestamp, 64)
+	if assert.NoError(t, err) {
+		assert.InDelta(t, 1705384174, ts, 1.0)
+	}
+}
+
+func TestStream_reconnectResubscribe(t *testing.T) {
+	var mu sync.Mutex
+	var frames []string
+	var connections int
+
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		mu.Lock()
+		connections++
+		n := connections
+		mu.Unlock()
+
+		for {
+			_, message, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			mu.Lock()
+			frames = append(frames, strings.TrimSpace(string(message)))
+			mu.Unlock()
+
+			// drop the first connection after the subscription
+			if n == 1 {
+				_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, "going away"))
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	e := New("", "", "")
+	s := NewStream(e.client, e)
+	s.SetPublicOnly()
+	s.SetReconnectBackoff(10*time.Millisecond, 50*time.Millisecond, 3)
+	s.SetEndpointCreator(func(ctx context.Context) (string, error) {
+		return "ws" + strings.TrimPrefix(server.URL, "http"), nil
+	})
+
+	s.Subscribe(types.BookChannel, "BTCUSDT", types.SubscribeOptions{Depth: types.DepthLevel400})
+	s.Subscribe(types.KLineChannel, "BTCUSDT", types.SubscribeOptions{Interval: types.Interval1m})
+	s.Subscribe(types.MarketTradeChannel, "BTCUSDT", types.SubscribeOptions{})
+
+	reconnectC := make(chan struct{}, 1)
+	s.OnReconnect(func() {
+		reconnectC <- struct{}{}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if !assert.NoError(t, s.Connect(ctx)) {
+		return
+	}
+	defer s.Close()
+
+	select {
+	case <-reconnectC:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the stream is not re-connected")
+	}
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(frames) == 2
+	}, 2*time.Second, 10*time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if assert.Len(t, frames, 2) {
+		assert.Equal(t, `{"op":"subscribe","args":[{"channel":"books","instId":"BTC-USDT"},{"channel":"candle1m","instId":"BTC-USDT"},{"channel":"trades","instId":"BTC-USDT"}]}`, frames[0])
+		assert.Equal(t, frames[0], frames[1], "the subscriptions should be re-sent in the same order")
+	}
+}
+
+func TestStream_aggTrade(t *testing.T) {
+	e := New("", "", "")
+	s := NewStream(e.client, e)
+
+	var aggTrades []types.Trade
+	s.OnAggTrade(func(trade types.Trade) {
+		aggTrades = append(aggTrades, trade)
+	})
+
+	// each entry aggregates the trades of the same taker order and price, count is the number of the aggregated trades
+	push := `{"arg":{"channel":"trades","instId":"BTC-USDT"},"data":[
+		{"instId":"BTC-USDT","tradeId":"130639474","px":"42219.9","sz":"0.12060306","side":"buy","ts":"1630048897897","count":"3"},
+		{"instId":"BTC-USDT","tradeId":"130639477","px":"42220","sz":"0.5","side":"buy","ts":"1630048897897","count":"2"},
+		{"instId":"BTC-USDT","tradeId":"130639479","px":"42218.1","sz":"0.01","side":"sell","ts":"1630048897950","count":"1"}
+	]}`
+
+	evt, err := parseWebSocketEvent([]byte(push))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	s.dispatchEvent(evt)
+
+	if assert.Len(t, aggTrades, 3) {
+		assert.Equal(t, "BTCUSDT", aggTrades[0].Symbol)
+		assert.Equal(t, uint64(130639474), aggTrades[0].ID)
+		assert.Equal(t, types.SideTypeBuy, aggTrades[0].Side)
+		assert.Equal(t, fixedpoint.MustNewFromString("42219.9"), aggTrades[0].Price)
+		assert.Equal(t, fixedpoint.MustNewFromString("0.12060306"), aggTrades[0].Quantity)
+
+		assert.Equal(t, fixedpoint.MustNewFromString("0.5"), aggTrades[1].Quantity)
+
+		assert.Equal(t, types.SideTypeSell, aggTrades[2].Side)
+		assert.Equal(t, fixedpoint.MustNewFromString("0.01"), aggTrades[2].Quantity)
+	}
+}
+
+func Test_appendUniqueSubscription(t *testing.T) {
+	var subs []WebsocketSubscription
+	for _, channel := range []types.Channel{types.MarketTradeChannel, types.AggTradeChannel} {
+		sub, err := convertSubscription(types.Subscription{Channel: channel, Symbol: "BTCUSDT"})
+		if assert.NoError(t, err) {
+			subs = appendUniqueSubscription(subs, sub)
+		}
+	}
+
+	assert.Equal(t, []WebsocketSubscription{{Channel: ChannelMarketTrades, InstrumentID: "BTC-USDT"}}, subs)
+}
+
+func TestStream_subscribePrivateChannels(t *testing.T) {
+	frames := make(chan string, 2)
+
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for {
+			_, message, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			frames <- strings.TrimSpace(string(message))
+		}
+	}))
+	defer server.Close()
+
+	transport := &httptesting.MockTransport{}
+	transport.GET("/api/v5/public/instruments", func(req *http.Request) (*http.Response, error) {
+		assert.Equal(t, "SWAP", req.URL.Query().Get("instType"))
+		return httptesting.BuildResponseString(http.StatusOK, `{"code":"0","msg":"","data":[{"instType":"SWAP","instId":"BTC-USDT-SWAP","ctVal":"0.01","ctValCcy":"BTC","lotSz":"1","minSz":"1","tickSz":"0.1","state":"live"}]}`), nil
+	})
+
+	newStream := func(t *testing.T) *Stream {
+		e := New("key", "secret", "passphrase")
+		e.client.HttpClient = &http.Client{Transport: transport}
+
+		conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		t.Cleanup(func() { _ = conn.Close() })
+
+		s := NewStream(e.client, e)
+		s.Conn = conn
+		return s
+	}
+
+	t.Run("spot", func(t *testing.T) {
+		s := newStream(t)
+		s.subscribePrivateChannels(func() {})()
+
+		assert.Equal(t, `{"op":"subscribe","args":[{"channel":"account"},{"channel":"orders","instType":"SPOT"}]}`, <-frames)
+	})
+
+	t.Run("futures", func(t *testing.T) {
+		s := newStream(t)
+		s.UseFutures()
+		s.subscribePrivateChannels(func() {})()
+
+		assert.Equal(t, `{"op":"subscribe","args":[{"channel":"account"},{"channel":"orders","instType":"SPOT"},{"channel":"positions","instType":"SWAP"}]}`, <-frames)
+
+		contractValue, ok := s.getContractValue("BTC-USDT-SWAP")
+		if assert.True(t, ok) {
+			assert.Equal(t, "0.01", contractValue.String())
+		}
+	})
+}
+
+func TestStream_UnsubscribeChannel(t *testing.T) {
+	framesC := make(chan string, 10)
+	var connections int32
+
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		atomic.AddInt32(&connections, 1)
+		for {
+			_, message, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			framesC <- strings.TrimSpace(string(message))
+		}
+	}))
+	defer server.Close()
+
+	e := New("", "", "")
+	s := NewStream(e.client, e)
+	s.SetPublicOnly()
+	s.SetEndpointCreator(func(ctx context.Context) (string, error) {
+		return "ws" + strings.TrimPrefix(server.URL, "http"), nil
+	})
+
+	s.Subscribe(types.KLineChannel, "BTCUSDT", types.SubscribeOptions{Interval: types.Interval1m})
+	s.Subscribe(types.MarketTradeChannel, "BTCUSDT", types.SubscribeOptions{})
+	s.Subscribe(types.AggTradeChannel, "BTCUSDT", types.SubscribeOptions{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if !assert.NoError(t, s.Connect(ctx)) {
+		return
+	}
+	defer s.Close()
+
+	nextFrame := func() string {
+		select {
+		case frame := <-framesC:
+			return frame
+		case <-time.After(2 * time.Second):
+			t.Fatal("no frame is received")
+			return ""
+		}
+	}
+
+	assert.Equal(t, `{"op":"subscribe","args":[{"channel":"candle1m","instId":"BTC-USDT"},{"channel":"trades","instId":"BTC-USDT"}]}`, nextFrame())
+
+	// the trades channel is still used by the agg trade subscription
+	assert.NoError(t, s.UnsubscribeChannel(types.MarketTradeChannel, "BTCUSDT", types.SubscribeOptions{}))
+	assert.NoError(t, s.UnsubscribeChannel(types.KLineChannel, "BTCUSDT", types.SubscribeOptions{Interval: types.Interval1m}))
+	assert.Equal(t, `{"op":"unsubscribe","args":[{"channel":"candle1m","instId":"BTC-USDT"}]}`, nextFrame())
+
+	// unsubscribing a subscription that does not exist is a no-op
+	assert.NoError(t, s.UnsubscribeChannel(types.KLineChannel, "BTCUSDT", types.SubscribeOptions{Interval: types.Interval1m}))
+
+	assert.Equal(t, []types.Subscription{
+		{Channel: types.AggTradeChannel, Symbol: "BTCUSDT", Options: types.SubscribeOptions{}},
+	}, s.GetSubscriptions())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&connections), "the stream should not be re-connected")
+	assert.Len(t, framesC, 0)
+}
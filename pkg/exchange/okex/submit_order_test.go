@@ -0,0 +1,91 @@
+package okex
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func Test_validateSubmitOrder(t *testing.T) {
+	market := types.Market{
+		Symbol:          "BTCUSDT",
+		LocalSymbol:     "BTC-USDT",
+		PricePrecision:  1,
+		VolumePrecision: 8,
+		QuoteCurrency:   "USDT",
+		BaseCurrency:    "BTC",
+		MinNotional:     fixedpoint.NewFromFloat(10.0),
+		MinQuantity:     fixedpoint.NewFromFloat(0.0001),
+		StepSize:        fixedpoint.NewFromFloat(0.0001),
+		TickSize:        fixedpoint.NewFromFloat(0.1),
+	}
+
+	newOrder := func(price, quantity string) types.SubmitOrder {
+		return types.SubmitOrder{
+			Symbol:   "BTCUSDT",
+			Side:     types.SideTypeBuy,
+			Type:     types.OrderTypeLimit,
+			Market:   market,
+			Price:    fixedpoint.MustNewFromString(price),
+			Quantity: fixedpoint.MustNewFromString(quantity),
+		}
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		assert.NoError(t, validateSubmitOrder(newOrder("20000.0", "0.0012")))
+	})
+
+	t.Run("min quantity", func(t *testing.T) {
+		err := validateSubmitOrder(newOrder("200000.0", "0.00005"))
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "min quantity")
+		}
+	})
+
+	t.Run("zero quantity", func(t *testing.T) {
+		order := newOrder("20000.0", "0")
+		order.Market.MinQuantity = fixedpoint.Zero
+		err := validateSubmitOrder(order)
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "should be positive")
+		}
+	})
+
+	t.Run("step size", func(t *testing.T) {
+		err := validateSubmitOrder(newOrder("20000.0", "0.00125"))
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "step size")
+		}
+	})
+
+	t.Run("min notional", func(t *testing.T) {
+		err := validateSubmitOrder(newOrder("20000.0", "0.0004"))
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "min notional")
+		}
+	})
+
+	t.Run("market order without price", func(t *testing.T) {
+		order := newOrder("0", "0.0004")
+		order.Type = types.OrderTypeMarket
+		assert.NoError(t, validateSubmitOrder(order))
+	})
+
+	t.Run("without market", func(t *testing.T) {
+		order := newOrder("20000.0", "0.00005")
+		order.Market = types.Market{}
+		assert.NoError(t, validateSubmitOrder(order))
+	})
+
+	t.Run("rejected before sending the request", func(t *testing.T) {
+		e := New("", "", "")
+		_, err := e.SubmitOrder(context.Background(), newOrder("20000.0", "0.0004"))
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "min notional")
+		}
+	})
+}
@@ -0,0 +1,91 @@
+package okex
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/testing/httptesting"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func Test_Withdraw(t *testing.T) {
+	ctx := context.Background()
+
+	newExchange := func(available string, body *map[string]interface{}) *Exchange {
+		transport := &httptesting.MockTransport{}
+		transport.GET("/api/v5/asset/currencies", func(req *http.Request) (*http.Response, error) {
+			return httptesting.BuildResponseString(http.StatusOK, `{"code":"0","msg":"","data":[
+				{"ccy":"USDT","name":"Tether","chain":"USDT-TRC20","canDep":true,"canWd":true,"canInternal":true,"minFee":"0.8","maxFee":"1.6","minWd":"0.1"},
+				{"ccy":"USDT","name":"Tether","chain":"USDT-ERC20","canDep":true,"canWd":true,"canInternal":true,"minFee":"3.2","maxFee":"6.4","minWd":"2"},
+				{"ccy":"BTC","name":"Bitcoin","chain":"BTC-Bitcoin","canDep":true,"canWd":true,"canInternal":true,"minFee":"0.0002","maxFee":"0.0004","minWd":"0.001"}
+			]}`), nil
+		})
+		transport.GET("/api/v5/asset/balances", func(req *http.Request) (*http.Response, error) {
+			return httptesting.BuildResponseString(http.StatusOK, `{"code":"0","msg":"","data":[
+				{"ccy":"USDT","bal":"`+available+`","frozenBal":"0","availBal":"`+available+`"}
+			]}`), nil
+		})
+		transport.POST("/api/v5/asset/withdrawal", func(req *http.Request) (*http.Response, error) {
+			if err := json.NewDecoder(req.Body).Decode(body); err != nil {
+				return nil, err
+			}
+
+			return httptesting.BuildResponseString(http.StatusOK, `{"code":"0","msg":"","data":[
+				{"amt":"50","wdId":"67485","ccy":"USDT","clientId":"","chain":"USDT-TRC20"}
+			]}`), nil
+		})
+
+		e := New("key", "secret", "passphrase")
+		e.client.HttpClient = &http.Client{Transport: transport}
+		return e
+	}
+
+	t.Run("usdt on trc20", func(t *testing.T) {
+		var body map[string]interface{}
+		e := newExchange("100", &body)
+
+		err := e.Withdraw(ctx, "USDT", fixedpoint.NewFromInt(50), "TXYZopYRdj2D9XRtbG411XZZ3kM5VkAeBf", &types.WithdrawalOptions{
+			Network: "TRC20",
+		})
+		if assert.NoError(t, err) {
+			assert.Equal(t, map[string]interface{}{
+				"ccy":    "USDT",
+				"amt":    "50",
+				"dest":   "4",
+				"toAddr": "TXYZopYRdj2D9XRtbG411XZZ3kM5VkAeBf",
+				"chain":  "USDT-TRC20",
+				"fee":    "0.8",
+			}, body)
+		}
+	})
+
+	t.Run("multi-chain asset without network", func(t *testing.T) {
+		var body map[string]interface{}
+		e := newExchange("100", &body)
+
+		err := e.Withdraw(ctx, "USDT", fixedpoint.NewFromInt(50), "TXYZopYRdj2D9XRtbG411XZZ3kM5VkAeBf", nil)
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "multi-chain")
+		}
+		assert.Nil(t, body)
+	})
+
+	t.Run("insufficient balance", func(t *testing.T) {
+		var body map[string]interface{}
+		e := newExchange("50", &body)
+
+		// the fee is charged in addition to the amount
+		err := e.Withdraw(ctx, "USDT", fixedpoint.NewFromInt(50), "TXYZopYRdj2D9XRtbG411XZZ3kM5VkAeBf", &types.WithdrawalOptions{
+			Network: "USDT-TRC20",
+		})
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "insufficient")
+		}
+		assert.Nil(t, body)
+	})
+}
@@ -34,25 +34,55 @@ const (
 	HalfUp
 )
 
+// RoundUp rounds away from zero, RoundDown rounds toward zero (truncation),
+// and RoundHalfUp rounds to the nearest with the half rounded away from zero.
+const (
+	RoundUp     = Up
+	RoundDown   = Down
+	RoundHalfUp = HalfUp
+)
+
 // Trunc returns the integer portion (truncating any fractional part)
 func (v Value) Trunc() Value {
 	return NewFromFloat(math.Floor(v.Float64()))
 }
 
+// Round rounds the value to r decimal places with the given rounding mode,
+// the negative values are rounded symmetrically, e.g. -1.25 rounds down to -1.2 and up to -1.3.
 func (v Value) Round(r int, mode RoundingMode) Value {
-	pow := math.Pow10(r)
-	f := v.Float64() * pow
+	if v == PosInf || v == NegInf || r >= DefaultPrecision {
+		return v
+	}
+
+	if DefaultPrecision-r > 18 {
+		return Zero
+	}
+
+	unit := int64(math.Round(math.Pow10(DefaultPrecision - r)))
+
+	abs := int64(v)
+	if abs < 0 {
+		abs = -abs
+	}
+
+	rem := abs % unit
+	rounded := abs - rem
 	switch mode {
 	case Up:
-		f = math.Ceil(f) / pow
+		if rem > 0 {
+			rounded += unit
+		}
 	case HalfUp:
-		f = math.Floor(f+0.5) / pow
-	case Down:
-		f = math.Floor(f) / pow
+		if rem >= unit/2 {
+			rounded += unit
+		}
+	}
+
+	if v < 0 {
+		return Value(-rounded)
 	}
 
-	s := strconv.FormatFloat(f, 'f', r, 64)
-	return MustNewFromString(s)
+	return Value(rounded)
 }
 
 func (v Value) Value() (driver.Value, error) {
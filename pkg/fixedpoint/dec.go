@@ -950,6 +950,14 @@ const (
 	HalfUp
 )
 
+// RoundUp rounds away from zero, RoundDown rounds toward zero (truncation),
+// and RoundHalfUp rounds to the nearest with the half rounded away from zero.
+const (
+	RoundUp     = Up
+	RoundDown   = Down
+	RoundHalfUp = HalfUp
+)
+
 // Trunc returns the integer portion (truncating any fractional part)
 func (dn Value) Trunc() Value {
 	return dn.integer(Down)
@@ -983,6 +991,8 @@ func (dn Value) Floor() Value {
 	return dn.Round(0, Down)
 }
 
+// Round rounds the value to r decimal places with the given rounding mode,
+// the negative values are rounded symmetrically, e.g. -1.25 rounds down to -1.2 and up to -1.3.
 func (dn Value) Round(r int, mode RoundingMode) Value {
 	if dn.sign == 0 || dn.sign == signNegInf || dn.sign == signPosInf ||
 		r >= digitsMax {
@@ -125,6 +125,43 @@ func TestRound(t *testing.T) {
 	assert.Equal(t, "1.23", s.Round(2, Down).String())
 }
 
+func TestRound_Modes(t *testing.T) {
+	testCases := []struct {
+		value  string
+		places int
+		mode   RoundingMode
+		out    string
+	}{
+		{"1.2345", 2, RoundDown, "1.23"},
+		{"1.2345", 2, RoundUp, "1.24"},
+		{"1.2345", 2, RoundHalfUp, "1.23"},
+		{"1.235", 2, RoundHalfUp, "1.24"},
+		{"1.2345", 0, RoundDown, "1"},
+		{"1.2345", 0, RoundUp, "2"},
+		{"2.5", 0, RoundHalfUp, "3"},
+		{"-1.2345", 2, RoundDown, "-1.23"},
+		{"-1.2345", 2, RoundUp, "-1.24"},
+		{"-1.2345", 2, RoundHalfUp, "-1.23"},
+		{"-1.235", 2, RoundHalfUp, "-1.24"},
+		{"-1.2345", 0, RoundDown, "-1"},
+		{"-1.2345", 0, RoundUp, "-2"},
+		{"-2.5", 0, RoundHalfUp, "-3"},
+		{"-0.004", 2, RoundDown, "0"},
+		{"-0.004", 2, RoundUp, "-0.01"},
+		{"1.005", 2, RoundHalfUp, "1.01"},
+		{"1.23", 2, RoundUp, "1.23"},
+		{"1234.5", -2, RoundDown, "1200"},
+		{"1234.5", -2, RoundUp, "1300"},
+		{"0.12345678", 8, RoundDown, "0.12345678"},
+	}
+
+	for _, testCase := range testCases {
+		value := MustNewFromString(testCase.value)
+		assert.Equal(t, testCase.out, value.Round(testCase.places, testCase.mode).String(),
+			"%s round %d places with mode %d", testCase.value, testCase.places, testCase.mode)
+	}
+}
+
 func TestNewFromString(t *testing.T) {
 	f, err := NewFromString("0.00000003")
 	assert.NoError(t, err)
@@ -15,6 +15,8 @@ const MaxNumOfATRTruncateSize = 500
 type ATR struct {
 	types.SeriesBase
 	types.IntervalWindow
+	KLineWindowBinder
+
 	PercentageVolatility floats.Slice
 
 	PreviousClose float64
@@ -81,10 +83,13 @@ func (inc *ATR) Update(high, low, cloze float64) {
 	}
 }
 
+// Last returns the i-th ATR value from the latest one.
+// During the warmup (less than Window true ranges), there is no valid ATR value and 0 is returned.
 func (inc *ATR) Last(i int) float64 {
-	if inc.RMA == nil {
+	if i < 0 || i >= inc.Length() {
 		return 0
 	}
+
 	return inc.RMA.Last(i)
 }
 
@@ -92,12 +97,13 @@ func (inc *ATR) Index(i int) float64 {
 	return inc.Last(i)
 }
 
+// Length returns the number of the valid ATR values, the RMA values in the warmup period are excluded.
 func (inc *ATR) Length() int {
 	if inc.RMA == nil {
 		return 0
 	}
 
-	return inc.RMA.Length()
+	return inc.RMA.ValidLength()
 }
 
 func (inc *ATR) PushK(k types.KLine) {
@@ -109,3 +115,7 @@ func (inc *ATR) PushK(k types.KLine) {
 	inc.EndTime = k.EndTime.Time()
 	inc.EmitUpdate(inc.Last(0))
 }
+
+func (inc *ATR) Bind(updater KLineWindowUpdater) {
+	inc.BindKLineWindow(updater, inc.Interval, inc)
+}
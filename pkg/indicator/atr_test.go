@@ -5,6 +5,8 @@ import (
 	"math"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
 	"github.com/c9s/bbgo/pkg/fixedpoint"
 	"github.com/c9s/bbgo/pkg/types"
 )
@@ -73,3 +75,37 @@ func Test_calculateATR(t *testing.T) {
 		})
 	}
 }
+
+func Test_ATR_Warmup(t *testing.T) {
+	var Delta = 1e-9
+	// true ranges: 2, 3, 1, 3, 1, 3, 1
+	var bars = [][3]float64{
+		{11, 9, 10},
+		{12, 10, 11},
+		{13, 10, 12},
+		{12, 11, 11.5},
+		{14, 11, 13},
+		{13, 12, 12.5},
+		{15, 12, 14},
+		{14, 13, 13.5},
+	}
+
+	window := 5
+	atr := &ATR{IntervalWindow: types.IntervalWindow{Window: window}}
+	for i, bar := range bars {
+		atr.Update(bar[0], bar[1], bar[2])
+
+		// the first bar only provides the previous close, and the ATR needs window true ranges
+		if i < window {
+			assert.Equal(t, 0, atr.Length(), "bar %d", i)
+			assert.Equal(t, 0.0, atr.Last(0), "bar %d", i)
+		}
+	}
+
+	// window + 3 bars, the first valid ATR is the RMA of the first 5 true ranges
+	assert.Equal(t, len(bars)-window, atr.Length())
+	assert.InDelta(t, 1.902427415516421, atr.Index(2), Delta)
+	assert.InDelta(t, 2.1999306097666755, atr.Index(1), Delta)
+	assert.InDelta(t, 1.8962601836704946, atr.Last(0), Delta)
+	assert.Equal(t, 0.0, atr.Index(3), "the warmup values should not be exposed")
+}
@@ -0,0 +1,33 @@
+package indicator
+
+import (
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// KLineWindowBinder is embedded by the indicators to bind the kline window updater without re-implementing
+// the interval filtering, the indicator only needs to implement PushK (and the Update it calls).
+//
+// On the first window update of the interval, all the klines of the window are pushed to load the history,
+// after that only the last kline of the window is pushed.
+type KLineWindowBinder struct {
+	loaded bool
+}
+
+// BindKLineWindow registers the window update handler of the given interval to the updater
+func (b *KLineWindowBinder) BindKLineWindow(updater KLineWindowUpdater, interval types.Interval, pusher KLinePusher) {
+	updater.OnKLineWindowUpdate(func(windowInterval types.Interval, window types.KLineWindow) {
+		if windowInterval != interval || len(window) == 0 {
+			return
+		}
+
+		if !b.loaded {
+			b.loaded = true
+			for _, k := range window {
+				pusher.PushK(k)
+			}
+			return
+		}
+
+		pusher.PushK(window[len(window)-1])
+	})
+}
@@ -0,0 +1,84 @@
+package indicator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+type testKLineWindowUpdater struct {
+	callbacks []func(interval types.Interval, window types.KLineWindow)
+}
+
+func (u *testKLineWindowUpdater) OnKLineWindowUpdate(cb func(interval types.Interval, window types.KLineWindow)) {
+	u.callbacks = append(u.callbacks, cb)
+}
+
+func (u *testKLineWindowUpdater) EmitKLineWindowUpdate(interval types.Interval, window types.KLineWindow) {
+	for _, cb := range u.callbacks {
+		cb(interval, window)
+	}
+}
+
+func TestKLineWindowBinder(t *testing.T) {
+	startTime := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	var klines types.KLineWindow
+	for i := 0; i < 30; i++ {
+		price := 100.0 + float64(i%7) - float64(i%3)
+		klines = append(klines, types.KLine{
+			Interval:  types.Interval5m,
+			StartTime: types.Time(startTime.Add(time.Duration(i) * 5 * time.Minute)),
+			EndTime:   types.Time(startTime.Add(time.Duration(i+1)*5*time.Minute - time.Millisecond)),
+			High:      fixedpoint.NewFromFloat(price + 2),
+			Low:       fixedpoint.NewFromFloat(price - 1),
+			Close:     fixedpoint.NewFromFloat(price),
+		})
+	}
+
+	iw := types.IntervalWindow{Interval: types.Interval5m, Window: 5}
+
+	// the indicators pushed directly
+	expectedSMA := &SMA{IntervalWindow: iw}
+	expectedATR := &ATR{IntervalWindow: iw}
+	for _, k := range klines {
+		expectedSMA.PushK(k)
+		expectedATR.PushK(k)
+	}
+
+	// the indicators bound to the window updater
+	updater := &testKLineWindowUpdater{}
+	sma := &SMA{IntervalWindow: iw}
+	sma.Bind(updater)
+	atr := &ATR{IntervalWindow: iw}
+	atr.Bind(updater)
+
+	var smaUpdates int
+	sma.OnUpdate(func(value float64) {
+		smaUpdates++
+	})
+
+	// the first update loads the history window
+	updater.EmitKLineWindowUpdate(types.Interval5m, klines[:10])
+	assert.Equal(t, 6, sma.Length())
+
+	// the updates of the other intervals are ignored
+	updater.EmitKLineWindowUpdate(types.Interval1m, klines[:11])
+	assert.Equal(t, 6, sma.Length())
+
+	for i := 11; i <= len(klines); i++ {
+		updater.EmitKLineWindowUpdate(types.Interval5m, klines[:i])
+	}
+
+	assert.Equal(t, len(klines), smaUpdates)
+	assert.Equal(t, expectedSMA.Values, sma.Values)
+	assert.Equal(t, expectedATR.Length(), atr.Length())
+	for i := 0; i < atr.Length(); i++ {
+		assert.Equal(t, expectedATR.Last(i), atr.Last(i))
+	}
+	assert.Equal(t, expectedATR.PercentageVolatility, atr.PercentageVolatility)
+}
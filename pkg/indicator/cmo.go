@@ -0,0 +1,119 @@
+package indicator
+
+import (
+	"time"
+
+	"github.com/c9s/bbgo/pkg/datatype/floats"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const MaxNumOfCMO = 5_000
+const MaxNumOfCMOTruncateSize = 100
+
+// Refer: Chande Momentum Oscillator
+// Refer URL: https://www.investopedia.com/terms/c/chandemomentumoscillator.asp
+// The Chande Momentum Oscillator (CMO) measures the momentum with the sum of the up changes and the sum of the down changes
+// over the window: CMO = 100 * (up - down) / (up + down). It's bounded in [-100, 100], the values above 50 are usually
+// considered as overbought and the values below -50 are considered as oversold.
+
+//go:generate callbackgen -type CMO
+type CMO struct {
+	types.SeriesBase
+	types.IntervalWindow
+	Values floats.Slice
+	input  floats.Slice
+
+	EndTime time.Time
+
+	updateCallbacks []func(value float64)
+}
+
+// Update pushes the CMO value when there are at least Window changes, i.e. Window + 1 values
+func (inc *CMO) Update(value float64) {
+	if len(inc.input) == 0 {
+		inc.SeriesBase.Series = inc
+	}
+
+	inc.input.Push(value)
+	if len(inc.input) > MaxNumOfCMO {
+		inc.input = inc.input[MaxNumOfCMOTruncateSize-1:]
+	}
+
+	if len(inc.input) <= inc.Window {
+		return
+	}
+
+	inc.Values.Push(100.0 * calculateCMO(inc.input, inc.Window))
+	if len(inc.Values) > MaxNumOfCMO {
+		inc.Values = inc.Values[MaxNumOfCMOTruncateSize-1:]
+	}
+}
+
+func (inc *CMO) Last(i int) float64 {
+	return inc.Values.Last(i)
+}
+
+func (inc *CMO) Index(i int) float64 {
+	return inc.Last(i)
+}
+
+func (inc *CMO) Length() int {
+	return inc.Values.Length()
+}
+
+var _ types.SeriesExtend = &CMO{}
+
+func (inc *CMO) PushK(k types.KLine) {
+	if inc.EndTime != zeroTime && !k.EndTime.After(inc.EndTime) {
+		return
+	}
+
+	inc.Update(k.Close.Float64())
+	inc.EndTime = k.EndTime.Time()
+
+	if inc.Length() > 0 {
+		inc.EmitUpdate(inc.Last(0))
+	}
+}
+
+func (inc *CMO) CalculateAndUpdate(allKLines []types.KLine) {
+	if len(inc.input) == 0 {
+		for _, k := range allKLines {
+			inc.PushK(k)
+		}
+	} else {
+		inc.PushK(allKLines[len(allKLines)-1])
+	}
+}
+
+func (inc *CMO) handleKLineWindowUpdate(interval types.Interval, window types.KLineWindow) {
+	if inc.Interval != interval {
+		return
+	}
+
+	inc.CalculateAndUpdate(window)
+}
+
+func (inc *CMO) Bind(updater KLineWindowUpdater) {
+	updater.OnKLineWindowUpdate(inc.handleKLineWindowUpdate)
+}
+
+// calculateCMO returns (up - down) / (up + down) of the last window changes of the values,
+// fewer changes are used if there are not enough values, and 0 is returned if there is no change.
+func calculateCMO(values floats.Slice, window int) float64 {
+	var up, down float64
+	for i := 0; i < window && i+1 < len(values); i++ {
+		diff := values.Last(i) - values.Last(i+1)
+		if diff > 0 {
+			up += diff
+		} else {
+			down -= diff
+		}
+	}
+
+	if up+down == 0 {
+		return 0
+	}
+
+	return (up - down) / (up + down)
+}
@@ -0,0 +1,69 @@
+package indicator
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+/*
+python:
+
+data = [100, 102, 101, 105, 107, 106, 104, 108, 110, 109, 111, 113, 112]
+window = 5
+
+changes = [data[j] - data[j-1] for j in range(1, len(data))]
+up = [sum(c for c in changes[i-window:i] if c > 0) for i in range(window, len(changes)+1)]
+down = [-sum(c for c in changes[i-window:i] if c < 0) for i in range(window, len(changes)+1)]
+print([100 * (u - d) / (u + d) for u, d in zip(up, down)])
+*/
+func Test_CMO(t *testing.T) {
+	var Delta = 1e-9
+	data := []float64{100, 102, 101, 105, 107, 106, 104, 108, 110, 109, 111, 113, 112}
+	expected := []float64{60.0, 20.0, 53.84615384615385, 45.45454545454545, 20.0, 45.45454545454545, 81.81818181818181, 50.0}
+
+	cmo := &CMO{IntervalWindow: types.IntervalWindow{Window: 5}}
+	for i, v := range data {
+		cmo.Update(v)
+
+		// there is no value until the window has 5 changes
+		if i < 5 {
+			assert.Equal(t, 0, cmo.Length())
+		}
+	}
+
+	if assert.Equal(t, len(expected), cmo.Length()) {
+		for i, v := range expected {
+			assert.InDelta(t, v, cmo.Index(len(expected)-1-i), Delta)
+		}
+	}
+}
+
+func Test_CMO_Range(t *testing.T) {
+	cmo := &CMO{IntervalWindow: types.IntervalWindow{Window: 9}}
+	for i := 0; i < 200; i++ {
+		cmo.Update(100 + 10*math.Sin(float64(i)/5.0) + float64(i%7))
+	}
+
+	for i := 0; i < cmo.Length(); i++ {
+		assert.GreaterOrEqual(t, cmo.Index(i), -100.0)
+		assert.LessOrEqual(t, cmo.Index(i), 100.0)
+	}
+
+	// only rising values
+	rising := &CMO{IntervalWindow: types.IntervalWindow{Window: 3}}
+	for _, v := range []float64{1, 2, 3, 4} {
+		rising.Update(v)
+	}
+	assert.Equal(t, 100.0, rising.Last(0))
+
+	// no change, the CMO should be zero instead of NaN
+	flat := &CMO{IntervalWindow: types.IntervalWindow{Window: 3}}
+	for _, v := range []float64{1, 1, 1, 1} {
+		flat.Update(v)
+	}
+	assert.Equal(t, 0.0, flat.Last(0))
+}
@@ -23,6 +23,9 @@ type DEMA struct {
 	a1     *EWMA
 	a2     *EWMA
 
+	// SeedWithSMA seeds the nested EWMAs with the SMA of their first Window values
+	SeedWithSMA bool
+
 	UpdateCallbacks []func(value float64)
 }
 
@@ -32,6 +35,7 @@ func (inc *DEMA) Clone() *DEMA {
 		Values:         inc.Values[:],
 		a1:             inc.a1.Clone(),
 		a2:             inc.a2.Clone(),
+		SeedWithSMA:    inc.SeedWithSMA,
 	}
 	out.SeriesBase.Series = out
 	return out
@@ -44,20 +48,32 @@ func (inc *DEMA) TestUpdate(value float64) *DEMA {
 }
 
 func (inc *DEMA) Update(value float64) {
-	if len(inc.Values) == 0 {
+	if inc.a1 == nil {
 		inc.SeriesBase.Series = inc
-		inc.a1 = &EWMA{IntervalWindow: inc.IntervalWindow}
-		inc.a2 = &EWMA{IntervalWindow: inc.IntervalWindow}
+		inc.a1 = &EWMA{IntervalWindow: inc.IntervalWindow, SeedWithSMA: inc.SeedWithSMA}
+		inc.a2 = &EWMA{IntervalWindow: inc.IntervalWindow, SeedWithSMA: inc.SeedWithSMA}
 	}
 
 	inc.a1.Update(value)
+	if inc.a1.Length() == 0 {
+		return
+	}
+
 	inc.a2.Update(inc.a1.Last(0))
+	if inc.a2.Length() == 0 {
+		return
+	}
 	inc.Values.Push(2*inc.a1.Last(0) - inc.a2.Last(0))
 	if len(inc.Values) > MaxNumOfEWMA {
 		inc.Values = inc.Values[MaxNumOfEWMATruncateSize-1:]
 	}
 }
 
+// IsReady returns true when both of the nested EWMAs have seen Window values
+func (inc *DEMA) IsReady() bool {
+	return inc.a1 != nil && inc.a1.IsReady() && inc.a2.IsReady()
+}
+
 func (inc *DEMA) Last(i int) float64 {
 	return inc.Values.Last(i)
 }
@@ -53,3 +53,25 @@ func Test_DEMA(t *testing.T) {
 		})
 	}
 }
+
+func Test_DEMA_IsReady(t *testing.T) {
+	dema := &DEMA{IntervalWindow: types.IntervalWindow{Window: 5}}
+	assert.False(t, dema.IsReady())
+
+	for i := 0; i < 4; i++ {
+		dema.Update(float64(i))
+		assert.False(t, dema.IsReady(), "update %d", i)
+	}
+
+	// the values are emitted before the warmup is done
+	assert.Equal(t, 4, dema.Length())
+	assert.False(t, dema.a1.IsReady())
+	assert.False(t, dema.a2.IsReady())
+
+	dema.Update(4)
+	assert.True(t, dema.a1.IsReady())
+	assert.True(t, dema.a2.IsReady())
+	assert.True(t, dema.IsReady())
+
+	var _ ReadyIndicator = dema
+}
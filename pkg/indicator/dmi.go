@@ -69,7 +69,7 @@ func (inc *DMI) Update(high, low, cloze float64) {
 
 	inc.DMP.Update(pos)
 	inc.DMN.Update(neg)
-	if inc.atr.Length() < inc.Window {
+	if inc.atr.Length() == 0 {
 		return
 	}
 	k := 100. / inc.atr.Last(0)
@@ -0,0 +1,127 @@
+package indicator
+
+import (
+	"time"
+
+	"github.com/c9s/bbgo/pkg/datatype/floats"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const MaxNumOfDonchian = 5_000
+const MaxNumOfDonchianTruncateSize = 100
+
+/*
+donchian implements the Donchian Channels indicator:
+
+The upper band is the highest high and the lower band is the lowest low of the last Window bars,
+the middle band is the average of the upper and the lower bands.
+
+Donchian Channels
+- https://www.investopedia.com/terms/d/donchianchannels.asp
+*/
+
+//go:generate callbackgen -type DonchianChannel
+type DonchianChannel struct {
+	types.IntervalWindow
+
+	UpBand   floats.Slice
+	DownBand floats.Slice
+	MidBand  floats.Slice
+
+	highs *types.Queue
+	lows  *types.Queue
+
+	EndTime time.Time
+
+	updateCallbacks []func(upBand, downBand, midBand float64)
+}
+
+// Upper returns the highest high series
+func (inc *DonchianChannel) Upper() types.SeriesExtend {
+	return types.NewSeries(&inc.UpBand)
+}
+
+// Lower returns the lowest low series
+func (inc *DonchianChannel) Lower() types.SeriesExtend {
+	return types.NewSeries(&inc.DownBand)
+}
+
+// Mid returns the midline series of the upper and the lower bands
+func (inc *DonchianChannel) Mid() types.SeriesExtend {
+	return types.NewSeries(&inc.MidBand)
+}
+
+// Length returns the number of the channel values, there is no value until Window bars are updated
+func (inc *DonchianChannel) Length() int {
+	return len(inc.UpBand)
+}
+
+func (inc *DonchianChannel) Update(high, low float64) {
+	if inc.highs == nil {
+		inc.highs = types.NewQueue(inc.Window)
+		inc.lows = types.NewQueue(inc.Window)
+	}
+
+	inc.highs.Update(high)
+	inc.lows.Update(low)
+	if inc.highs.Length() < inc.Window {
+		return
+	}
+
+	upBand := types.Highest(inc.highs, inc.Window)
+	downBand := types.Lowest(inc.lows, inc.Window)
+
+	inc.UpBand.Push(upBand)
+	inc.DownBand.Push(downBand)
+	inc.MidBand.Push((upBand + downBand) / 2.0)
+
+	if len(inc.UpBand) > MaxNumOfDonchian {
+		inc.UpBand = inc.UpBand[MaxNumOfDonchianTruncateSize-1:]
+		inc.DownBand = inc.DownBand[MaxNumOfDonchianTruncateSize-1:]
+		inc.MidBand = inc.MidBand[MaxNumOfDonchianTruncateSize-1:]
+	}
+}
+
+func (inc *DonchianChannel) BindK(target KLineClosedEmitter, symbol string, interval types.Interval) {
+	target.OnKLineClosed(types.KLineWith(symbol, interval, inc.PushK))
+}
+
+func (inc *DonchianChannel) PushK(k types.KLine) {
+	if inc.EndTime != zeroTime && !k.EndTime.After(inc.EndTime) {
+		return
+	}
+
+	inc.Update(k.High.Float64(), k.Low.Float64())
+	inc.EndTime = k.EndTime.Time()
+
+	if inc.Length() > 0 {
+		inc.EmitUpdate(inc.UpBand.Last(0), inc.DownBand.Last(0), inc.MidBand.Last(0))
+	}
+}
+
+func (inc *DonchianChannel) LoadK(allKLines []types.KLine) {
+	for _, k := range allKLines {
+		inc.PushK(k)
+	}
+}
+
+func (inc *DonchianChannel) CalculateAndUpdate(allKLines []types.KLine) {
+	if inc.highs == nil {
+		inc.LoadK(allKLines)
+		return
+	}
+
+	inc.PushK(allKLines[len(allKLines)-1])
+}
+
+func (inc *DonchianChannel) handleKLineWindowUpdate(interval types.Interval, window types.KLineWindow) {
+	if inc.Interval != interval {
+		return
+	}
+
+	inc.CalculateAndUpdate(window)
+}
+
+func (inc *DonchianChannel) Bind(updater KLineWindowUpdater) {
+	updater.OnKLineWindowUpdate(inc.handleKLineWindowUpdate)
+}
@@ -0,0 +1,67 @@
+package indicator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestDonchianChannel(t *testing.T) {
+	var Delta = 1e-9
+	// high, low
+	bars := [][2]int64{
+		{10, 8},
+		{15, 9},
+		{12, 7},
+		{11, 9},
+		{13, 10},
+		{14, 11},
+	}
+
+	startTime := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	var window types.KLineWindow
+	for i, bar := range bars {
+		window.Add(types.KLine{
+			Interval:  types.Interval1m,
+			StartTime: types.Time(startTime.Add(time.Duration(i) * time.Minute)),
+			EndTime:   types.Time(startTime.Add(time.Duration(i+1)*time.Minute - time.Millisecond)),
+			High:      fixedpoint.NewFromInt(bar[0]),
+			Low:       fixedpoint.NewFromInt(bar[1]),
+		})
+	}
+
+	var updates [][3]float64
+	dc := &DonchianChannel{IntervalWindow: types.IntervalWindow{Interval: types.Interval1m, Window: 3}}
+	dc.OnUpdate(func(upBand, downBand, midBand float64) {
+		updates = append(updates, [3]float64{upBand, downBand, midBand})
+	})
+
+	// the first 3 bars fill the window
+	dc.handleKLineWindowUpdate(types.Interval1m, window[:3])
+	assert.Equal(t, 1, dc.Length())
+	assert.InDelta(t, 15.0, dc.Upper().Last(0), Delta)
+	assert.InDelta(t, 7.0, dc.Lower().Last(0), Delta)
+	assert.InDelta(t, 11.0, dc.Mid().Last(0), Delta)
+
+	// the other interval is ignored
+	dc.handleKLineWindowUpdate(types.Interval5m, window)
+	assert.Equal(t, 1, dc.Length())
+
+	for i := 4; i <= len(window); i++ {
+		dc.handleKLineWindowUpdate(types.Interval1m, window[:i])
+	}
+
+	// the high 15 drops out of the window at the 5th bar, and the low 7 drops out at the 6th bar
+	assert.InDeltaSlice(t, []float64{15, 15, 13, 14}, []float64(dc.UpBand), Delta)
+	assert.InDeltaSlice(t, []float64{7, 7, 7, 9}, []float64(dc.DownBand), Delta)
+	assert.InDeltaSlice(t, []float64{11, 11, 10, 11.5}, []float64(dc.MidBand), Delta)
+	assert.InDelta(t, 13.0, dc.Upper().Last(1), Delta)
+	if assert.Len(t, updates, 4) {
+		assert.InDeltaSlice(t, []float64{14, 9, 11.5}, updates[3][:], Delta)
+	}
+}
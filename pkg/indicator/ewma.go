@@ -19,6 +19,17 @@ type EWMA struct {
 	Values  floats.Slice
 	EndTime time.Time
 
+	// SeedWithSMA buffers the first Window values and uses their SMA as the initial EWMA value,
+	// otherwise the EWMA is initialized with the first value.
+	SeedWithSMA bool
+
+	// PriceType is the kline price pushed by PushK, defaults to the close price
+	PriceType PriceType
+
+	// counter is the number of the updates, which is used for checking the warmup
+	counter int
+	seedSum float64
+
 	updateCallbacks []func(value float64)
 }
 
@@ -28,6 +39,10 @@ func (inc *EWMA) Clone() *EWMA {
 	out := &EWMA{
 		IntervalWindow: inc.IntervalWindow,
 		Values:         inc.Values[:],
+		SeedWithSMA:    inc.SeedWithSMA,
+		PriceType:      inc.PriceType,
+		counter:        inc.counter,
+		seedSum:        inc.seedSum,
 	}
 	out.SeriesBase.Series = out
 	return out
@@ -42,6 +57,16 @@ func (inc *EWMA) TestUpdate(value float64) *EWMA {
 func (inc *EWMA) Update(value float64) {
 	var multiplier = 2.0 / float64(1+inc.Window)
 
+	inc.counter++
+	if inc.SeedWithSMA && inc.counter <= inc.Window {
+		inc.SeriesBase.Series = inc
+		inc.seedSum += value
+		if inc.counter == inc.Window {
+			inc.Values.Push(inc.seedSum / float64(inc.Window))
+		}
+		return
+	}
+
 	if len(inc.Values) == 0 {
 		inc.SeriesBase.Series = inc
 		inc.Values.Push(value)
@@ -54,6 +79,11 @@ func (inc *EWMA) Update(value float64) {
 	inc.Values.Push(ema)
 }
 
+// IsReady returns true when the EWMA has been updated with at least Window values
+func (inc *EWMA) IsReady() bool {
+	return inc.counter >= inc.Window
+}
+
 func (inc *EWMA) Last(i int) float64 {
 	return inc.Values.Last(i)
 }
@@ -71,7 +101,7 @@ func (inc *EWMA) PushK(k types.KLine) {
 		return
 	}
 
-	inc.Update(k.Close.Float64())
+	inc.Update(SelectPrice(k, inc.PriceType))
 	inc.EndTime = k.EndTime.Time()
 	inc.EmitUpdate(inc.Last(0))
 }
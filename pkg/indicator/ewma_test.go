@@ -5,6 +5,9 @@ import (
 	"math"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/datatype/floats"
 	"github.com/c9s/bbgo/pkg/fixedpoint"
 	"github.com/c9s/bbgo/pkg/types"
 )
@@ -1077,3 +1080,59 @@ func Test_calculateEWMA(t *testing.T) {
 		})
 	}
 }
+
+func Test_EWMA_FloatsSlice(t *testing.T) {
+	var input floats.Slice
+	if err := json.Unmarshal(ethusdt5m, &input); err != nil {
+		panic(err)
+	}
+
+	ewma := EWMA{IntervalWindow: types.IntervalWindow{Window: 25}}
+	for _, v := range input {
+		ewma.Update(v)
+	}
+
+	values := input.EMA(25)
+	assert.Equal(t, input.Length(), values.Length())
+	assert.InDelta(t, ewma.Last(0), values.Last(0), 1e-9)
+	assert.InDelta(t, ewma.Last(10), values.Last(10), 1e-9)
+}
+
+func Test_EWMA_SeedWithSMA(t *testing.T) {
+	var Delta = 1e-9
+	unseeded := &EWMA{IntervalWindow: types.IntervalWindow{Window: 3}}
+	seeded := &EWMA{IntervalWindow: types.IntervalWindow{Window: 3}, SeedWithSMA: true}
+
+	for _, v := range []float64{1, 2, 3, 4, 5} {
+		unseeded.Update(v)
+		seeded.Update(v)
+	}
+
+	// the unseeded EWMA starts from the first value: 1, 1.5, 2.25, 3.125, 4.0625
+	assert.Equal(t, 5, unseeded.Length())
+	assert.InDelta(t, 1.0, unseeded.Index(4), Delta)
+	assert.InDelta(t, 2.25, unseeded.Index(2), Delta)
+	assert.InDelta(t, 4.0625, unseeded.Last(0), Delta)
+
+	// the seeded EWMA starts from SMA(1, 2, 3) = 2: 2, 3, 4
+	assert.Equal(t, 3, seeded.Length())
+	assert.InDelta(t, 2.0, seeded.Index(2), Delta)
+	assert.InDelta(t, 3.0, seeded.Index(1), Delta)
+	assert.InDelta(t, 4.0, seeded.Last(0), Delta)
+	assert.True(t, seeded.IsReady())
+}
+
+func Test_DEMA_SeedWithSMA(t *testing.T) {
+	var Delta = 1e-9
+	dema := &DEMA{IntervalWindow: types.IntervalWindow{Window: 2}, SeedWithSMA: true}
+
+	dema.Update(1)
+	dema.Update(2)
+	assert.Equal(t, 0, dema.Length(), "the second EWMA is not seeded yet")
+
+	// a1: 1.5, 2.5, a2: SMA(1.5, 2.5) = 2.0
+	dema.Update(3)
+	assert.Equal(t, 1, dema.Length())
+	assert.InDelta(t, 3.0, dema.Last(0), Delta)
+	assert.True(t, dema.IsReady())
+}
@@ -0,0 +1,122 @@
+package indicator
+
+import (
+	"math"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/datatype/floats"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const MaxNumOfHMA = 5_000
+const MaxNumOfHMATruncateSize = 100
+
+// Refer: Hull Moving Average
+// Refer URL: https://school.stockcharts.com/doku.php?id=technical_indicators:hull_moving_average
+//
+// Unlike the HULL indicator which approximates the smoothing with the exponential moving averages,
+// HMA implements the original definition with the linear weighted moving averages:
+//
+//	HMA = WMA(2 * WMA(price, n / 2) - WMA(price, n), sqrt(n))
+//
+// The sub-periods n / 2 and sqrt(n) are rounded down, which is the same as TradingView's ta.hma.
+//
+//go:generate callbackgen -type HMA
+type HMA struct {
+	types.SeriesBase
+	types.IntervalWindow
+
+	Values  floats.Slice
+	EndTime time.Time
+
+	halfWMA   *WMA
+	fullWMA   *WMA
+	resultWMA *WMA
+
+	updateCallbacks []func(value float64)
+}
+
+var _ types.SeriesExtend = &HMA{}
+
+func (inc *HMA) Update(value float64) {
+	if inc.fullWMA == nil {
+		inc.SeriesBase.Series = inc
+		halfWindow := inc.Window / 2
+		if halfWindow < 1 {
+			halfWindow = 1
+		}
+
+		sqrtWindow := int(math.Sqrt(float64(inc.Window)))
+		if sqrtWindow < 1 {
+			sqrtWindow = 1
+		}
+
+		inc.halfWMA = &WMA{IntervalWindow: types.IntervalWindow{Interval: inc.Interval, Window: halfWindow}}
+		inc.fullWMA = &WMA{IntervalWindow: inc.IntervalWindow}
+		inc.resultWMA = &WMA{IntervalWindow: types.IntervalWindow{Interval: inc.Interval, Window: sqrtWindow}}
+	}
+
+	inc.halfWMA.Update(value)
+	inc.fullWMA.Update(value)
+	if inc.fullWMA.Length() == 0 {
+		return
+	}
+
+	inc.resultWMA.Update(2*inc.halfWMA.Last(0) - inc.fullWMA.Last(0))
+	if inc.resultWMA.Length() == 0 {
+		return
+	}
+
+	inc.Values.Push(inc.resultWMA.Last(0))
+	if len(inc.Values) > MaxNumOfHMA {
+		inc.Values = inc.Values[MaxNumOfHMATruncateSize-1:]
+	}
+}
+
+func (inc *HMA) Last(i int) float64 {
+	return inc.Values.Last(i)
+}
+
+func (inc *HMA) Index(i int) float64 {
+	return inc.Last(i)
+}
+
+func (inc *HMA) Length() int {
+	return len(inc.Values)
+}
+
+func (inc *HMA) PushK(k types.KLine) {
+	if inc.EndTime != zeroTime && k.EndTime.Before(inc.EndTime) {
+		return
+	}
+
+	inc.Update(k.Close.Float64())
+	inc.EndTime = k.EndTime.Time()
+	inc.EmitUpdate(inc.Last(0))
+}
+
+func (inc *HMA) CalculateAndUpdate(allKLines []types.KLine) {
+	if len(allKLines) == 0 {
+		return
+	}
+
+	if inc.fullWMA == nil {
+		for _, k := range allKLines {
+			inc.PushK(k)
+		}
+	} else {
+		inc.PushK(allKLines[len(allKLines)-1])
+	}
+}
+
+func (inc *HMA) handleKLineWindowUpdate(interval types.Interval, window types.KLineWindow) {
+	if inc.Interval != interval {
+		return
+	}
+
+	inc.CalculateAndUpdate(window)
+}
+
+func (inc *HMA) Bind(updater KLineWindowUpdater) {
+	updater.OnKLineWindowUpdate(inc.handleKLineWindowUpdate)
+}
@@ -0,0 +1,78 @@
+package indicator
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+/*
+python:
+
+import pandas as pd
+import numpy as np
+
+wma = lambda s, n: s.rolling(n).apply(lambda x: np.dot(x, np.arange(1, n + 1)) / np.arange(1, n + 1).sum(), raw=True)
+
+s = pd.Series([0,1,2,3,4,5,6,7,8,9,0,1,2,3,4,5,6,7,8,9,0,1,2,3,4,5,6,7,8,9,0,1,2,3,4,5,6,7,8,9,0,1,2,3,4,5,6,7,8,9])
+n = 9
+result = wma(2 * wma(s, n // 2) - wma(s, n), int(np.sqrt(n)))
+print(result.dropna())
+*/
+func Test_HMA(t *testing.T) {
+	var Delta = 1e-9
+	var randomPrices = []byte(`[0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 0, 1, 2, 3, 4, 5, 6, 7, 8, 9]`)
+	var input []fixedpoint.Value
+	if err := json.Unmarshal(randomPrices, &input); err != nil {
+		panic(err)
+	}
+
+	tests := []struct {
+		name   string
+		window int
+		kLines []types.KLine
+		want   float64
+		next   float64
+		first  float64
+		all    int
+	}{
+		{
+			// the sub-periods of the odd window are rounded down: 9 / 2 = 4 and sqrt(9) = 3
+			name:   "odd_window",
+			window: 9,
+			kLines: buildKLines(input),
+			want:   8.962962962962964,
+			next:   7.814814814814816,
+			first:  7.0,
+			all:    40,
+		},
+		{
+			// sqrt(10) = 3.16 is rounded down to 3
+			name:   "fractional_sqrt_window",
+			window: 10,
+			kLines: buildKLines(input),
+			want:   8.515151515151516,
+			next:   7.212121212121214,
+			first:  4.777777777777778,
+			all:    39,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hma := &HMA{IntervalWindow: types.IntervalWindow{Window: tt.window}}
+			for _, k := range tt.kLines {
+				hma.PushK(k)
+			}
+
+			assert.InDelta(t, tt.want, hma.Last(0), Delta)
+			assert.InDelta(t, tt.next, hma.Index(1), Delta)
+			assert.InDelta(t, tt.first, hma.Index(hma.Length()-1), Delta)
+			assert.Equal(t, tt.all, hma.Length())
+		})
+	}
+}
@@ -29,6 +29,12 @@ type Simple interface {
 	OnUpdate(f func(value float64))
 }
 
+// ReadyIndicator is implemented by the indicators which need a warmup period,
+// IsReady returns true when the indicator has seen enough data for its window.
+type ReadyIndicator interface {
+	IsReady() bool
+}
+
 type KLineCalculateUpdater interface {
 	CalculateAndUpdate(allKLines []types.KLine)
 }
@@ -18,6 +18,9 @@ type PivotHigh struct {
 	EndTime time.Time
 
 	updateCallbacks []func(value float64)
+
+	// pivotCallbacks are called with the pivot price and the index of the pivot bar in Highs when a new pivot is found
+	pivotCallbacks []func(price float64, index int)
 }
 
 func (inc *PivotHigh) Length() int {
@@ -50,6 +53,7 @@ func (inc *PivotHigh) Update(value float64) {
 
 	if high > 0.0 {
 		inc.Values.Push(high)
+		inc.EmitPivot(high, len(inc.Highs)-1-*inc.RightWindow)
 	}
 }
 
@@ -0,0 +1,60 @@
+package indicator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestPivotHighLow_OnPivot(t *testing.T) {
+	// one clear pivot high at index 3 and one clear pivot low at index 8
+	prices := []int64{10, 11, 12, 15, 12, 11, 10, 8, 5, 8, 9, 10}
+
+	startTime := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	right := 2
+	pivotHigh := &PivotHigh{IntervalWindow: types.IntervalWindow{Window: 2, RightWindow: &right}}
+	pivotLow := &PivotLow{IntervalWindow: types.IntervalWindow{Window: 2, RightWindow: &right}}
+
+	type pivot struct {
+		price float64
+		index int
+	}
+
+	var highs, lows []pivot
+	pivotHigh.OnPivot(func(price float64, index int) {
+		highs = append(highs, pivot{price, index})
+	})
+	pivotLow.OnPivot(func(price float64, index int) {
+		lows = append(lows, pivot{price, index})
+	})
+
+	for i, p := range prices {
+		k := types.KLine{
+			EndTime: types.Time(startTime.Add(time.Duration(i+1)*time.Minute - time.Millisecond)),
+			High:    fixedpoint.NewFromInt(p + 1),
+			Low:     fixedpoint.NewFromInt(p - 1),
+		}
+		pivotHigh.PushK(k)
+		pivotLow.PushK(k)
+	}
+
+	if assert.Len(t, highs, 1) {
+		assert.InDelta(t, 16.0, highs[0].price, 1e-9)
+		assert.Equal(t, 3, highs[0].index)
+	}
+
+	if assert.Len(t, lows, 1) {
+		assert.InDelta(t, 4.0, lows[0].price, 1e-9)
+		assert.Equal(t, 8, lows[0].index)
+	}
+
+	assert.Equal(t, 1, pivotHigh.Length())
+	assert.InDelta(t, 16.0, pivotHigh.Last(0), 1e-9)
+	assert.Equal(t, 1, pivotLow.Length())
+	assert.InDelta(t, 4.0, pivotLow.Last(0), 1e-9)
+}
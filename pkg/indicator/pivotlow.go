@@ -18,6 +18,9 @@ type PivotLow struct {
 	EndTime time.Time
 
 	updateCallbacks []func(value float64)
+
+	// pivotCallbacks are called with the pivot price and the index of the pivot bar in Lows when a new pivot is found
+	pivotCallbacks []func(price float64, index int)
 }
 
 func (inc *PivotLow) Length() int {
@@ -50,6 +53,7 @@ func (inc *PivotLow) Update(value float64) {
 
 	if low > 0.0 {
 		inc.Values.Push(low)
+		inc.EmitPivot(low, len(inc.Lows)-1-*inc.RightWindow)
 	}
 }
 
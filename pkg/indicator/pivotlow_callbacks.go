@@ -13,3 +13,13 @@ func (inc *PivotLow) EmitUpdate(value float64) {
 		cb(value)
 	}
 }
+
+func (inc *PivotLow) OnPivot(cb func(price float64, index int)) {
+	inc.pivotCallbacks = append(inc.pivotCallbacks, cb)
+}
+
+func (inc *PivotLow) EmitPivot(price float64, index int) {
+	for _, cb := range inc.pivotCallbacks {
+		cb(price, index)
+	}
+}
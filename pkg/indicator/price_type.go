@@ -0,0 +1,40 @@
+package indicator
+
+import (
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// PriceType is the price source of the kline for the indicators, the empty price type is the close price
+type PriceType string
+
+const (
+	PriceTypeClose   PriceType = "close"
+	PriceTypeOpen    PriceType = "open"
+	PriceTypeHigh    PriceType = "high"
+	PriceTypeLow     PriceType = "low"
+	PriceTypeTypical PriceType = "typical"
+	PriceTypeHL2     PriceType = "hl2"
+	PriceTypeHLC3    PriceType = "hlc3"
+	PriceTypeOHLC4   PriceType = "ohlc4"
+)
+
+// SelectPrice returns the price of the kline by the price type, it falls back to the close price
+func SelectPrice(k types.KLine, pt PriceType) float64 {
+	switch pt {
+	case PriceTypeOpen:
+		return types.KLineOpenPriceMapper(k)
+	case PriceTypeHigh:
+		return types.KLineHighPriceMapper(k)
+	case PriceTypeLow:
+		return types.KLineLowPriceMapper(k)
+	case PriceTypeTypical, PriceTypeHLC3:
+		// the typical price is (high + low + close) / 3
+		return types.KLineTypicalPriceMapper(k)
+	case PriceTypeHL2:
+		return (k.High.Float64() + k.Low.Float64()) / 2.
+	case PriceTypeOHLC4:
+		return (k.Open.Float64() + k.High.Float64() + k.Low.Float64() + k.Close.Float64()) / 4.
+	}
+
+	return types.KLineClosePriceMapper(k)
+}
@@ -0,0 +1,65 @@
+package indicator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestSelectPrice(t *testing.T) {
+	var Delta = 1e-9
+	k := types.KLine{
+		Open:  fixedpoint.NewFromInt(10),
+		High:  fixedpoint.NewFromInt(14),
+		Low:   fixedpoint.NewFromInt(6),
+		Close: fixedpoint.NewFromInt(12),
+	}
+
+	testCases := []struct {
+		priceType PriceType
+		expected  float64
+	}{
+		{"", 12},
+		{PriceTypeClose, 12},
+		{PriceTypeOpen, 10},
+		{PriceTypeHigh, 14},
+		{PriceTypeLow, 6},
+		{PriceTypeTypical, 32.0 / 3.0},
+		{PriceTypeHL2, 10},
+		{PriceTypeHLC3, 32.0 / 3.0},
+		{PriceTypeOHLC4, 10.5},
+	}
+
+	for _, testCase := range testCases {
+		assert.InDelta(t, testCase.expected, SelectPrice(k, testCase.priceType), Delta, "price type %q", testCase.priceType)
+	}
+}
+
+func TestEWMA_PriceType(t *testing.T) {
+	var Delta = 1e-9
+	klines := []types.KLine{
+		{High: fixedpoint.NewFromInt(12), Low: fixedpoint.NewFromInt(8), Close: fixedpoint.NewFromInt(11)},
+		{High: fixedpoint.NewFromInt(14), Low: fixedpoint.NewFromInt(10), Close: fixedpoint.NewFromInt(10)},
+	}
+
+	ewma := &EWMA{IntervalWindow: types.IntervalWindow{Window: 3}, PriceType: PriceTypeHL2}
+	sma := &SMA{IntervalWindow: types.IntervalWindow{Window: 2}, PriceType: PriceTypeHL2}
+	for _, k := range klines {
+		ewma.PushK(k)
+		sma.PushK(k)
+	}
+
+	// hl2: 10, 12
+	assert.InDelta(t, 0.5*10+0.5*12, ewma.Last(0), Delta)
+	assert.InDelta(t, 11.0, sma.Last(0), Delta)
+
+	// the close price is used by default
+	closeSMA := &SMA{IntervalWindow: types.IntervalWindow{Window: 2}}
+	for _, k := range klines {
+		closeSMA.PushK(k)
+	}
+	assert.InDelta(t, 10.5, closeSMA.Last(0), Delta)
+}
@@ -81,10 +81,33 @@ func (inc *RMA) Index(i int) float64 {
 	return inc.Last(i)
 }
 
+// Length returns the number of the values including the values in the warmup period,
+// use ValidLength for the number of the values computed with at least Window inputs.
 func (inc *RMA) Length() int {
 	return len(inc.Values)
 }
 
+// IsReady returns true when the RMA has been updated with at least Window values
+func (inc *RMA) IsReady() bool {
+	return inc.counter >= inc.Window
+}
+
+// ValidLength returns the number of the latest values that are computed with at least Window inputs,
+// Last(i) with i < ValidLength() is a valid moving average, the older ones are the warmup values.
+func (inc *RMA) ValidLength() int {
+	length := inc.counter - inc.Window + 1
+	if length <= 0 {
+		return 0
+	}
+
+	// the values might be truncated
+	if length > len(inc.Values) {
+		return len(inc.Values)
+	}
+
+	return length
+}
+
 var _ types.SeriesExtend = &RMA{}
 
 func (inc *RMA) PushK(k types.KLine) {
@@ -70,3 +70,30 @@ func Test_RMA(t *testing.T) {
 		})
 	}
 }
+
+func Test_RMA_Warmup(t *testing.T) {
+	window := 3
+	values := []float64{1, 2, 3, 4, 5, 6}
+
+	rma := &RMA{IntervalWindow: types.IntervalWindow{Window: window}, Adjust: true}
+	sma := &SMA{IntervalWindow: types.IntervalWindow{Window: window}}
+	ewma := &EWMA{IntervalWindow: types.IntervalWindow{Window: window}, SeedWithSMA: true}
+
+	for i, v := range values {
+		rma.Update(v)
+		sma.Update(v)
+		ewma.Update(v)
+
+		// the warmup values are kept in the RMA, but they are not counted as valid values
+		assert.Equal(t, i+1, rma.Length(), "update %d", i)
+		assert.Equal(t, sma.Length(), rma.ValidLength(), "update %d", i)
+		assert.Equal(t, ewma.Length(), rma.ValidLength(), "update %d", i)
+		assert.Equal(t, ewma.IsReady(), rma.IsReady(), "update %d", i)
+	}
+
+	// the valid values are the running averages of the increasing inputs
+	for i := 0; i < rma.ValidLength(); i++ {
+		assert.NotZero(t, rma.Index(i))
+	}
+	assert.Greater(t, rma.Last(0), rma.Last(1))
+}
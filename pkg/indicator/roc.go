@@ -0,0 +1,105 @@
+package indicator
+
+import (
+	"time"
+
+	"github.com/c9s/bbgo/pkg/datatype/floats"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const MaxNumOfROC = 5_000
+const MaxNumOfROCTruncateSize = 100
+
+// Refer: Rate of Change
+// Refer URL: https://www.investopedia.com/terms/p/pricerateofchange.asp
+// The Rate of Change (ROC) is the percentage change between the current value and the value Window periods ago:
+// ROC = 100 * (value - value[Window]) / value[Window]
+
+//go:generate callbackgen -type ROC
+type ROC struct {
+	types.SeriesBase
+	types.IntervalWindow
+	Values floats.Slice
+	input  floats.Slice
+
+	EndTime time.Time
+
+	updateCallbacks []func(value float64)
+}
+
+// Update pushes the ROC value when there are at least Window + 1 values,
+// 0 is pushed if the value Window periods ago is zero.
+func (inc *ROC) Update(value float64) {
+	if len(inc.input) == 0 {
+		inc.SeriesBase.Series = inc
+	}
+
+	inc.input.Push(value)
+	if len(inc.input) > MaxNumOfROC {
+		inc.input = inc.input[MaxNumOfROCTruncateSize-1:]
+	}
+
+	if len(inc.input) <= inc.Window {
+		return
+	}
+
+	roc := 0.0
+	if prev := inc.input.Last(inc.Window); prev != 0 {
+		roc = 100.0 * (value - prev) / prev
+	}
+
+	inc.Values.Push(roc)
+	if len(inc.Values) > MaxNumOfROC {
+		inc.Values = inc.Values[MaxNumOfROCTruncateSize-1:]
+	}
+}
+
+func (inc *ROC) Last(i int) float64 {
+	return inc.Values.Last(i)
+}
+
+func (inc *ROC) Index(i int) float64 {
+	return inc.Last(i)
+}
+
+func (inc *ROC) Length() int {
+	return inc.Values.Length()
+}
+
+var _ types.SeriesExtend = &ROC{}
+
+func (inc *ROC) PushK(k types.KLine) {
+	if inc.EndTime != zeroTime && !k.EndTime.After(inc.EndTime) {
+		return
+	}
+
+	inc.Update(k.Close.Float64())
+	inc.EndTime = k.EndTime.Time()
+
+	// no update during the warmup
+	if inc.Length() > 0 {
+		inc.EmitUpdate(inc.Last(0))
+	}
+}
+
+func (inc *ROC) CalculateAndUpdate(allKLines []types.KLine) {
+	if len(inc.input) == 0 {
+		for _, k := range allKLines {
+			inc.PushK(k)
+		}
+	} else {
+		inc.PushK(allKLines[len(allKLines)-1])
+	}
+}
+
+func (inc *ROC) handleKLineWindowUpdate(interval types.Interval, window types.KLineWindow) {
+	if inc.Interval != interval {
+		return
+	}
+
+	inc.CalculateAndUpdate(window)
+}
+
+func (inc *ROC) Bind(updater KLineWindowUpdater) {
+	updater.OnKLineWindowUpdate(inc.handleKLineWindowUpdate)
+}
@@ -0,0 +1,59 @@
+package indicator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func Test_ROC(t *testing.T) {
+	var Delta = 1e-9
+	closes := []int64{100, 101, 103, 102, 105, 107, 106, 110, 108, 111, 115, 112, 120}
+
+	startTime := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	var klines []types.KLine
+	for i, c := range closes {
+		klines = append(klines, types.KLine{
+			Interval: types.Interval1m,
+			EndTime:  types.Time(startTime.Add(time.Duration(i+1)*time.Minute - time.Millisecond)),
+			Close:    fixedpoint.NewFromInt(c),
+		})
+	}
+
+	roc := &ROC{IntervalWindow: types.IntervalWindow{Interval: types.Interval1m, Window: 10}}
+
+	var updates []float64
+	roc.OnUpdate(func(value float64) {
+		updates = append(updates, value)
+	})
+
+	// 10 klines are not enough for ROC-10
+	roc.handleKLineWindowUpdate(types.Interval1m, klines[:10])
+	assert.Equal(t, 0, roc.Length())
+	assert.Empty(t, updates)
+
+	for i := 11; i <= len(klines); i++ {
+		roc.handleKLineWindowUpdate(types.Interval1m, klines[:i])
+	}
+
+	// 100 * (115 - 100) / 100, 100 * (112 - 101) / 101, 100 * (120 - 103) / 103
+	expected := []float64{15.0, 1100.0 / 101.0, 1700.0 / 103.0}
+	assert.InDeltaSlice(t, expected, []float64(roc.Values), Delta)
+	assert.InDeltaSlice(t, expected, updates, Delta)
+	assert.InDelta(t, 1700.0/103.0, roc.Last(0), Delta)
+}
+
+func Test_ROC_ZeroDenominator(t *testing.T) {
+	roc := &ROC{IntervalWindow: types.IntervalWindow{Window: 2}}
+	for _, v := range []float64{0, 1, 2, 3} {
+		roc.Update(v)
+	}
+
+	// the value 2 periods before 2 is zero
+	assert.Equal(t, []float64{0, 200}, []float64(roc.Values))
+}
@@ -14,10 +14,15 @@ const MaxNumOfSMATruncateSize = 100
 type SMA struct {
 	types.SeriesBase
 	types.IntervalWindow
+	KLineWindowBinder
+
 	Values    floats.Slice
 	rawValues *types.Queue
 	EndTime   time.Time
 
+	// PriceType is the kline price pushed by PushK, defaults to the close price
+	PriceType PriceType
+
 	UpdateCallbacks []func(value float64)
 }
 
@@ -38,6 +43,7 @@ func (inc *SMA) Clone() types.UpdatableSeriesExtend {
 		Values:    inc.Values[:],
 		rawValues: inc.rawValues.Clone(),
 		EndTime:   inc.EndTime,
+		PriceType: inc.PriceType,
 	}
 	out.SeriesBase.Series = out
 	return out
@@ -71,7 +77,7 @@ func (inc *SMA) PushK(k types.KLine) {
 		return
 	}
 
-	inc.Update(k.Close.Float64())
+	inc.Update(SelectPrice(k, inc.PriceType))
 	inc.EndTime = k.EndTime.Time()
 	inc.EmitUpdate(inc.Values.Last(0))
 }
@@ -81,3 +87,7 @@ func (inc *SMA) LoadK(allKLines []types.KLine) {
 		inc.PushK(k)
 	}
 }
+
+func (inc *SMA) Bind(updater KLineWindowUpdater) {
+	inc.BindKLineWindow(updater, inc.Interval, inc)
+}
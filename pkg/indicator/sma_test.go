@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/c9s/bbgo/pkg/datatype/floats"
 	"github.com/c9s/bbgo/pkg/fixedpoint"
 	"github.com/c9s/bbgo/pkg/types"
 )
@@ -66,3 +67,20 @@ func Test_SMA(t *testing.T) {
 		})
 	}
 }
+
+func Test_SMA_FloatsSlice(t *testing.T) {
+	var input floats.Slice
+	if err := json.Unmarshal(ethusdt5m, &input); err != nil {
+		panic(err)
+	}
+
+	sma := SMA{IntervalWindow: types.IntervalWindow{Window: 25}}
+	for _, v := range input {
+		sma.Update(v)
+	}
+
+	values := input.SMA(25)
+	assert.Equal(t, sma.Length(), values.Length())
+	assert.InDelta(t, sma.Last(0), values.Last(0), 1e-9)
+	assert.InDelta(t, sma.Last(10), values.Last(10), 1e-9)
+}
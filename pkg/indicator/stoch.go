@@ -19,8 +19,11 @@ const DPeriod int = 3
 // entry and exit points for trades, or to confirm other technical analysis signals. It is typically used in conjunction with other indicators
 // to provide a more comprehensive view of the security's price.
 
+// STOCH is a series of the %K values, the %D values are available from GetD.
+//
 //go:generate callbackgen -type STOCH
 type STOCH struct {
+	types.SeriesBase
 	types.IntervalWindow
 	K floats.Slice
 	D floats.Slice
@@ -32,7 +35,13 @@ type STOCH struct {
 	UpdateCallbacks []func(k float64, d float64)
 }
 
+var _ types.SeriesExtend = &STOCH{}
+
 func (inc *STOCH) Update(high, low, cloze float64) {
+	if len(inc.K) == 0 {
+		inc.SeriesBase.Series = inc
+	}
+
 	inc.HighValues.Push(high)
 	inc.LowValues.Push(low)
 
@@ -64,6 +73,18 @@ func (inc *STOCH) LastD() float64 {
 	return inc.D[len(inc.D)-1]
 }
 
+func (inc *STOCH) Last(i int) float64 {
+	return inc.K.Last(i)
+}
+
+func (inc *STOCH) Index(i int) float64 {
+	return inc.Last(i)
+}
+
+func (inc *STOCH) Length() int {
+	return inc.K.Length()
+}
+
 func (inc *STOCH) PushK(k types.KLine) {
 	if inc.EndTime != zeroTime && !k.EndTime.After(inc.EndTime) {
 		return
@@ -74,10 +95,10 @@ func (inc *STOCH) PushK(k types.KLine) {
 	inc.EmitUpdate(inc.LastK(), inc.LastD())
 }
 
-func (inc *STOCH) GetD() types.Series {
-	return &inc.D
+func (inc *STOCH) GetD() types.SeriesExtend {
+	return types.NewSeries(&inc.D)
 }
 
-func (inc *STOCH) GetK() types.Series {
-	return &inc.K
+func (inc *STOCH) GetK() types.SeriesExtend {
+	return types.NewSeries(&inc.K)
 }
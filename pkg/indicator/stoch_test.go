@@ -75,3 +75,26 @@ func TestSTOCH_update(t *testing.T) {
 		})
 	}
 }
+
+func TestSTOCH_SeriesExtend(t *testing.T) {
+	stoch := &STOCH{IntervalWindow: types.IntervalWindow{Window: 3}}
+	for _, v := range [][3]float64{{3, 1, 2}, {4, 2, 3}, {5, 2, 4}, {6, 3, 4}, {6, 4, 5}} {
+		stoch.Update(v[0], v[1], v[2])
+	}
+
+	diff := stoch.Minus(stoch.GetD())
+	if diff.Length() != stoch.Length() {
+		t.Errorf("diff length = %d, expected %d", diff.Length(), stoch.Length())
+	}
+
+	for i := 0; i < stoch.Length(); i++ {
+		expected := stoch.K.Last(i) - stoch.D.Last(i)
+		if math.Abs(diff.Last(i)-expected) > 1e-9 {
+			t.Errorf("diff.Last(%d) = %f, expected %f", i, diff.Last(i), expected)
+		}
+	}
+
+	if stoch.Last(0) != stoch.GetK().Last(0) {
+		t.Errorf("stoch.Last(0) = %f, expected the last %%K %f", stoch.Last(0), stoch.GetK().Last(0))
+	}
+}
@@ -13,6 +13,7 @@ type TMA struct {
 	types.IntervalWindow
 	s1              *SMA
 	s2              *SMA
+	counter         int
 	UpdateCallbacks []func(value float64)
 }
 
@@ -24,10 +25,21 @@ func (inc *TMA) Update(value float64) {
 		inc.s2 = &SMA{IntervalWindow: types.IntervalWindow{Interval: inc.Interval, Window: w}}
 	}
 
+	inc.counter++
 	inc.s1.Update(value)
 	inc.s2.Update(inc.s1.Last(0))
 }
 
+// IsReady returns true when the window of the second SMA is filled with the values of the first SMA,
+// which needs (Window + 1) / 2 * 2 - 1 updates.
+func (inc *TMA) IsReady() bool {
+	if inc.s1 == nil {
+		return false
+	}
+
+	return inc.counter >= inc.s1.Window+inc.s2.Window-1
+}
+
 func (inc *TMA) Last(i int) float64 {
 	return inc.s2.Last(i)
 }
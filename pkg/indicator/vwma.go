@@ -63,6 +63,11 @@ func (inc *VWMA) Update(price, volume float64) {
 	inc.PriceVolumeSMA.Update(price * volume)
 	inc.VolumeSMA.Update(volume)
 
+	// the rolling window is not filled yet
+	if inc.VolumeSMA.Length() == 0 {
+		return
+	}
+
 	pv := inc.PriceVolumeSMA.Last(0)
 	v := inc.VolumeSMA.Last(0)
 	vwma := pv / v
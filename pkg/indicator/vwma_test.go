@@ -0,0 +1,53 @@
+package indicator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func Test_VWMA(t *testing.T) {
+	var Delta = 1e-9
+	var bars = []struct {
+		price, volume float64
+	}{
+		{10, 1},
+		{20, 1},
+		{30, 2},
+		{40, 1},
+		{50, 4},
+	}
+
+	var kLines []types.KLine
+	for _, bar := range bars {
+		kLines = append(kLines, types.KLine{
+			Close:  fixedpoint.NewFromFloat(bar.price),
+			Volume: fixedpoint.NewFromFloat(bar.volume),
+		})
+	}
+
+	vwma := &VWMA{IntervalWindow: types.IntervalWindow{Window: 3}}
+
+	// nothing is pushed before the window is filled
+	vwma.CalculateAndUpdate(kLines[:2])
+	assert.Equal(t, 0, vwma.Length())
+
+	// (10*1 + 20*1 + 30*2) / (1 + 1 + 2)
+	vwma.CalculateAndUpdate(kLines[:3])
+	assert.Equal(t, 1, vwma.Length())
+	assert.InDelta(t, 22.5, vwma.Last(0), Delta)
+
+	// the first bar drops out of the window: (20*1 + 30*2 + 40*1) / (1 + 2 + 1)
+	vwma.CalculateAndUpdate(kLines[:4])
+	assert.InDelta(t, 30.0, vwma.Last(0), Delta)
+
+	// (30*2 + 40*1 + 50*4) / (2 + 1 + 4), unlike VWAP, the older bars are not accumulated
+	vwma.CalculateAndUpdate(kLines[:5])
+	assert.Equal(t, 3, vwma.Length())
+	assert.InDelta(t, 300.0/7.0, vwma.Last(0), Delta)
+	assert.InDelta(t, 30.0, vwma.Index(1), Delta)
+	assert.InDelta(t, 22.5, vwma.Index(2), Delta)
+}
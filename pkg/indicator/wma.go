@@ -0,0 +1,102 @@
+package indicator
+
+import (
+	"time"
+
+	"github.com/c9s/bbgo/pkg/datatype/floats"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+const MaxNumOfWMA = 5_000
+const MaxNumOfWMATruncateSize = 100
+
+// Refer: Weighted Moving Average
+// Refer URL: https://www.investopedia.com/articles/technical/060401.asp
+//
+// The Weighted Moving Average (WMA) assigns linear weights to the values in the window,
+// the most recent value is weighted by the window size, the next one by window - 1, and so on.
+// The weighted sum is then divided by the sum of the weights, which is the triangular number window * (window + 1) / 2.
+//
+//go:generate callbackgen -type WMA
+type WMA struct {
+	types.SeriesBase
+	types.IntervalWindow
+	Values    floats.Slice
+	rawValues *types.Queue
+	EndTime   time.Time
+
+	updateCallbacks []func(value float64)
+}
+
+var _ types.SeriesExtend = &WMA{}
+
+func (inc *WMA) Update(value float64) {
+	if inc.rawValues == nil {
+		inc.rawValues = types.NewQueue(inc.Window)
+		inc.SeriesBase.Series = inc
+	}
+
+	inc.rawValues.Update(value)
+	if inc.rawValues.Length() < inc.Window {
+		return
+	}
+
+	var sum float64
+	for i := 0; i < inc.Window; i++ {
+		sum += inc.rawValues.Last(i) * float64(inc.Window-i)
+	}
+
+	weights := float64(inc.Window*(inc.Window+1)) / 2.0
+	inc.Values.Push(sum / weights)
+	if len(inc.Values) > MaxNumOfWMA {
+		inc.Values = inc.Values[MaxNumOfWMATruncateSize-1:]
+	}
+}
+
+func (inc *WMA) Last(i int) float64 {
+	return inc.Values.Last(i)
+}
+
+func (inc *WMA) Index(i int) float64 {
+	return inc.Last(i)
+}
+
+func (inc *WMA) Length() int {
+	return len(inc.Values)
+}
+
+func (inc *WMA) PushK(k types.KLine) {
+	if inc.EndTime != zeroTime && k.EndTime.Before(inc.EndTime) {
+		return
+	}
+
+	inc.Update(k.Close.Float64())
+	inc.EndTime = k.EndTime.Time()
+	inc.EmitUpdate(inc.Last(0))
+}
+
+func (inc *WMA) CalculateAndUpdate(allKLines []types.KLine) {
+	if len(allKLines) == 0 {
+		return
+	}
+
+	if inc.rawValues == nil {
+		for _, k := range allKLines {
+			inc.PushK(k)
+		}
+	} else {
+		inc.PushK(allKLines[len(allKLines)-1])
+	}
+}
+
+func (inc *WMA) handleKLineWindowUpdate(interval types.Interval, window types.KLineWindow) {
+	if inc.Interval != interval {
+		return
+	}
+
+	inc.CalculateAndUpdate(window)
+}
+
+func (inc *WMA) Bind(updater KLineWindowUpdater) {
+	updater.OnKLineWindowUpdate(inc.handleKLineWindowUpdate)
+}
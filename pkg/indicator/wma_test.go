@@ -0,0 +1,46 @@
+package indicator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func Test_WMA(t *testing.T) {
+	var Delta = 1e-9
+	var input = []fixedpoint.Value{
+		fixedpoint.NewFromInt(1),
+		fixedpoint.NewFromInt(2),
+		fixedpoint.NewFromInt(3),
+		fixedpoint.NewFromInt(4),
+		fixedpoint.NewFromInt(6),
+		fixedpoint.NewFromInt(5),
+	}
+
+	wma := &WMA{IntervalWindow: types.IntervalWindow{Window: 3}}
+
+	var updates []float64
+	wma.OnUpdate(func(value float64) {
+		updates = append(updates, value)
+	})
+
+	for _, k := range buildKLines(input) {
+		wma.PushK(k)
+	}
+
+	// (1*1 + 2*2 + 3*3) / 6 = 14 / 6
+	// (2*1 + 3*2 + 4*3) / 6 = 20 / 6
+	// (3*1 + 4*2 + 6*3) / 6 = 29 / 6
+	// (4*1 + 6*2 + 5*3) / 6 = 31 / 6
+	assert.Equal(t, 4, wma.Length())
+	assert.InDelta(t, 31.0/6.0, wma.Last(0), Delta)
+	assert.InDelta(t, 29.0/6.0, wma.Index(1), Delta)
+	assert.InDelta(t, 20.0/6.0, wma.Index(2), Delta)
+	assert.InDelta(t, 14.0/6.0, wma.Index(3), Delta)
+	if assert.NotEmpty(t, updates) {
+		assert.InDelta(t, 31.0/6.0, updates[len(updates)-1], Delta)
+	}
+}
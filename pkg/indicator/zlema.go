@@ -23,6 +23,9 @@ type ZLEMA struct {
 	zlema *EWMA
 	lag   int
 
+	// SeedWithSMA seeds the nested EWMA with the SMA of its first Window values
+	SeedWithSMA bool
+
 	updateCallbacks []func(value float64)
 }
 
@@ -44,11 +47,34 @@ func (inc *ZLEMA) Length() int {
 	return inc.zlema.Length()
 }
 
+// Lag returns the lag of the input data, which is (window - 1) / 2 rounded half up
+func (inc *ZLEMA) Lag() int {
+	if inc.lag == 0 {
+		return calculateZLEMALag(inc.Window)
+	}
+
+	return inc.lag
+}
+
+func calculateZLEMALag(window int) int {
+	return int((float64(window)-1.)/2. + 0.5)
+}
+
+// IsReady returns true when the nested EWMA has seen Window values, the first lag values are not fed into the EWMA.
+func (inc *ZLEMA) IsReady() bool {
+	return inc.zlema != nil && inc.zlema.IsReady()
+}
+
 func (inc *ZLEMA) Update(value float64) {
-	if inc.lag == 0 || inc.zlema == nil {
+	if inc.zlema == nil {
+		// the lag is zero when the window is less than 2, which degenerates to a plain EWMA
+		if inc.Window < 2 {
+			panic("window must be greater than 1")
+		}
+
 		inc.SeriesBase.Series = inc
-		inc.zlema = &EWMA{IntervalWindow: inc.IntervalWindow}
-		inc.lag = int((float64(inc.Window)-1.)/2. + 0.5)
+		inc.zlema = &EWMA{IntervalWindow: inc.IntervalWindow, SeedWithSMA: inc.SeedWithSMA}
+		inc.lag = calculateZLEMALag(inc.Window)
 	}
 	inc.data.Push(value)
 	if len(inc.data) > MaxNumOfEWMA {
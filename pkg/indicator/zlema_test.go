@@ -53,3 +53,45 @@ func Test_ZLEMA(t *testing.T) {
 		})
 	}
 }
+
+func Test_ZLEMA_Lag(t *testing.T) {
+	tests := []struct {
+		window int
+		lag    int
+	}{
+		{window: 2, lag: 1},
+		{window: 3, lag: 1},
+		{window: 4, lag: 2},
+		{window: 9, lag: 4},
+		{window: 16, lag: 8},
+	}
+
+	for _, tt := range tests {
+		zlema := &ZLEMA{IntervalWindow: types.IntervalWindow{Window: tt.window}}
+		assert.Equal(t, tt.lag, zlema.Lag(), "window %d", tt.window)
+
+		zlema.Update(1.0)
+		assert.Equal(t, tt.lag, zlema.Lag(), "window %d", tt.window)
+	}
+}
+
+func Test_ZLEMA_InvalidWindow(t *testing.T) {
+	for _, window := range []int{0, 1} {
+		zlema := &ZLEMA{IntervalWindow: types.IntervalWindow{Window: window}}
+		assert.PanicsWithValue(t, "window must be greater than 1", func() {
+			zlema.Update(1.0)
+		}, "window %d", window)
+	}
+}
+
+func Test_ZLEMA_IsReady(t *testing.T) {
+	// the lag of window 4 is 2, so the first 2 values are not fed into the nested EWMA
+	zlema := &ZLEMA{IntervalWindow: types.IntervalWindow{Window: 4}}
+	for i := 0; i < 5; i++ {
+		zlema.Update(float64(i))
+		assert.False(t, zlema.IsReady(), "update %d", i)
+	}
+
+	zlema.Update(5)
+	assert.True(t, zlema.IsReady())
+}
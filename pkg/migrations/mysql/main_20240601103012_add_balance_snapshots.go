@@ -0,0 +1,29 @@
+package mysql
+
+import (
+	"context"
+
+	"github.com/c9s/rockhopper/v2"
+)
+
+func init() {
+	AddMigration("main", up_main_addBalanceSnapshots, down_main_addBalanceSnapshots)
+}
+
+func up_main_addBalanceSnapshots(ctx context.Context, tx rockhopper.SQLExecutor) (err error) {
+	// This code is executed when the migration is applied.
+	_, err = tx.ExecContext(ctx, "CREATE TABLE `balance_snapshots`\n(\n    `gid`       BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,\n    `session`   VARCHAR(30)     NOT NULL,\n    `exchange`  VARCHAR(30)     NOT NULL,\n    `currency`  VARCHAR(12)     NOT NULL,\n    `available` DECIMAL(32, 8)  NOT NULL DEFAULT 0.00000000,\n    `locked`    DECIMAL(32, 8)  NOT NULL DEFAULT 0.00000000,\n    -- valuation is the value of the balance in the valuation currency, e.g. USDT\n    `valuation` DECIMAL(32, 8)  NOT NULL DEFAULT 0.00000000,\n    `time`      DATETIME(3)     NOT NULL,\n    PRIMARY KEY (`gid`),\n    INDEX idx_balance_snapshots_exchange_time (`exchange`, `time`)\n);")
+	if err != nil {
+		return err
+	}
+	return err
+}
+
+func down_main_addBalanceSnapshots(ctx context.Context, tx rockhopper.SQLExecutor) (err error) {
+	// This code is executed when the migration is rolled back.
+	_, err = tx.ExecContext(ctx, "DROP TABLE IF EXISTS `balance_snapshots`;")
+	if err != nil {
+		return err
+	}
+	return err
+}
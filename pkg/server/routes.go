@@ -247,6 +247,8 @@ func (s *Server) newEngine(ctx context.Context) *gin.Engine {
 		c.JSON(200, gin.H{"message": "pong"})
 	})
 
+	r.GET("/api/sessions/:session/market/:symbol/indicators", s.getSessionIndicators)
+
 	r.GET("/api/strategies/single", s.listStrategies)
 	r.NoRoute(s.assetsHandler)
 	return r
@@ -387,6 +389,25 @@ func (s *Server) getSessionAccount(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"account": session.GetAccount()})
 }
 
+func (s *Server) getSessionIndicators(c *gin.Context) {
+	sessionName := c.Param("session")
+	symbol := c.Param("symbol")
+	session, ok := s.Environ.Session(sessionName)
+
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("session %s not found", sessionName)})
+		return
+	}
+
+	snapshot, ok := session.IndicatorSnapshot(symbol)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("indicators of symbol %s not found", symbol)})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"indicators": snapshot})
+}
+
 func (s *Server) getSessionAccountBalance(c *gin.Context) {
 	sessionName := c.Param("session")
 	session, ok := s.Environ.Session(sessionName)
@@ -171,6 +171,11 @@ func (s *BacktestService) QueryKLine(
 	return nil, rows.Err()
 }
 
+// QueryLast queries the last stored kline, it implements the KLineService interface
+func (s *BacktestService) QueryLast(ex types.Exchange, symbol string, interval types.Interval) (*types.KLine, error) {
+	return s.QueryKLine(ex, symbol, interval, "DESC", 1)
+}
+
 // QueryKLinesForward is used for querying klines to back-testing
 func (s *BacktestService) QueryKLinesForward(
 	exchange types.Exchange, symbol string, interval types.Interval, startTime time.Time, limit int,
@@ -216,6 +221,29 @@ func (s *BacktestService) QueryKLinesBackward(
 	return s.scanRows(rows)
 }
 
+// QueryKLines queries the stored klines of the symbol and interval with the start time in the range [since, until],
+// the klines are sorted by the start time in ascending order.
+func (s *BacktestService) QueryKLines(
+	exchange types.Exchange, symbol string, interval types.Interval, since, until time.Time,
+) ([]types.KLine, error) {
+	tableName := targetKlineTable(exchange)
+	sql := "SELECT * FROM `binance_klines` WHERE `start_time` BETWEEN :since AND :until AND `exchange` = :exchange AND `symbol` = :symbol AND `interval` = :interval ORDER BY start_time ASC"
+	sql = strings.ReplaceAll(sql, "binance_klines", tableName)
+
+	rows, err := s.DB.NamedQuery(sql, map[string]interface{}{
+		"since":    since,
+		"until":    until,
+		"symbol":   symbol,
+		"interval": interval,
+		"exchange": exchange.Name().String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.scanRows(rows)
+}
+
 func (s *BacktestService) QueryKLinesCh(
 	since, until time.Time, exchange types.Exchange, symbols []string, intervals []types.Interval,
 ) (chan types.KLine, chan error) {
@@ -8,11 +8,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jmoiron/sqlx"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/c9s/bbgo/pkg/exchange"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
 	"github.com/c9s/bbgo/pkg/types"
 )
 
@@ -185,3 +187,57 @@ func TestBacktestService_FindMissingTimeRanges(t *testing.T) {
 		assert.Empty(t, timeRanges, "after partial sync, missing time ranges should be back-filled")
 	}
 }
+
+func TestBacktestService_InsertAndQueryKLines(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer db.Close()
+
+	ex, err := exchange.NewPublic(types.ExchangeBinance)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	service := &BacktestService{DB: sqlx.NewDb(db, "mysql")}
+
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	kline := types.KLine{
+		Exchange:  types.ExchangeBinance,
+		Symbol:    "BTCUSDT",
+		Interval:  types.Interval1h,
+		StartTime: types.Time(startTime),
+		EndTime:   types.Time(startTime.Add(time.Hour - time.Millisecond)),
+		Open:      fixedpoint.NewFromFloat(42000.0),
+		High:      fixedpoint.NewFromFloat(42500.0),
+		Low:       fixedpoint.NewFromFloat(41800.0),
+		Close:     fixedpoint.NewFromFloat(42300.0),
+		Volume:    fixedpoint.NewFromFloat(12.5),
+		Closed:    true,
+	}
+
+	mock.ExpectExec("INSERT INTO `binance_klines` \\(`exchange`, `start_time`, `end_time`, `symbol`, `interval`, .*\\)").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, service.Insert(kline, ex))
+
+	// the kline without the exchange field is rejected
+	assert.ErrorIs(t, service.Insert(types.KLine{}, ex), errExchangeFieldIsUnset)
+
+	mock.ExpectQuery("SELECT \\* FROM `binance_klines` WHERE `start_time` BETWEEN \\? AND \\? AND `exchange` = \\? AND `symbol` = \\? AND `interval` = \\? ORDER BY start_time ASC").
+		WithArgs(startTime, startTime.Add(2*time.Hour), "binance", "BTCUSDT", types.Interval1h).
+		WillReturnRows(sqlmock.NewRows([]string{"exchange", "symbol", "interval", "start_time", "end_time", "open", "high", "low", "close", "volume", "closed"}).
+			AddRow("binance", "BTCUSDT", "1h", kline.StartTime.Time(), kline.EndTime.Time(), 42000.0, 42500.0, 41800.0, 42300.0, 12.5, true))
+
+	kLines, err := service.QueryKLines(ex, "BTCUSDT", types.Interval1h, startTime, startTime.Add(2*time.Hour))
+	if assert.NoError(t, err) && assert.Len(t, kLines, 1) {
+		assert.Equal(t, types.ExchangeBinance, kLines[0].Exchange)
+		assert.Equal(t, types.Interval1h, kLines[0].Interval)
+		assert.Equal(t, startTime, kLines[0].StartTime.Time())
+		assert.Equal(t, "42300", kLines[0].Close.String())
+		assert.Equal(t, "12.5", kLines[0].Volume.String())
+	}
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/multierr"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+// BalanceSnapshot is the balance of a currency in a session at the given time,
+// the valuation is the value of the balance in the valuation currency (e.g. USDT).
+type BalanceSnapshot struct {
+	GID       uint64             `json:"gid,omitempty" db:"gid"`
+	Session   string             `json:"session" db:"session"`
+	Exchange  types.ExchangeName `json:"exchange" db:"exchange"`
+	Currency  string             `json:"currency" db:"currency"`
+	Available fixedpoint.Value   `json:"available" db:"available"`
+	Locked    fixedpoint.Value   `json:"locked" db:"locked"`
+	Valuation fixedpoint.Value   `json:"valuation" db:"valuation"`
+	Time      types.Time         `json:"time" db:"time"`
+}
+
+// BalanceService stores the periodic balance snapshots of the sessions,
+// which can be used to build the equity curve.
+type BalanceService struct {
+	DB *sqlx.DB
+}
+
+func NewBalanceService(db *sqlx.DB) *BalanceService {
+	return &BalanceService{DB: db}
+}
+
+// Insert inserts the given balance snapshots
+func (s *BalanceService) Insert(snapshots ...BalanceSnapshot) error {
+	if s.DB == nil {
+		// skip db insert when no db connection setting.
+		return nil
+	}
+
+	var err error
+	for _, snapshot := range snapshots {
+		_, _err := s.DB.NamedExec(`
+			INSERT INTO balance_snapshots (session, exchange, currency, available, locked, valuation, time)
+			VALUES (:session, :exchange, :currency, :available, :locked, :valuation, :time)`,
+			snapshot)
+
+		err = multierr.Append(err, _err)
+	}
+
+	return err
+}
+
+// Query queries the balance snapshots of the given exchange in the time range [since, until), ordered by time
+func (s *BalanceService) Query(ex types.ExchangeName, since, until time.Time) ([]BalanceSnapshot, error) {
+	sql, args, err := sq.Select("*").
+		From("balance_snapshots").
+		Where(sq.And{
+			sq.Eq{"exchange": ex},
+			sq.GtOrEq{"time": since},
+			sq.Lt{"time": until},
+		}).
+		OrderBy("time ASC", "gid ASC").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.DB.Queryx(sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var snapshots []BalanceSnapshot
+	for rows.Next() {
+		var snapshot BalanceSnapshot
+		if err := rows.StructScan(&snapshot); err != nil {
+			return nil, err
+		}
+
+		snapshots = append(snapshots, snapshot)
+	}
+
+	return snapshots, rows.Err()
+}
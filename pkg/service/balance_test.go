@@ -0,0 +1,73 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestBalanceService_Insert(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer db.Close()
+
+	s := &BalanceService{DB: sqlx.NewDb(db, "mysql")}
+
+	now := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	snapshot := BalanceSnapshot{
+		Session:   "binance",
+		Exchange:  types.ExchangeBinance,
+		Currency:  "BTC",
+		Available: fixedpoint.MustNewFromString("0.5"),
+		Locked:    fixedpoint.MustNewFromString("0.1"),
+		Valuation: fixedpoint.MustNewFromString("12000"),
+		Time:      types.Time(now),
+	}
+
+	mock.ExpectExec(`INSERT INTO balance_snapshots \(session, exchange, currency, available, locked, valuation, time\)`).
+		WithArgs("binance", types.ExchangeBinance, "BTC", snapshot.Available, snapshot.Locked, snapshot.Valuation, snapshot.Time).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, s.Insert(snapshot))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestBalanceService_Query(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer db.Close()
+
+	s := &BalanceService{DB: sqlx.NewDb(db, "mysql")}
+
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT \* FROM balance_snapshots WHERE \(exchange = \? AND time >= \? AND time < \?\) ORDER BY time ASC, gid ASC`).
+		WithArgs(types.ExchangeBinance, since, until).
+		WillReturnRows(sqlmock.NewRows([]string{"gid", "session", "exchange", "currency", "available", "locked", "valuation", "time"}).
+			AddRow(1, "binance", "binance", "BTC", 0.5, 0.1, 12000.0, since.Add(time.Hour)).
+			AddRow(2, "binance", "binance", "USDT", 1000.0, 0.0, 1000.0, since.Add(time.Hour)))
+
+	snapshots, err := s.Query(types.ExchangeBinance, since, until)
+	if assert.NoError(t, err) && assert.Len(t, snapshots, 2) {
+		assert.Equal(t, uint64(1), snapshots[0].GID)
+		assert.Equal(t, types.ExchangeBinance, snapshots[0].Exchange)
+		assert.Equal(t, "BTC", snapshots[0].Currency)
+		assert.Equal(t, "0.5", snapshots[0].Available.String())
+		assert.Equal(t, "12000", snapshots[0].Valuation.String())
+		assert.Equal(t, since.Add(time.Hour), snapshots[0].Time.Time())
+		assert.Equal(t, "USDT", snapshots[1].Currency)
+	}
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
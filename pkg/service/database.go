@@ -90,6 +90,21 @@ func (s *DatabaseService) Upgrade(ctx context.Context) error {
 	return rockhopper.Upgrade(ctx, rh, migrations.FilterPackage(pkgNames))
 }
 
+// NewSQLiteDB opens the sqlite3 database file of the given path and checks the connection
+func NewSQLiteDB(path string) (*sqlx.DB, error) {
+	db, err := sqlx.Open("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
 func ReformatMysqlDSN(dsn string) (string, error) {
 	config, err := mysql.ParseDSN(dsn)
 	if err != nil {
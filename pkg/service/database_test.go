@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestNewSQLiteDB(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := NewSQLiteDB(filepath.Join(t.TempDir(), "bbgo.sqlite3"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	defer db.Close()
+
+	dbService := &DatabaseService{Driver: "sqlite3", DB: db}
+	if err := dbService.Upgrade(ctx); !assert.NoError(t, err) {
+		return
+	}
+
+	tradedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tradeService := &TradeService{DB: db}
+	err = tradeService.Insert(types.Trade{
+		ID:            1,
+		OrderID:       1,
+		Exchange:      types.ExchangeBinance,
+		Price:         fixedpoint.NewFromInt(1000),
+		Quantity:      fixedpoint.NewFromFloat(0.1),
+		QuoteQuantity: fixedpoint.NewFromFloat(100.0),
+		Symbol:        "BTCUSDT",
+		Side:          types.SideTypeBuy,
+		IsBuyer:       true,
+		Time:          types.Time(tradedAt),
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	trades, err := tradeService.Query(QueryTradesOptions{Exchange: types.ExchangeBinance, Symbol: "BTCUSDT"})
+	if assert.NoError(t, err) && assert.Len(t, trades, 1) {
+		assert.Equal(t, uint64(1), trades[0].ID)
+		assert.Equal(t, "1000", trades[0].Price.String())
+		assert.True(t, tradedAt.Equal(trades[0].Time.Time()))
+	}
+
+	orderService := &OrderService{DB: db}
+	order := types.Order{
+		SubmitOrder: types.SubmitOrder{
+			Symbol:      "BTCUSDT",
+			Side:        types.SideTypeBuy,
+			Type:        types.OrderTypeLimit,
+			Price:       fixedpoint.NewFromInt(1000),
+			Quantity:    fixedpoint.NewFromFloat(0.2),
+			TimeInForce: types.TimeInForceGTC,
+		},
+		Exchange:     types.ExchangeBinance,
+		OrderID:      1,
+		Status:       types.OrderStatusNew,
+		IsWorking:    true,
+		CreationTime: types.Time(tradedAt),
+		UpdateTime:   types.Time(tradedAt),
+	}
+	assert.NoError(t, orderService.Insert(order))
+
+	// upsert the same order
+	order.Status = types.OrderStatusFilled
+	order.ExecutedQuantity = fixedpoint.NewFromFloat(0.2)
+	order.IsWorking = false
+	assert.NoError(t, orderService.Insert(order))
+
+	orders, err := orderService.QueryBySymbol(types.ExchangeBinance, "BTCUSDT")
+	if assert.NoError(t, err) && assert.Len(t, orders, 1) {
+		assert.Equal(t, types.OrderStatusFilled, orders[0].Status)
+		assert.Equal(t, "0.2", orders[0].ExecutedQuantity.String())
+		assert.True(t, tradedAt.Equal(orders[0].CreationTime.Time()))
+	}
+}
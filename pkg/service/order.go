@@ -128,6 +128,56 @@ func (s *OrderService) Query(options QueryOrdersOptions) ([]AggOrder, error) {
 	return s.scanAggRows(rows)
 }
 
+// QueryBySymbol queries all the stored orders of the given exchange and symbol, ordered by gid
+func (s *OrderService) QueryBySymbol(ex types.ExchangeName, symbol string) ([]types.Order, error) {
+	sel := sq.Select("*").
+		From("orders").
+		Where(sq.And{
+			sq.Eq{"exchange": ex},
+			sq.Eq{"symbol": symbol},
+		}).
+		OrderBy("gid ASC")
+
+	return s.querySelect(sel)
+}
+
+// QueryClosed queries the closed (filled, canceled or rejected) orders of the given exchange and symbol
+// that were created in the time range [since, until), ordered by gid
+func (s *OrderService) QueryClosed(ex types.ExchangeName, symbol string, since, until time.Time) ([]types.Order, error) {
+	sel := sq.Select("*").
+		From("orders").
+		Where(sq.And{
+			sq.Eq{"exchange": ex},
+			sq.Eq{"symbol": symbol},
+			sq.Eq{"status": []types.OrderStatus{
+				types.OrderStatusFilled,
+				types.OrderStatusCanceled,
+				types.OrderStatusRejected,
+			}},
+			sq.GtOrEq{"created_at": since},
+			sq.Lt{"created_at": until},
+		}).
+		OrderBy("gid ASC")
+
+	return s.querySelect(sel)
+}
+
+func (s *OrderService) querySelect(sel sq.SelectBuilder) ([]types.Order, error) {
+	sql, args, err := sel.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.DB.Queryx(sql, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	return s.scanRows(rows)
+}
+
 func genOrderSQL(options QueryOrdersOptions) string {
 	// ascending
 	ordering := "ASC"
@@ -202,6 +252,14 @@ func (s *OrderService) Insert(order types.Order) (err error) {
 		return err
 	}
 
+	if s.DB.DriverName() == "sqlite3" {
+		_, err = s.DB.NamedExec(`
+			INSERT INTO orders (exchange, order_id, client_order_id, order_type, status, symbol, price, stop_price, quantity, executed_quantity, side, is_working, time_in_force, created_at, updated_at, is_margin, is_futures, is_isolated)
+			VALUES (:exchange, :order_id, :client_order_id, :order_type, :status, :symbol, :price, :stop_price, :quantity, :executed_quantity, :side, :is_working, :time_in_force, :created_at, :updated_at, :is_margin, :is_futures, :is_isolated)
+			ON CONFLICT (order_id, exchange) DO UPDATE SET status=excluded.status, executed_quantity=excluded.executed_quantity, is_working=excluded.is_working, updated_at=excluded.updated_at`, order)
+		return err
+	}
+
 	_, err = s.DB.NamedExec(`
 			INSERT INTO orders (exchange, order_id, client_order_id, order_type, status, symbol, price, stop_price, quantity, executed_quantity, side, is_working, time_in_force, created_at, updated_at, is_margin, is_futures, is_isolated)
 			VALUES (:exchange, :order_id, :client_order_id, :order_type, :status, :symbol, :price, :stop_price, :quantity, :executed_quantity, :side, :is_working, :time_in_force, :created_at, :updated_at, :is_margin, :is_futures, :is_isolated)
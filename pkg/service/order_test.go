@@ -2,8 +2,13 @@ package service
 
 import (
 	"testing"
+	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/types"
 )
 
 func Test_genOrderSQL(t *testing.T) {
@@ -22,3 +27,58 @@ func Test_genOrderSQL(t *testing.T) {
 	})
 
 }
+
+func TestOrderService_QueryBySymbol(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer db.Close()
+
+	s := &OrderService{DB: sqlx.NewDb(db, "mysql")}
+
+	mock.ExpectQuery(`SELECT \* FROM orders WHERE \(exchange = \? AND symbol = \?\) ORDER BY gid ASC`).
+		WithArgs(types.ExchangeBinance, "BTCUSDT").
+		WillReturnRows(sqlmock.NewRows([]string{"gid", "exchange", "symbol", "order_id", "side", "status", "price", "quantity"}).
+			AddRow(1, "binance", "BTCUSDT", 1001, "BUY", "FILLED", 20000.0, 0.1).
+			AddRow(2, "binance", "BTCUSDT", 1002, "SELL", "NEW", 21000.0, 0.2))
+
+	orders, err := s.QueryBySymbol(types.ExchangeBinance, "BTCUSDT")
+	if assert.NoError(t, err) && assert.Len(t, orders, 2) {
+		assert.Equal(t, uint64(1), orders[0].GID)
+		assert.Equal(t, uint64(1001), orders[0].OrderID)
+		assert.Equal(t, types.SideTypeBuy, orders[0].Side)
+		assert.Equal(t, types.OrderStatusFilled, orders[0].Status)
+		assert.Equal(t, "20000", orders[0].Price.String())
+		assert.Equal(t, uint64(1002), orders[1].OrderID)
+		assert.Equal(t, "0.2", orders[1].Quantity.String())
+	}
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrderService_QueryClosed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer db.Close()
+
+	s := &OrderService{DB: sqlx.NewDb(db, "mysql")}
+
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`SELECT \* FROM orders WHERE \(exchange = \? AND symbol = \? AND status IN \(\?,\?,\?\) AND created_at >= \? AND created_at < \?\) ORDER BY gid ASC`).
+		WithArgs(types.ExchangeBinance, "BTCUSDT", types.OrderStatusFilled, types.OrderStatusCanceled, types.OrderStatusRejected, since, until).
+		WillReturnRows(sqlmock.NewRows([]string{"gid", "exchange", "symbol", "order_id", "status"}).
+			AddRow(3, "binance", "BTCUSDT", 1003, "CANCELED"))
+
+	orders, err := s.QueryClosed(types.ExchangeBinance, "BTCUSDT", since, until)
+	if assert.NoError(t, err) && assert.Len(t, orders, 1) {
+		assert.Equal(t, uint64(3), orders[0].GID)
+		assert.Equal(t, types.OrderStatusCanceled, orders[0].Status)
+	}
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -9,12 +9,20 @@ import (
 
 	log "github.com/sirupsen/logrus"
 
+	"github.com/c9s/bbgo/pkg/exchange/batch"
 	"github.com/c9s/bbgo/pkg/types"
 )
 
 var ErrNotImplemented = errors.New("not implemented")
 var ErrExchangeRewardServiceNotImplemented = errors.New("exchange does not implement ExchangeRewardService interface")
 
+// KLineService stores the synchronized klines, it's implemented by BacktestService
+type KLineService interface {
+	// QueryLast returns the last stored kline, nil is returned if there is no kline stored
+	QueryLast(ex types.Exchange, symbol string, interval types.Interval) (*types.KLine, error)
+	BatchInsert(klines []types.KLine, ex types.Exchange) error
+}
+
 type SyncService struct {
 	TradeService    *TradeService
 	OrderService    *OrderService
@@ -22,6 +30,7 @@ type SyncService struct {
 	WithdrawService *WithdrawService
 	DepositService  *DepositService
 	MarginService   *MarginService
+	KLineService    KLineService
 }
 
 // SyncSessionSymbols syncs the trades from the given exchange session
@@ -118,3 +127,60 @@ func (s *SyncService) SyncWithdrawHistory(ctx context.Context, exchange types.Ex
 
 	return nil
 }
+
+// SyncKLines syncs the closed klines of the given symbol and interval,
+// the sync resumes from the last stored kline if it's later than the given start time.
+func (s *SyncService) SyncKLines(
+	ctx context.Context, exchange types.Exchange, symbol string, interval types.Interval, startTime time.Time,
+) error {
+	if s.KLineService == nil {
+		return ErrNotImplemented
+	}
+
+	lastKLine, err := s.KLineService.QueryLast(exchange, symbol, interval)
+	if err != nil {
+		return err
+	}
+
+	if lastKLine != nil {
+		// resume from the next kline of the last stored kline
+		if nextStartTime := lastKLine.StartTime.Time().Add(interval.Duration()); nextStartTime.After(startTime) {
+			startTime = nextStartTime
+		}
+	}
+
+	endTime := time.Now()
+
+	log.Infof("syncing %s %s %s klines from %s...", exchange.Name(), symbol, interval, startTime)
+
+	const batchInsertBufferSize = 1000
+	buffer := make([]types.KLine, 0, batchInsertBufferSize)
+
+	q := &batch.KLineBatchQuery{Exchange: exchange}
+	kLineC, errC := q.Query(ctx, symbol, interval, startTime, endTime)
+	for kline := range kLineC {
+		// skip the klines which are not closed yet
+		if kline.EndTime.After(endTime) {
+			continue
+		}
+
+		buffer = append(buffer, kline)
+		if len(buffer) >= batchInsertBufferSize {
+			if err := s.KLineService.BatchInsert(buffer, exchange); err != nil {
+				return err
+			}
+
+			buffer = buffer[:0]
+		}
+	}
+
+	if err := <-errC; err != nil {
+		return err
+	}
+
+	if len(buffer) > 0 {
+		return s.KLineService.BatchInsert(buffer, exchange)
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/types/mocks"
+)
+
+type testKLineService struct {
+	last     *types.KLine
+	inserted []types.KLine
+}
+
+func (s *testKLineService) QueryLast(ex types.Exchange, symbol string, interval types.Interval) (*types.KLine, error) {
+	return s.last, nil
+}
+
+func (s *testKLineService) BatchInsert(klines []types.KLine, ex types.Exchange) error {
+	s.inserted = append(s.inserted, klines...)
+	return nil
+}
+
+func TestSyncService_SyncKLines(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	startTime := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	var remoteKLines []types.KLine
+	for i := 0; i < 7; i++ {
+		kt := startTime.Add(time.Duration(i) * time.Hour)
+		remoteKLines = append(remoteKLines, types.KLine{
+			Exchange:  types.ExchangeBinance,
+			Symbol:    "BTCUSDT",
+			Interval:  types.Interval1h,
+			StartTime: types.Time(kt),
+			EndTime:   types.Time(kt.Add(time.Hour - time.Millisecond)),
+			Close:     fixedpoint.NewFromInt(int64(20000 + i)),
+			Closed:    true,
+		})
+	}
+
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().Name().Return(types.ExchangeBinance).AnyTimes()
+
+	// the fake exchange returns at most 2 klines per page
+	mockEx.EXPECT().QueryKLines(gomock.Any(), "BTCUSDT", types.Interval1h, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, symbol string, interval types.Interval, options types.KLineQueryOptions) ([]types.KLine, error) {
+			var klines []types.KLine
+			for _, k := range remoteKLines {
+				if k.StartTime.Time().Before(*options.StartTime) {
+					continue
+				}
+
+				klines = append(klines, k)
+				if len(klines) == 2 {
+					break
+				}
+			}
+			return klines, nil
+		}).AnyTimes()
+
+	// the first 2 klines are stored already
+	klineService := &testKLineService{last: &remoteKLines[1]}
+	syncService := &SyncService{KLineService: klineService}
+
+	err := syncService.SyncKLines(context.Background(), mockEx, "BTCUSDT", types.Interval1h, startTime)
+	if assert.NoError(t, err) {
+		assert.Equal(t, remoteKLines[2:], klineService.inserted)
+	}
+}
@@ -101,6 +101,17 @@ func (s *TradeService) Sync(ctx context.Context, exchange types.Exchange, symbol
 				trade := obj.(types.Trade)
 				return strconv.FormatUint(trade.ID, 10) + trade.Side.String()
 			},
+			Insert: func(obj interface{}) error {
+				trade := obj.(types.Trade)
+
+				// some exchange implementations do not fill the exchange field,
+				// always use the name of the exchange that we are syncing from
+				if len(trade.Exchange) == 0 {
+					trade.Exchange = exchange.Name()
+				}
+
+				return s.Insert(trade)
+			},
 			LogInsert: true,
 		},
 	}
@@ -1,14 +1,17 @@
 package service
 
 import (
+	"context"
 	"testing"
 	"time"
 
+	"github.com/golang/mock/gomock"
 	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/c9s/bbgo/pkg/fixedpoint"
 	"github.com/c9s/bbgo/pkg/types"
+	"github.com/c9s/bbgo/pkg/types/mocks"
 )
 
 func Test_tradeService(t *testing.T) {
@@ -37,6 +40,131 @@ func Test_tradeService(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+type mockTradeHistoryExchange struct {
+	*mocks.MockExchange
+	*mocks.MockExchangeTradeHistoryService
+}
+
+func Test_tradeService_Sync(t *testing.T) {
+	db, err := prepareDB(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Close()
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	now := time.Now()
+	trades := []types.Trade{
+		{
+			ID:            1,
+			OrderID:       1,
+			Price:         fixedpoint.NewFromInt(1000),
+			Quantity:      fixedpoint.NewFromFloat(0.1),
+			QuoteQuantity: fixedpoint.NewFromFloat(1000.0 * 0.1),
+			Symbol:        "BTCUSDT",
+			Side:          types.SideTypeBuy,
+			IsBuyer:       true,
+			Time:          types.Time(now.Add(-time.Hour)),
+		},
+	}
+
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().Name().Return(types.ExchangeBinance).AnyTimes()
+
+	mockHistory := mocks.NewMockExchangeTradeHistoryService(mockCtrl)
+	mockHistory.EXPECT().QueryTrades(gomock.Any(), "BTCUSDT", gomock.Any()).Return(trades, nil).AnyTimes()
+
+	xdb := sqlx.NewDb(db.DB, "sqlite3")
+	service := &TradeService{DB: xdb}
+
+	err = service.Sync(context.Background(), &mockTradeHistoryExchange{mockEx, mockHistory}, "BTCUSDT", now.Add(-2*time.Hour))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	stored, err := service.Query(QueryTradesOptions{Symbol: "BTCUSDT"})
+	if assert.NoError(t, err) && assert.Len(t, stored, 1) {
+		assert.Equal(t, types.ExchangeBinance, stored[0].Exchange)
+		assert.Equal(t, uint64(1), stored[0].ID)
+	}
+}
+
+func Test_tradeService_Sync_Resume(t *testing.T) {
+	db, err := prepareDB(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Close()
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	xdb := sqlx.NewDb(db.DB, "sqlite3")
+	service := &TradeService{DB: xdb}
+
+	now := time.Now()
+	newTrade := func(id uint64, tradedAt time.Time) types.Trade {
+		return types.Trade{
+			ID:            id,
+			OrderID:       id,
+			Exchange:      types.ExchangeBinance,
+			Price:         fixedpoint.NewFromInt(1000),
+			Quantity:      fixedpoint.NewFromFloat(0.1),
+			QuoteQuantity: fixedpoint.NewFromFloat(1000.0 * 0.1),
+			Symbol:        "BTCUSDT",
+			Side:          types.SideTypeBuy,
+			IsBuyer:       true,
+			Time:          types.Time(tradedAt),
+		}
+	}
+
+	// the stored last trade
+	err = service.Insert(newTrade(5, now.Add(-3*time.Hour)))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	pages := [][]types.Trade{
+		{newTrade(6, now.Add(-2*time.Hour)), newTrade(7, now.Add(-90*time.Minute))},
+		{newTrade(8, now.Add(-time.Hour))},
+	}
+
+	var lastTradeIDs []uint64
+	mockEx := mocks.NewMockExchange(mockCtrl)
+	mockEx.EXPECT().Name().Return(types.ExchangeBinance).AnyTimes()
+
+	mockHistory := mocks.NewMockExchangeTradeHistoryService(mockCtrl)
+	mockHistory.EXPECT().QueryTrades(gomock.Any(), "BTCUSDT", gomock.Any()).
+		DoAndReturn(func(ctx context.Context, symbol string, options *types.TradeQueryOptions) ([]types.Trade, error) {
+			page := len(lastTradeIDs)
+			lastTradeIDs = append(lastTradeIDs, options.LastTradeID)
+			if page < len(pages) {
+				return pages[page], nil
+			}
+			return nil, nil
+		}).AnyTimes()
+
+	err = service.Sync(context.Background(), &mockTradeHistoryExchange{mockEx, mockHistory}, "BTCUSDT", now.Add(-24*time.Hour))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	if assert.GreaterOrEqual(t, len(lastTradeIDs), 2) {
+		assert.Equal(t, uint64(5), lastTradeIDs[0], "should resume from the stored last trade id")
+		assert.Equal(t, uint64(7), lastTradeIDs[1], "should continue from the last trade id of the previous page")
+	}
+
+	stored, err := service.Query(QueryTradesOptions{Symbol: "BTCUSDT"})
+	if assert.NoError(t, err) && assert.Len(t, stored, 4) {
+		assert.Equal(t, uint64(5), stored[0].ID)
+		assert.Equal(t, uint64(8), stored[3].ID)
+	}
+}
+
 func Test_queryTradingVolumeSQL(t *testing.T) {
 	t.Run("group by different period", func(t *testing.T) {
 		o := TradingVolumeQueryOptions{
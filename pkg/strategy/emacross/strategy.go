@@ -20,6 +20,13 @@ func init() {
 	bbgo.RegisterStrategy(ID, &Strategy{})
 }
 
+//go:generate mockgen -destination=mocks/order_executor.go -package=mocks . OrderExecutor
+type OrderExecutor interface {
+	GracefulCancel(ctx context.Context, orders ...types.Order) error
+	OpenPosition(ctx context.Context, options bbgo.OpenPositionOptions) (types.OrderSlice, error)
+	ClosePosition(ctx context.Context, percentage fixedpoint.Value, tags ...string) error
+}
+
 type Strategy struct {
 	*common.Strategy
 
@@ -35,7 +42,10 @@ type Strategy struct {
 
 	lastKLine types.KLine
 
+	// OpenPositionOptions provides the quantity (or the leverage) of the long position
 	bbgo.OpenPositionOptions
+
+	orderExecutor OrderExecutor
 }
 
 func (s *Strategy) ID() string {
@@ -60,7 +70,9 @@ func (s *Strategy) Subscribe(session *bbgo.ExchangeSession) {
 func (s *Strategy) Run(ctx context.Context, _ bbgo.OrderExecutor, session *bbgo.ExchangeSession) error {
 	s.Strategy.Initialize(ctx, s.Environment, session, s.Market, ID, s.InstanceID())
 
-	session.MarketDataStream.OnKLineClosed(types.KLineWith(s.Symbol, types.Interval5m, func(k types.KLine) {
+	s.orderExecutor = s.Strategy.OrderExecutor
+
+	session.MarketDataStream.OnKLineClosed(types.KLineWith(s.Symbol, s.Interval, func(k types.KLine) {
 		s.lastKLine = k
 	}))
 
@@ -69,27 +81,12 @@ func (s *Strategy) Run(ctx context.Context, _ bbgo.OrderExecutor, session *bbgo.
 
 	cross := indicatorv2.Cross(fastEMA, slowEMA)
 	cross.OnUpdate(func(v float64) {
-		switch indicatorv2.CrossType(v) {
-
-		case indicatorv2.CrossOver:
-			if err := s.Strategy.OrderExecutor.GracefulCancel(ctx); err != nil {
-				log.WithError(err).Errorf("unable to cancel order")
-			}
-
-			opts := s.OpenPositionOptions
-			opts.Long = true
-			if price, ok := session.LastPrice(s.Symbol); ok {
-				opts.Price = price
-			}
-
-			opts.Tags = []string{"emaCrossOver"}
-
-			_, err := s.Strategy.OrderExecutor.OpenPosition(ctx, opts)
-			logErr(err, "unable to open position")
-		case indicatorv2.CrossUnder:
-			err := s.Strategy.OrderExecutor.ClosePosition(ctx, fixedpoint.One)
-			logErr(err, "unable to submit close position order")
+		price, ok := session.LastPrice(s.Symbol)
+		if !ok {
+			price = s.lastKLine.Close
 		}
+
+		s.handleCross(ctx, indicatorv2.CrossType(v), price)
 	})
 
 	bbgo.OnShutdown(ctx, func(ctx context.Context, wg *sync.WaitGroup) {
@@ -99,6 +96,30 @@ func (s *Strategy) Run(ctx context.Context, _ bbgo.OrderExecutor, session *bbgo.
 	return nil
 }
 
+// handleCross opens a long position on the golden cross (the fast EMA crosses over the slow EMA),
+// and closes the position on the death cross.
+func (s *Strategy) handleCross(ctx context.Context, crossType indicatorv2.CrossType, price fixedpoint.Value) {
+	switch crossType {
+
+	case indicatorv2.CrossOver:
+		if err := s.orderExecutor.GracefulCancel(ctx); err != nil {
+			log.WithError(err).Errorf("unable to cancel order")
+		}
+
+		opts := s.OpenPositionOptions
+		opts.Long = true
+		opts.Price = price
+		opts.Tags = []string{"emaCrossOver"}
+
+		_, err := s.orderExecutor.OpenPosition(ctx, opts)
+		logErr(err, "unable to open position")
+
+	case indicatorv2.CrossUnder:
+		err := s.orderExecutor.ClosePosition(ctx, fixedpoint.One)
+		logErr(err, "unable to submit close position order")
+	}
+}
+
 func logErr(err error, msgAndArgs ...interface{}) bool {
 	if err == nil {
 		return false
@@ -0,0 +1,88 @@
+package emacross
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/bbgo"
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+	"github.com/c9s/bbgo/pkg/indicator/v2"
+	"github.com/c9s/bbgo/pkg/strategy/emacross/mocks"
+	"github.com/c9s/bbgo/pkg/types"
+)
+
+func TestStrategy_handleCross(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	var sides []types.SideType
+	orderExecutor := mocks.NewMockOrderExecutor(mockCtrl)
+	orderExecutor.EXPECT().GracefulCancel(gomock.Any()).Return(nil).AnyTimes()
+	orderExecutor.EXPECT().OpenPosition(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, options bbgo.OpenPositionOptions) (types.OrderSlice, error) {
+			assert.True(t, options.Long)
+			assert.Equal(t, "0.01", options.Quantity.String())
+			assert.Equal(t, []string{"emaCrossOver"}, options.Tags)
+			sides = append(sides, types.SideTypeBuy)
+			return nil, nil
+		}).AnyTimes()
+	orderExecutor.EXPECT().ClosePosition(gomock.Any(), fixedpoint.One).DoAndReturn(
+		func(ctx context.Context, percentage fixedpoint.Value, tags ...string) error {
+			sides = append(sides, types.SideTypeSell)
+			return nil
+		}).AnyTimes()
+
+	s := &Strategy{
+		Symbol:              "BTCUSDT",
+		Interval:            types.Interval1h,
+		FastWindow:          3,
+		SlowWindow:          7,
+		OpenPositionOptions: bbgo.OpenPositionOptions{Quantity: fixedpoint.MustNewFromString("0.01")},
+		orderExecutor:       orderExecutor,
+	}
+
+	ctx := context.Background()
+	stream := &types.StandardStream{}
+	closePrices := indicatorv2.ClosePrices(indicatorv2.KLines(stream, s.Symbol, s.Interval))
+	fastEMA := indicatorv2.EWMA2(closePrices, s.FastWindow)
+	slowEMA := indicatorv2.EWMA2(closePrices, s.SlowWindow)
+	cross := indicatorv2.Cross(fastEMA, slowEMA)
+	cross.OnUpdate(func(v float64) {
+		s.handleCross(ctx, indicatorv2.CrossType(v), s.lastKLine.Close)
+	})
+	stream.OnKLineClosed(types.KLineWith(s.Symbol, s.Interval, func(k types.KLine) {
+		s.lastKLine = k
+	}))
+
+	// the price falls, rises (golden cross), falls (death cross) and rises again (golden cross)
+	var prices []float64
+	for i := 0; i < 10; i++ {
+		prices = append(prices, 20000.0-float64(i)*100.0)
+	}
+	for i := 0; i < 10; i++ {
+		prices = append(prices, 19100.0+float64(i)*200.0)
+	}
+	for i := 0; i < 10; i++ {
+		prices = append(prices, 20900.0-float64(i)*200.0)
+	}
+	for i := 0; i < 10; i++ {
+		prices = append(prices, 19100.0+float64(i)*200.0)
+	}
+
+	startTime := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	for i, price := range prices {
+		stream.EmitKLineClosed(types.KLine{
+			Symbol:    s.Symbol,
+			Interval:  s.Interval,
+			StartTime: types.Time(startTime.Add(time.Duration(i) * time.Hour)),
+			Close:     fixedpoint.NewFromFloat(price),
+			Closed:    true,
+		})
+	}
+
+	assert.Equal(t, []types.SideType{types.SideTypeBuy, types.SideTypeSell, types.SideTypeBuy}, sides)
+}
@@ -236,6 +236,31 @@ func (a *Account) UpdateBalances(balances BalanceMap) {
 	}
 }
 
+// TotalValueInQuote returns the total net value of the balances in the quote currency.
+// The prices are keyed by the market symbol, e.g. BTCUSDT, and each balance is converted by
+// the direct market (BTCUSDT), the inverse market (USDTTWD) or one hop through another currency (ETHBTC -> BTCUSDT).
+// The balances without a price to convert are skipped with a warning.
+func (a *Account) TotalValueInQuote(quote string, prices PriceMap) fixedpoint.Value {
+	total := fixedpoint.Zero
+	for currency, balance := range a.Balances() {
+		net := balance.Net()
+		if net.IsZero() {
+			continue
+		}
+
+		price, ok := prices.ConvertPrice(currency, quote)
+		if !ok {
+			logrus.Warnf("price of %s in %s not found, skip the %s balance %s in the total value",
+				currency, quote, currency, net.String())
+			continue
+		}
+
+		total = total.Add(net.Mul(price))
+	}
+
+	return total
+}
+
 func (a *Account) Print() {
 	a.Lock()
 	defer a.Unlock()
@@ -60,3 +60,28 @@ func TestAccountLockAndUse(t *testing.T) {
 	assert.Equal(t, balance.Available, fixedpoint.NewFromInt(900))
 	assert.Equal(t, balance.Locked, fixedpoint.Zero)
 }
+
+func TestAccount_TotalValueInQuote(t *testing.T) {
+	a := NewAccount()
+	a.UpdateBalances(BalanceMap{
+		"USDT": {Currency: "USDT", Available: fixedpoint.NewFromInt(1000)},
+		"BTC":  {Currency: "BTC", Available: fixedpoint.NewFromFloat(0.4), Locked: fixedpoint.NewFromFloat(0.1)},
+		"ETH":  {Currency: "ETH", Available: fixedpoint.NewFromInt(2)},
+		"TWD":  {Currency: "TWD", Available: fixedpoint.NewFromInt(3000)},
+		"DOGE": {Currency: "DOGE", Available: fixedpoint.NewFromInt(100)},
+	})
+
+	prices := PriceMap{
+		"BTCUSDT": fixedpoint.NewFromInt(30000),
+		"ETHBTC":  fixedpoint.NewFromFloat(0.05),
+		"USDTTWD": fixedpoint.NewFromInt(30),
+	}
+
+	// 1000 USDT + 0.5 BTC * 30000 + 2 ETH * 0.05 * 30000 + 3000 TWD / 30, DOGE is skipped without the price
+	total := a.TotalValueInQuote("USDT", prices)
+	assert.InDelta(t, 19100.0, total.Float64(), 1e-4)
+
+	// 1000 / 30000 + 0.5 + 2 * 0.05 + 3000 / 30 / 30000
+	total = a.TotalValueInQuote("BTC", prices)
+	assert.InDelta(t, 0.63666667, total.Float64(), 1e-4)
+}
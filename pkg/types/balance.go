@@ -2,6 +2,7 @@ package types
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -14,6 +15,67 @@ import (
 
 type PriceMap map[string]fixedpoint.Value
 
+// directPrice returns the price of the base currency in the quote currency from the market or the inverse market
+func (m PriceMap) directPrice(base, quote string) (fixedpoint.Value, bool) {
+	if base == quote {
+		return fixedpoint.One, true
+	}
+
+	if price, ok := m[base+quote]; ok && price.Sign() > 0 {
+		return price, true
+	}
+
+	if price, ok := m[quote+base]; ok && price.Sign() > 0 {
+		return fixedpoint.One.Div(price), true
+	}
+
+	return fixedpoint.Zero, false
+}
+
+// ConvertPrice returns the price of the currency in the quote currency,
+// it tries the direct markets first, and then one hop through the other currency of the markets of the currency.
+func (m PriceMap) ConvertPrice(currency, quote string) (fixedpoint.Value, bool) {
+	if price, ok := m.directPrice(currency, quote); ok {
+		return price, true
+	}
+
+	symbols := make([]string, 0, len(m))
+	for symbol := range m {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	for _, symbol := range symbols {
+		var bridge string
+		switch {
+		case strings.HasPrefix(symbol, currency):
+			bridge = strings.TrimPrefix(symbol, currency)
+		case strings.HasSuffix(symbol, currency):
+			bridge = strings.TrimSuffix(symbol, currency)
+		default:
+			continue
+		}
+
+		if bridge == "" || bridge == quote {
+			continue
+		}
+
+		price1, ok := m.directPrice(currency, bridge)
+		if !ok {
+			continue
+		}
+
+		price2, ok := m.directPrice(bridge, quote)
+		if !ok {
+			continue
+		}
+
+		return price1.Mul(price2), true
+	}
+
+	return fixedpoint.Zero, false
+}
+
 type Balance struct {
 	Currency  string           `json:"currency"`
 	Available fixedpoint.Value `json:"available"`
@@ -199,9 +261,9 @@ func (m BalanceMap) Assets(prices PriceMap, priceTime time.Time) AssetMap {
 			NetAsset:  netAsset,
 		}
 
-		if IsUSDFiatCurrency(currency) { // for usd
-			asset.InUSD = netAsset
-			asset.PriceInUSD = fixedpoint.One
+		if priceInUSD, ok := StableCoinPriceInUSD(currency, prices); ok { // for usd stable coins
+			asset.InUSD = netAsset.Mul(priceInUSD)
+			asset.PriceInUSD = priceInUSD
 			if hasBtcPrice && !asset.InUSD.IsZero() {
 				asset.InBTC = asset.InUSD.Div(btcInUSD)
 			}
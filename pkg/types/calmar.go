@@ -0,0 +1,45 @@
+package types
+
+import (
+	"math"
+)
+
+// Calmar: Calculates the calmar ratio of access returns
+//
+//	annualized return
+//
+// calmar = -------------------
+//
+//	max drawdown
+//
+// @param returns (Series): Series of profit/loss percentage every specific interval
+// @param periods (int): Freq. of returns (252/365 for daily, 12 for monthy, 1 for annually)
+func Calmar(returns Series, periods int) float64 {
+	num := returns.Length()
+	if num == 0 {
+		return 0
+	}
+
+	equity := 1.
+	for i := num - 1; i >= 0; i-- {
+		equity *= 1. + returns.Last(i)
+	}
+
+	annualizedReturn := equity - 1.
+	if periods > 0 && equity > 0 {
+		annualizedReturn = math.Pow(equity, float64(periods)/float64(num)) - 1.
+	}
+
+	maxDD, _, _ := MaxDrawdown(returns)
+	if maxDD == 0 {
+		if annualizedReturn > 0 {
+			return math.Inf(1)
+		} else if annualizedReturn < 0 {
+			return math.Inf(-1)
+		} else {
+			return 0
+		}
+	}
+
+	return annualizedReturn / maxDD
+}
@@ -0,0 +1,26 @@
+package types
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/datatype/floats"
+)
+
+func TestCalmar(t *testing.T) {
+	// equity curve: 1.0, 1.1, 0.55, 0.66, 0.726
+	// max drawdown = 0.5 (1.1 -> 0.55)
+	var a Series = &floats.Slice{0.1, -0.5, 0.2, 0.1}
+	output := Calmar(a, 4)
+	assert.InDelta(t, -0.274/0.5, output, 0.0001)
+
+	// annualize with 8 periods: 0.726^2 - 1
+	output = Calmar(a, 8)
+	assert.InDelta(t, (0.726*0.726-1.)/0.5, output, 0.0001)
+
+	var b Series = &floats.Slice{0.1, 0.2}
+	output = Calmar(b, 2)
+	assert.True(t, math.IsInf(output, 1))
+}
@@ -0,0 +1,39 @@
+package types
+
+// MaxDrawdown calculates the maximum drawdown of the equity curve built from the return series
+//
+// The equity curve starts with 1.0 at index 0, and the value at index i is the
+// compounded equity after the i-th return (in chronological order).
+//
+// @param returns (Series): Series of profit/loss percentage every specific interval
+// @return maxDD (float64): the largest peak-to-trough decline in ratio, e.g. 0.2 means 20%
+// @return peakIdx (int): the index of the peak on the equity curve
+// @return troughIdx (int): the index of the trough on the equity curve
+func MaxDrawdown(returns Series) (maxDD float64, peakIdx, troughIdx int) {
+	num := returns.Length()
+
+	equity := 1.
+	peak := equity
+	currentPeakIdx := 0
+	for i := 1; i <= num; i++ {
+		equity *= 1. + returns.Last(num-i)
+		if equity > peak {
+			peak = equity
+			currentPeakIdx = i
+			continue
+		}
+
+		if peak <= 0 {
+			continue
+		}
+
+		dd := (peak - equity) / peak
+		if dd > maxDD {
+			maxDD = dd
+			peakIdx = currentPeakIdx
+			troughIdx = i
+		}
+	}
+
+	return maxDD, peakIdx, troughIdx
+}
@@ -0,0 +1,30 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/datatype/floats"
+)
+
+func TestMaxDrawdown(t *testing.T) {
+	// equity curve: 1.0, 1.1, 1.21, 1.089, 0.9801, 1.27413
+	var a Series = &floats.Slice{0.1, 0.1, -0.1, -0.1, 0.3}
+	maxDD, peakIdx, troughIdx := MaxDrawdown(a)
+	assert.InDelta(t, 0.19, maxDD, 0.0001)
+	assert.Equal(t, 2, peakIdx)
+	assert.Equal(t, 4, troughIdx)
+
+	// loss from the initial equity
+	// equity curve: 1.0, 0.8, 0.88
+	var b Series = &floats.Slice{-0.2, 0.1}
+	maxDD, peakIdx, troughIdx = MaxDrawdown(b)
+	assert.InDelta(t, 0.2, maxDD, 0.0001)
+	assert.Equal(t, 0, peakIdx)
+	assert.Equal(t, 1, troughIdx)
+
+	var c Series = &floats.Slice{0.1, 0.2}
+	maxDD, _, _ = MaxDrawdown(c)
+	assert.Equal(t, 0.0, maxDD)
+}
@@ -31,3 +31,26 @@ func NewZeroAssetError(e error) ZeroAssetError {
 		error: e,
 	}
 }
+
+// SubmitOrderError is returned when the order submission fails, and the order may or may not be placed,
+// e.g., the request times out. It carries the client order id sent in the request,
+// so that the retry can reuse it and the exchange rejects the duplicated order.
+type SubmitOrderError struct {
+	error
+	clientOrderID string
+}
+
+func (e *SubmitOrderError) ClientOrderID() string {
+	return e.clientOrderID
+}
+
+func (e *SubmitOrderError) Unwrap() error {
+	return e.error
+}
+
+func NewSubmitOrderError(e error, clientOrderID string) error {
+	return &SubmitOrderError{
+		error:         e,
+		clientOrderID: clientOrderID,
+	}
+}
@@ -112,6 +112,17 @@ type ExchangeTradeService interface {
 	CancelOrders(ctx context.Context, orders ...Order) error
 }
 
+// ExchangePinger checks the connectivity and the api credentials of the exchange
+type ExchangePinger interface {
+	Ping(ctx context.Context) error
+}
+
+// ExchangeServerTimeSyncer synchronizes the timestamp of the signed requests with the exchange server time,
+// so that the signed requests are not rejected when the local clock is skewed
+type ExchangeServerTimeSyncer interface {
+	SyncServerTime(ctx context.Context) error
+}
+
 type ExchangeDefaultFeeRates interface {
 	DefaultFeeRates() ExchangeFee
 }
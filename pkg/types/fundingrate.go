@@ -10,4 +10,8 @@ type FundingRate struct {
 	FundingRate fixedpoint.Value
 	FundingTime time.Time
 	Time        time.Time
+
+	// RealizedRate is the actual funding rate settled at the funding time,
+	// it's only available from the exchanges that provide it, e.g. okex
+	RealizedRate fixedpoint.Value
 }
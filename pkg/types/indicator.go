@@ -425,6 +425,17 @@ func Reverse(a Series, limit ...int) (result floats.Slice) {
 	return
 }
 
+// Window returns a snapshot copy of the most recent n values of the series in the oldest-first order,
+// n is clamped to the length of the series.
+// The returned slice implements the Series interface, so it can be passed to the functions like Sharpe directly.
+func Window(a Series, n int) floats.Slice {
+	if n < 0 {
+		n = 0
+	}
+
+	return Reverse(a, n)
+}
+
 type ChangeResult struct {
 	a      Series
 	offset int
@@ -609,6 +620,36 @@ func Correlation(a Series, b Series, length int, method ...CorrFunc) float64 {
 	return runner(a, b, length)
 }
 
+// EWCorrelation computes the exponentially weighted Pearson correlation of the two series,
+// similar to pandas.Series.ewm(halflife=halfLife).corr().
+//
+// The weight of an observation decays by half every halfLife bars, so the recent observations
+// dominate the result, which makes it responsive to the regime shift of the relationship.
+func EWCorrelation(a, b Series, halfLife int) float64 {
+	if halfLife <= 0 {
+		return math.NaN()
+	}
+
+	length := a.Length()
+	if b.Length() < length {
+		length = b.Length()
+	}
+
+	decay := math.Pow(0.5, 1.0/float64(halfLife))
+	x := make([]float64, length)
+	y := make([]float64, length)
+	weights := make([]float64, length)
+	w := 1.0
+	for i := 0; i < length; i++ {
+		x[i] = a.Last(i)
+		y[i] = b.Last(i)
+		weights[i] = w
+		w *= decay
+	}
+
+	return stat.Correlation(x, y, weights)
+}
+
 // similar to pandas.Series.autocorr() function.
 //
 // The method computes the Pearson correlation between Series and shifted itself
@@ -73,6 +73,32 @@ func TestCorr(t *testing.T) {
 	assert.InDelta(t, corr, -0.94868, 0.001)
 }
 
+func TestEWCorrelation(t *testing.T) {
+	var a = floats.Slice{.2, .0, .6, .2}
+	var b = floats.Slice{.3, .6, .0, .1}
+
+	// with a very long half-life, the weights are almost equal
+	assert.InDelta(t, Correlation(&a, &b, 4), EWCorrelation(&a, &b, 100000), 0.001)
+	assert.True(t, math.IsNaN(EWCorrelation(&a, &b, 0)))
+
+	// the two series move together for 200 bars, and then move against each other for 20 bars
+	var x, y floats.Slice
+	for i := 0; i < 220; i++ {
+		v := math.Sin(float64(i) * 0.7)
+		x = append(x, v)
+		if i < 200 {
+			y = append(y, v)
+		} else {
+			y = append(y, -v)
+		}
+	}
+
+	rolling := Correlation(&x, &y, 220)
+	ew := EWCorrelation(&x, &y, 5)
+	assert.Greater(t, rolling, 0.5)
+	assert.Less(t, ew, -0.5)
+}
+
 /*
 python
 
@@ -250,3 +276,25 @@ func TestFilter(t *testing.T) {
 	assert.Equal(t, b.Last(0), 1000.)
 	assert.Equal(t, b.Sum(3), 1200.)
 }
+
+func TestWindow(t *testing.T) {
+	q := NewQueue(10)
+	for _, v := range []float64{1, 2, 3, 4, 5} {
+		q.Update(v)
+	}
+
+	w := Window(q, 3)
+	assert.Equal(t, floats.Slice{3, 4, 5}, w, "should be oldest-first")
+
+	// the snapshot should not be affected by the later updates
+	q.Update(6)
+	assert.Equal(t, floats.Slice{3, 4, 5}, w)
+
+	assert.Equal(t, floats.Slice{1, 2, 3, 4, 5, 6}, Window(q, 100), "should be clamped to the series length")
+	assert.Empty(t, Window(q, 0))
+	assert.Empty(t, Window(q, -1))
+
+	returns := Window(q, 4)
+	expected := floats.Slice{3, 4, 5, 6}
+	assert.InDelta(t, Sharpe(&expected, 4, false, false), Sharpe(&returns, 4, false, false), 1e-9)
+}
@@ -94,6 +94,39 @@ func (k *KLine) Set(o *KLine) {
 	k.Closed = o.Closed
 }
 
+// Validate checks if the high and low prices are consistent with the open and close prices
+func (k *KLine) Validate() error {
+	if k.High.Compare(fixedpoint.Max(k.Open, k.Close)) < 0 {
+		return fmt.Errorf("invalid kline %s %s %s: high %s is lower than max(open %s, close %s)",
+			k.Symbol, k.Interval, k.StartTime, k.High, k.Open, k.Close)
+	}
+
+	if k.Low.Compare(fixedpoint.Min(k.Open, k.Close)) > 0 {
+		return fmt.Errorf("invalid kline %s %s %s: low %s is higher than min(open %s, close %s)",
+			k.Symbol, k.Interval, k.StartTime, k.Low, k.Open, k.Close)
+	}
+
+	return nil
+}
+
+// Repair clamps the high and low prices to be consistent with the open and close prices,
+// it returns true if the kline is modified.
+func (k *KLine) Repair() bool {
+	repaired := false
+
+	if maxPrice := fixedpoint.Max(k.Open, k.Close); k.High.Compare(maxPrice) < 0 {
+		k.High = maxPrice
+		repaired = true
+	}
+
+	if minPrice := fixedpoint.Min(k.Open, k.Close); k.Low.Compare(minPrice) > 0 {
+		k.Low = minPrice
+		repaired = true
+	}
+
+	return repaired
+}
+
 func (k *KLine) Merge(o *KLine) {
 	k.EndTime = o.EndTime
 	k.Close = o.Close
@@ -450,6 +483,36 @@ func (k *KLineWindow) Truncate(size int) {
 	*k = kn
 }
 
+// Aggregate folds the lower interval klines into the klines of the given higher interval.
+// The aggregated klines are aligned to the interval boundaries, the open price is taken from the first kline,
+// the close price is taken from the last kline, and the volumes are summed up.
+// The klines must be sorted in ascending order.
+func (k KLineWindow) Aggregate(interval Interval) KLineWindow {
+	duration := interval.Duration()
+	var window KLineWindow
+	for _, kline := range k {
+		startTime := kline.StartTime.Time().Truncate(duration)
+		endTime := startTime.Add(duration - time.Millisecond)
+
+		last := len(window) - 1
+		if last < 0 || !window[last].StartTime.Time().Equal(startTime) {
+			aggregated := kline
+			aggregated.Interval = interval
+			aggregated.StartTime = Time(startTime)
+			window = append(window, aggregated)
+			last++
+		} else {
+			window[last].Merge(&kline)
+		}
+
+		// the aggregated kline is closed only when the kline of the boundary end is closed
+		window[last].Closed = kline.Closed && !kline.EndTime.Time().Before(endTime)
+		window[last].EndTime = Time(endTime)
+	}
+
+	return window
+}
+
 func (k KLineWindow) GetBody() fixedpoint.Value {
 	return k.GetChange()
 }
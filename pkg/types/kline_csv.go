@@ -0,0 +1,98 @@
+package types
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+var klineCsvHeader = []string{"time", "open", "high", "low", "close", "volume"}
+
+// WriteKLinesCSV writes the klines as the OHLCV csv rows with a header,
+// the time column is the kline start time in RFC3339 format.
+func WriteKLinesCSV(w io.Writer, klines []KLine) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(klineCsvHeader); err != nil {
+		return err
+	}
+
+	for _, k := range klines {
+		if err := writer.Write([]string{
+			k.StartTime.Time().UTC().Format(time.RFC3339),
+			k.Open.String(),
+			k.High.String(),
+			k.Low.String(),
+			k.Close.String(),
+			k.Volume.String(),
+		}); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// ReadKLinesCSV reads the OHLCV csv rows written by WriteKLinesCSV,
+// the symbol and the interval are not in the csv so they are given by the caller.
+func ReadKLinesCSV(r io.Reader, symbol string, interval Interval) ([]KLine, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = len(klineCsvHeader)
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	// skip the header
+	if records[0][0] == klineCsvHeader[0] {
+		records = records[1:]
+	}
+
+	var klines []KLine
+	for i, record := range records {
+		k, err := parseKLineCsvRecord(record, symbol, interval)
+		if err != nil {
+			return nil, fmt.Errorf("kline csv record #%d error: %w", i+1, err)
+		}
+
+		klines = append(klines, k)
+	}
+
+	return klines, nil
+}
+
+func parseKLineCsvRecord(record []string, symbol string, interval Interval) (KLine, error) {
+	startTime, err := time.Parse(time.RFC3339, record[0])
+	if err != nil {
+		return KLine{}, err
+	}
+
+	var values [5]fixedpoint.Value
+	for i := range values {
+		values[i], err = fixedpoint.NewFromString(record[i+1])
+		if err != nil {
+			return KLine{}, fmt.Errorf("invalid %s value %q: %w", klineCsvHeader[i+1], record[i+1], err)
+		}
+	}
+
+	return KLine{
+		Symbol:    symbol,
+		Interval:  interval,
+		StartTime: Time(startTime),
+		EndTime:   Time(startTime.Add(interval.Duration() - time.Millisecond)),
+		Open:      values[0],
+		High:      values[1],
+		Low:       values[2],
+		Close:     values[3],
+		Volume:    values[4],
+		Closed:    true,
+	}, nil
+}
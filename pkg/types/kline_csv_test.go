@@ -0,0 +1,65 @@
+package types
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+func TestKLinesCSV(t *testing.T) {
+	startTime := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	var klines []KLine
+	for i := 0; i < 3; i++ {
+		kt := startTime.Add(time.Duration(i) * time.Hour)
+		klines = append(klines, KLine{
+			Symbol:    "BTCUSDT",
+			Interval:  Interval1h,
+			StartTime: Time(kt),
+			EndTime:   Time(kt.Add(time.Hour - time.Millisecond)),
+			Open:      fixedpoint.MustNewFromString("42000.1"),
+			High:      fixedpoint.MustNewFromString("42500.25"),
+			Low:       fixedpoint.MustNewFromString("41800"),
+			Close:     fixedpoint.NewFromInt(int64(42100 + i)),
+			Volume:    fixedpoint.MustNewFromString("12.345"),
+			Closed:    true,
+		})
+	}
+
+	var buf bytes.Buffer
+	if !assert.NoError(t, WriteKLinesCSV(&buf, klines)) {
+		return
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if assert.Len(t, lines, 4) {
+		assert.Equal(t, "time,open,high,low,close,volume", lines[0])
+		assert.Equal(t, "2024-01-02T00:00:00Z,42000.1,42500.25,41800,42100,12.345", lines[1])
+	}
+
+	readKLines, err := ReadKLinesCSV(&buf, "BTCUSDT", Interval1h)
+	if assert.NoError(t, err) && assert.Len(t, readKLines, len(klines)) {
+		for i, k := range readKLines {
+			assert.Equal(t, klines[i].Symbol, k.Symbol)
+			assert.Equal(t, klines[i].Interval, k.Interval)
+			assert.Equal(t, klines[i].StartTime.Time(), k.StartTime.Time())
+			assert.Equal(t, klines[i].EndTime.Time(), k.EndTime.Time())
+			assert.Equal(t, klines[i].Open, k.Open)
+			assert.Equal(t, klines[i].High, k.High)
+			assert.Equal(t, klines[i].Low, k.Low)
+			assert.Equal(t, klines[i].Close, k.Close)
+			assert.Equal(t, klines[i].Volume, k.Volume)
+		}
+	}
+
+	_, err = ReadKLinesCSV(strings.NewReader("time,open,high,low,close,volume\n2024-01-02 00:00,1,1,1,1,1\n"), "BTCUSDT", Interval1h)
+	assert.Error(t, err)
+
+	_, err = ReadKLinesCSV(strings.NewReader("time,open,high,low,close,volume\n2024-01-02T00:00:00Z,1,1,1,1\n"), "BTCUSDT", Interval1h)
+	assert.Error(t, err)
+}
@@ -2,8 +2,12 @@ package types
 
 import (
 	"encoding/json"
-	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
 )
 
 func TestKLineWindow_Tail(t *testing.T) {
@@ -56,3 +60,72 @@ func TestKLineWindow_Truncate(t *testing.T) {
 	assert.Len(t, win, 1)
 	assert.Equal(t, 11603.0, win.Last().Open.Float64())
 }
+
+func TestKLineWindow_Aggregate(t *testing.T) {
+	startTime := time.Date(2024, 1, 1, 9, 55, 0, 0, time.UTC)
+
+	// one 5m kline before the boundary and twelve 5m klines of the 10:00 hour
+	var win KLineWindow
+	for i := 0; i < 13; i++ {
+		open := fixedpoint.NewFromInt(int64(100 + i))
+		win.Add(KLine{
+			Symbol:    "BTCUSDT",
+			Interval:  Interval5m,
+			StartTime: Time(startTime.Add(time.Duration(i) * 5 * time.Minute)),
+			EndTime:   Time(startTime.Add(time.Duration(i+1)*5*time.Minute - time.Millisecond)),
+			Open:      open,
+			Close:     open.Add(fixedpoint.NewFromFloat(0.5)),
+			High:      open.Add(fixedpoint.One),
+			Low:       open.Sub(fixedpoint.One),
+			Volume:    fixedpoint.One,
+			Closed:    true,
+		})
+	}
+
+	aggregated := win.Aggregate(Interval1h)
+	if !assert.Len(t, aggregated, 2) {
+		return
+	}
+
+	first := aggregated[0]
+	assert.Equal(t, Interval1h, first.Interval)
+	assert.Equal(t, time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC), first.StartTime.Time())
+	assert.Equal(t, "1", first.Volume.String())
+
+	k := aggregated[1]
+	assert.Equal(t, Interval1h, k.Interval)
+	assert.Equal(t, time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), k.StartTime.Time())
+	assert.Equal(t, time.Date(2024, 1, 1, 10, 59, 59, int(999*time.Millisecond), time.UTC), k.EndTime.Time())
+	assert.Equal(t, "101", k.Open.String())
+	assert.Equal(t, "112.5", k.Close.String())
+	assert.Equal(t, "113", k.High.String())
+	assert.Equal(t, "100", k.Low.String())
+	assert.Equal(t, "12", k.Volume.String())
+	assert.True(t, k.Closed)
+
+	// the aggregated kline is not closed if the klines of the boundary end are missing
+	partial := win[:12].Aggregate(Interval1h)
+	if assert.Len(t, partial, 2) {
+		assert.False(t, partial[1].Closed)
+		assert.Equal(t, "11", partial[1].Volume.String())
+	}
+}
+
+func TestKLine_Repair(t *testing.T) {
+	k := KLine{
+		Symbol: "BTCUSDT",
+		Open:   fixedpoint.NewFromFloat(100.0),
+		Close:  fixedpoint.NewFromFloat(105.0),
+		High:   fixedpoint.NewFromFloat(103.0),
+		Low:    fixedpoint.NewFromFloat(101.0),
+	}
+	assert.Error(t, k.Validate())
+
+	assert.True(t, k.Repair())
+	assert.NoError(t, k.Validate())
+	assert.Equal(t, "105", k.High.String())
+	assert.Equal(t, "100", k.Low.String())
+
+	// a consistent kline is not modified
+	assert.False(t, k.Repair())
+}
@@ -52,23 +52,56 @@ type Market struct {
 
 	MinPrice fixedpoint.Value `json:"minPrice,omitempty"`
 	MaxPrice fixedpoint.Value `json:"maxPrice,omitempty"`
+
+	// State is the exchange-specific trading state of the market, e.g. live, suspend
+	// this is only filled by the exchanges that provide the market state
+	State string `json:"state,omitempty"`
 }
 
 func (m Market) IsDustQuantity(quantity, price fixedpoint.Value) bool {
 	return quantity.Compare(m.MinQuantity) <= 0 || quantity.Mul(price).Compare(m.MinNotional) <= 0
 }
 
-// TruncateQuantity uses the step size to truncate floating number, in order to avoid the rounding issue
+// TruncateQuantity truncates the quantity toward zero to the nearest multiple of the step size,
+// so that the quantity is accepted by the lot size filter of the exchange.
 func (m Market) TruncateQuantity(quantity fixedpoint.Value) fixedpoint.Value {
-	var ts = m.StepSize.Float64()
-	var prec = int(math.Round(math.Log10(ts) * -1.0))
-	var pow10 = math.Pow10(prec)
+	if m.StepSize.Sign() <= 0 {
+		return quantity
+	}
 
-	qf := math.Trunc(quantity.Float64() * pow10)
-	qf = qf / pow10
+	if quantity.Sign() < 0 {
+		return m.TruncateQuantity(quantity.Neg()).Neg()
+	}
 
-	qs := strconv.FormatFloat(qf, 'f', prec, 64)
-	return fixedpoint.MustNewFromString(qs)
+	digits := m.StepSize.NumFractionalDigits()
+
+	// a power of ten step size (e.g. 0.001) truncates the fractional digits directly,
+	// which is exact and does not overflow like Div does for a large quantity with a tiny step size
+	if digits >= 0 && math.Abs(m.StepSize.Float64()*math.Pow10(digits)-1.0) < 1e-9 {
+		return quantity.Round(digits, fixedpoint.Down)
+	}
+
+	var truncated fixedpoint.Value
+	if steps := quantity.Div(m.StepSize).Round(0, fixedpoint.Down); !steps.IsInf() {
+		truncated = steps.Mul(m.StepSize).Round(digits, fixedpoint.HalfUp)
+	} else {
+		// the number of steps overflows the fixedpoint range, estimate the truncated quantity with float64
+		stepSize := m.StepSize.Float64()
+		steps := math.Floor(quantity.Float64() / stepSize)
+		truncated = fixedpoint.NewFromFloat(steps*stepSize).Round(digits, fixedpoint.HalfUp)
+	}
+
+	// the legacy fixedpoint multiplies and divides with float64, correct the off-by-one-step errors,
+	// the estimation is within a few steps of the result so the loops are bounded
+	for truncated.Compare(quantity) > 0 {
+		truncated = truncated.Sub(m.StepSize)
+	}
+
+	for truncated.Add(m.StepSize).Compare(quantity) <= 0 {
+		truncated = truncated.Add(m.StepSize)
+	}
+
+	return truncated
 }
 
 // TruncateQuoteQuantity uses the tick size to truncate floating number, in order to avoid the rounding issue
@@ -132,6 +165,26 @@ func (m Market) GreaterThanMinimalOrderQuantity(
 	return available, true
 }
 
+// QuantityFromNotional returns the base quantity that the given notional (quote amount) buys at the price,
+// the quantity is truncated to the step size, and zero is returned if it's less than the min quantity
+// or the truncated notional is less than the min notional.
+func (m Market) QuantityFromNotional(notional, price fixedpoint.Value) fixedpoint.Value {
+	if notional.Sign() <= 0 || price.Sign() <= 0 {
+		return fixedpoint.Zero
+	}
+
+	quantity := m.TruncateQuantity(notional.Div(price))
+	if quantity.Sign() <= 0 || quantity.Compare(m.MinQuantity) < 0 {
+		return fixedpoint.Zero
+	}
+
+	if quantity.Mul(price).Compare(m.MinNotional) < 0 {
+		return fixedpoint.Zero
+	}
+
+	return quantity
+}
+
 // RoundDownQuantityByPrecision uses the volume precision to round down the quantity
 // This is different from the TruncateQuantity, which uses StepSize (it uses fewer fractions to truncate)
 func (m Market) RoundDownQuantityByPrecision(quantity fixedpoint.Value) fixedpoint.Value {
@@ -198,12 +251,22 @@ func FormatPrice(price fixedpoint.Value, tickSize fixedpoint.Value) string {
 	return price.FormatString(prec)
 }
 
+// FormatQuantity truncates the quantity to the step size and formats it with the step size precision
 func (m Market) FormatQuantity(val fixedpoint.Value) string {
-	return formatQuantity(val, m.StepSize)
+	return formatQuantity(m.TruncateQuantity(val), m.StepSize)
 }
 
 func formatQuantity(quantity fixedpoint.Value, lot fixedpoint.Value) string {
-	prec := int(math.Round(math.Abs(math.Log10(lot.Float64()))))
+	if lot.Sign() <= 0 {
+		return quantity.String()
+	}
+
+	// the step size of 10 or more has negative fractional digits, format it as an integer
+	prec := lot.NumFractionalDigits()
+	if prec < 0 {
+		prec = 0
+	}
+
 	return quantity.FormatString(prec)
 }
 
@@ -237,6 +237,98 @@ func TestMarket_TruncateQuantity(t *testing.T) {
 		assert.Equalf(t, testCase.expect, q2.String(), "input: %s stepSize: %s", testCase.input, market.StepSize.String())
 	}
 
+	stepTestCases := []struct {
+		stepSize string
+		input    string
+		expect   string
+	}{
+		{"0.00000001", "0.123456789", "0.12345678"},
+		{"0.01", "0.29", "0.29"},
+		{"0.01", "0.299", "0.29"},
+		{"0.25", "1.7", "1.5"},
+		{"0.5", "0.4", "0"},
+		{"5", "23", "20"},
+	}
+
+	for _, testCase := range stepTestCases {
+		market := Market{StepSize: fixedpoint.MustNewFromString(testCase.stepSize)}
+		q := market.TruncateQuantity(fixedpoint.MustNewFromString(testCase.input))
+		assert.Equalf(t, testCase.expect, q.String(), "input: %s stepSize: %s", testCase.input, testCase.stepSize)
+	}
+
+	// no step size
+	assert.Equal(t, "0.12345", Market{}.TruncateQuantity(fixedpoint.MustNewFromString("0.12345")).String())
+
+	// negative quantities are truncated toward zero
+	market = Market{StepSize: fixedpoint.MustNewFromString("0.25")}
+	assert.Equal(t, "-1.5", market.TruncateQuantity(fixedpoint.MustNewFromString("-1.7")).String())
+
+}
+
+func TestMarket_TruncateQuantity_LargeQuantity(t *testing.T) {
+	// a large quantity with a tiny step size is beyond the float64 precision of Div and Mul,
+	// the correction loops must step back to the exact multiple within a few steps
+	testCases := []struct {
+		stepSize string
+		input    string
+		expect   string
+	}{
+		{"0.00000001", "87654321.12345678", "87654321.12345678"},
+		{"0.00000001", "12345678.87654321", "12345678.87654321"},
+		{"0.000001", "98765432.12345678", "98765432.123456"},
+		{"0.00000002", "12345678.87654321", "12345678.8765432"},
+	}
+
+	for _, testCase := range testCases {
+		market := Market{StepSize: fixedpoint.MustNewFromString(testCase.stepSize)}
+		input := fixedpoint.MustNewFromString(testCase.input)
+
+		done := make(chan fixedpoint.Value, 1)
+		go func() {
+			done <- market.TruncateQuantity(input)
+		}()
+
+		select {
+		case q := <-done:
+			assert.Equalf(t, testCase.expect, q.String(), "input: %s stepSize: %s", testCase.input, testCase.stepSize)
+		case <-time.After(time.Second):
+			t.Fatalf("TruncateQuantity is not bounded, input: %s stepSize: %s", testCase.input, testCase.stepSize)
+		}
+	}
+}
+
+func TestMarket_FormatQuantity(t *testing.T) {
+	market := Market{StepSize: fixedpoint.MustNewFromString("0.00000001")}
+	assert.Equal(t, "0.12345678", market.FormatQuantity(fixedpoint.MustNewFromString("0.123456789")))
+
+	market = Market{StepSize: fixedpoint.MustNewFromString("0.5")}
+	assert.Equal(t, "1.5", market.FormatQuantity(fixedpoint.MustNewFromString("1.99")))
+
+	market = Market{StepSize: fixedpoint.MustNewFromString("10")}
+	assert.Equal(t, "120", market.FormatQuantity(fixedpoint.MustNewFromString("123.45")))
+}
+
+func TestMarket_QuantityFromNotional(t *testing.T) {
+	market := Market{
+		Symbol:      "BTCUSDT",
+		StepSize:    fixedpoint.MustNewFromString("0.0001"),
+		MinQuantity: fixedpoint.MustNewFromString("0.0001"),
+		MinNotional: fixedpoint.MustNewFromString("10"),
+	}
+
+	price := fixedpoint.MustNewFromString("27345.67")
+
+	// 100 / 27345.67 = 0.003656886...
+	assert.Equal(t, "0.0036", market.QuantityFromNotional(fixedpoint.MustNewFromString("100"), price).String())
+
+	// 10 / 27345.67 = 0.000365688... is truncated to 0.0003, which is worth less than the min notional
+	assert.True(t, market.QuantityFromNotional(fixedpoint.MustNewFromString("10"), price).IsZero())
+
+	// less than the min quantity
+	market.MinNotional = fixedpoint.Zero
+	assert.True(t, market.QuantityFromNotional(fixedpoint.MustNewFromString("2"), price).IsZero())
+
+	assert.True(t, market.QuantityFromNotional(fixedpoint.MustNewFromString("100"), fixedpoint.Zero).IsZero())
 }
 
 func TestMarket_AdjustQuantityByMinNotional(t *testing.T) {
@@ -0,0 +1,14 @@
+package types
+
+import (
+	"time"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+// MarkPrice is the mark price of the futures or the perpetual contract pushed by the exchange
+type MarkPrice struct {
+	Symbol    string           `json:"symbol"`
+	MarkPrice fixedpoint.Value `json:"markPrice"`
+	Time      time.Time        `json:"time"`
+}
@@ -114,6 +114,9 @@ const (
 
 	// OrderStatusRejected means the order is not placed successfully, it's rejected by the api
 	OrderStatusRejected OrderStatus = "REJECTED"
+
+	// OrderStatusSimulated means the order is simulated by the dry run mode, it's not sent to the exchange
+	OrderStatusSimulated OrderStatus = "SIMULATED"
 )
 
 func (o OrderStatus) Closed() bool {
@@ -358,6 +361,11 @@ func (o Order) String() string {
 	return desc
 }
 
+// NotificationKey returns the key for coalescing the order notifications of the same symbol
+func (o Order) NotificationKey() string {
+	return "order " + o.Symbol
+}
+
 // PlainText is used for telegram-styled messages
 func (o Order) PlainText() string {
 	return fmt.Sprintf("Order %s %s %s %s @ %s %s/%s -> %s",
@@ -19,6 +19,40 @@ const (
 	PositionClosed = PositionType("Closed")
 )
 
+// CostMethod is the accounting method used for computing the realized profit of a position
+type CostMethod string
+
+const (
+	// CostMethodAverageCost uses the weighted average cost of the position, this is the default method
+	CostMethodAverageCost CostMethod = "average"
+
+	// CostMethodFIFO matches the closing trades against the opened lots in first-in-first-out order
+	CostMethodFIFO CostMethod = "fifo"
+)
+
+// PositionLot is an opened lot of the position, used by the FIFO cost method
+type PositionLot struct {
+	Quantity fixedpoint.Value `json:"quantity"`
+	Price    fixedpoint.Value `json:"price"`
+
+	// ApproximatePrice adds the computed fee in quote in the lot price
+	ApproximatePrice fixedpoint.Value `json:"approximatePrice"`
+}
+
+// PositionDelta is the position change caused by a trade
+type PositionDelta struct {
+	Trade Trade `json:"trade"`
+
+	BeforeBase        fixedpoint.Value `json:"beforeBase"`
+	BeforeAverageCost fixedpoint.Value `json:"beforeAverageCost"`
+
+	AfterBase        fixedpoint.Value `json:"afterBase"`
+	AfterAverageCost fixedpoint.Value `json:"afterAverageCost"`
+
+	Profit    fixedpoint.Value `json:"profit"`
+	NetProfit fixedpoint.Value `json:"netProfit"`
+}
+
 type ExchangeFee struct {
 	MakerFeeRate fixedpoint.Value
 	TakerFeeRate fixedpoint.Value
@@ -27,6 +61,7 @@ type ExchangeFee struct {
 type PositionRisk struct {
 	Leverage         fixedpoint.Value `json:"leverage"`
 	LiquidationPrice fixedpoint.Value `json:"liquidationPrice"`
+	UnrealizedProfit fixedpoint.Value `json:"unrealizedProfit,omitempty"`
 }
 
 type Position struct {
@@ -58,6 +93,12 @@ type Position struct {
 
 	AccumulatedProfit fixedpoint.Value `json:"accumulatedProfit,omitempty" db:"accumulated_profit"`
 
+	// CostMethod is the method used for computing the realized profit, defaults to the average cost method
+	CostMethod CostMethod `json:"costMethod,omitempty" db:"-"`
+
+	// Lots stores the opened lots when the FIFO cost method is used
+	Lots []PositionLot `json:"lots,omitempty" db:"-"`
+
 	// closing is a flag for marking this position is closing
 	closing bool
 
@@ -66,6 +107,14 @@ type Position struct {
 	// Modify position callbacks
 	modifyCallbacks []func(baseQty fixedpoint.Value, quoteQty fixedpoint.Value, price fixedpoint.Value)
 
+	updateCallbacks []func(delta PositionDelta)
+
+	// history is the ring-buffered audit log of the position deltas, it's disabled unless SetHistorySize is called,
+	// historyHead is the index of the oldest delta once the ring is full
+	history     []PositionDelta
+	historyHead int
+	historySize int
+
 	// ttl is the ttl to keep in persistence
 	ttl time.Duration
 }
@@ -197,6 +246,30 @@ func (p *Position) IsDust(a ...fixedpoint.Value) bool {
 	return p.Market.IsDustQuantity(base, price)
 }
 
+// AverageCostRounded returns the average cost rounded to the tick size of the given market,
+// so that the prices derived from the average cost (e.g., the stop price) won't be rejected by the exchange.
+//
+// The rounding direction is conservative for the stop placement, the stop derived from the rounded cost
+// is never tighter than the stop derived from the raw cost:
+// the average cost of a long position is rounded down, and the average cost of a short position is rounded up.
+func (p *Position) AverageCostRounded(market Market) fixedpoint.Value {
+	p.Lock()
+	averageCost := p.AverageCost
+	base := p.Base
+	p.Unlock()
+
+	if market.TickSize.Sign() <= 0 {
+		return averageCost
+	}
+
+	mode := fixedpoint.Down
+	if base.Sign() < 0 {
+		mode = fixedpoint.Up
+	}
+
+	return averageCost.Div(market.TickSize).Round(0, mode).Mul(market.TickSize)
+}
+
 // GetBase locks the mutex and return the base quantity
 // The base quantity can be negative
 func (p *Position) GetBase() (base fixedpoint.Value) {
@@ -206,6 +279,16 @@ func (p *Position) GetBase() (base fixedpoint.Value) {
 	return base
 }
 
+// GetBaseAndAverageCost returns the base and the average cost read in the same critical section,
+// so that the average cost matches the base when the trades are added concurrently.
+func (p *Position) GetBaseAndAverageCost() (base, averageCost fixedpoint.Value) {
+	p.Lock()
+	base = p.Base
+	averageCost = p.AverageCost
+	p.Unlock()
+	return base, averageCost
+}
+
 // GetQuantity calls GetBase() and then convert the number into a positive number
 // that could be treated as a quantity.
 func (p *Position) GetQuantity() fixedpoint.Value {
@@ -225,6 +308,27 @@ func (p *Position) UnrealizedProfit(price fixedpoint.Value) fixedpoint.Value {
 	return fixedpoint.Zero
 }
 
+// BreakEvenPrice returns the exit price that covers both the entry fee and the estimated exit fee.
+// For a long position the break-even price is above the average cost,
+// for a short position the break-even price is below the average cost.
+func (p *Position) BreakEvenPrice(feeRate fixedpoint.Value) fixedpoint.Value {
+	base := p.GetBase()
+	sign := base.Sign()
+	if sign == 0 {
+		return p.AverageCost
+	}
+
+	upper := fixedpoint.One.Add(feeRate)
+	lower := fixedpoint.One.Sub(feeRate)
+	if sign > 0 {
+		// exitPrice * (1 - feeRate) = averageCost * (1 + feeRate)
+		return p.AverageCost.Mul(upper).Div(lower)
+	}
+
+	// exitPrice * (1 + feeRate) = averageCost * (1 - feeRate)
+	return p.AverageCost.Mul(lower).Div(upper)
+}
+
 func (p *Position) OnModify(cb func(baseQty fixedpoint.Value, quoteQty fixedpoint.Value, price fixedpoint.Value)) {
 	p.modifyCallbacks = append(p.modifyCallbacks, cb)
 }
@@ -235,6 +339,72 @@ func (p *Position) EmitModify(baseQty fixedpoint.Value, quoteQty fixedpoint.Valu
 	}
 }
 
+// OnUpdate registers a callback that will be called with the position delta after each trade is added
+func (p *Position) OnUpdate(cb func(delta PositionDelta)) {
+	p.updateCallbacks = append(p.updateCallbacks, cb)
+}
+
+func (p *Position) EmitUpdate(delta PositionDelta) {
+	for _, cb := range p.updateCallbacks {
+		cb(delta)
+	}
+}
+
+// SetHistorySize enables the audit log with the given capacity, the oldest deltas are dropped when it's full.
+// The audit log is disabled by default, a size <= 0 disables it and drops the recorded deltas.
+func (p *Position) SetHistorySize(size int) {
+	p.Lock()
+	defer p.Unlock()
+
+	history := p.orderedHistory()
+	p.historySize = size
+	p.historyHead = 0
+
+	if size <= 0 {
+		p.history = nil
+		return
+	}
+
+	if len(history) > size {
+		history = history[len(history)-size:]
+	}
+
+	p.history = make([]PositionDelta, len(history), size)
+	copy(p.history, history)
+}
+
+// History returns a copy of the audit log, ordered from the oldest to the latest delta
+func (p *Position) History() []PositionDelta {
+	p.Lock()
+	defer p.Unlock()
+
+	return p.orderedHistory()
+}
+
+// orderedHistory copies the ring buffer from the oldest to the latest delta, the caller must hold the lock.
+func (p *Position) orderedHistory() []PositionDelta {
+	history := make([]PositionDelta, 0, len(p.history))
+	history = append(history, p.history[p.historyHead:]...)
+	history = append(history, p.history[:p.historyHead]...)
+	return history
+}
+
+// appendHistory writes the delta into the ring buffer, the caller must hold the lock.
+func (p *Position) appendHistory(delta PositionDelta) {
+	if p.historySize <= 0 {
+		return
+	}
+
+	if len(p.history) < p.historySize {
+		p.history = append(p.history, delta)
+		return
+	}
+
+	// the ring is full, overwrite the oldest delta
+	p.history[p.historyHead] = delta
+	p.historyHead = (p.historyHead + 1) % p.historySize
+}
+
 // ModifyBase modifies position base quantity with `qty`
 func (p *Position) ModifyBase(qty fixedpoint.Value) error {
 	p.Base = qty
@@ -321,6 +491,11 @@ func (p *Position) Reset() {
 	p.Quote = fixedpoint.Zero
 	p.AverageCost = fixedpoint.Zero
 	p.TotalFee = make(map[string]fixedpoint.Value)
+	p.Lots = nil
+}
+
+func (p *Position) SetCostMethod(method CostMethod) {
+	p.CostMethod = method
 }
 
 func (p *Position) SetFeeRate(exchangeFee ExchangeFee) {
@@ -360,6 +535,11 @@ func (p *Position) Type() PositionType {
 	return PositionClosed
 }
 
+// NotificationKey returns the key for coalescing the position notifications of the same symbol
+func (p *Position) NotificationKey() string {
+	return "position " + p.Symbol
+}
+
 func (p *Position) SlackAttachment() slack.Attachment {
 	p.Lock()
 	defer p.Unlock()
@@ -478,7 +658,32 @@ func (p *Position) AddTrades(trades []Trade) (fixedpoint.Value, fixedpoint.Value
 	return totalProfitAmount, totalNetProfit, !totalProfitAmount.IsZero()
 }
 
+// AddTrade adds the trade to the position, records the position delta in the audit log
+// and then emits the update callbacks.
+// The delta is captured in the same critical section of the update, so concurrent trades don't interleave it.
 func (p *Position) AddTrade(td Trade) (profit fixedpoint.Value, netProfit fixedpoint.Value, madeProfit bool) {
+	p.Lock()
+	delta := PositionDelta{
+		Trade:             td,
+		BeforeBase:        p.Base,
+		BeforeAverageCost: p.AverageCost,
+	}
+
+	profit, netProfit, madeProfit = p.addTrade(td)
+
+	delta.AfterBase = p.Base
+	delta.AfterAverageCost = p.AverageCost
+	delta.Profit = profit
+	delta.NetProfit = netProfit
+	p.appendHistory(delta)
+	p.Unlock()
+
+	p.EmitUpdate(delta)
+	return profit, netProfit, madeProfit
+}
+
+// addTrade updates the position with the trade, the caller must hold the lock.
+func (p *Position) addTrade(td Trade) (profit fixedpoint.Value, netProfit fixedpoint.Value, madeProfit bool) {
 	price := td.Price
 	quantity := td.Quantity
 	quoteQuantity := td.QuoteQuantity
@@ -520,16 +725,17 @@ func (p *Position) AddTrade(td Trade) (profit fixedpoint.Value, netProfit fixedp
 		}
 	}
 
-	p.Lock()
-	defer p.Unlock()
-
-	// update changedAt field before we unlock in the defer func
+	// update changedAt field before the caller unlocks
 	defer func() {
 		p.ChangedAt = td.Time.Time()
 	}()
 
 	p.addTradeFee(td)
 
+	if p.CostMethod == CostMethodFIFO {
+		return p.addTradeFIFO(td, quantity, quoteQuantity, feeInQuote)
+	}
+
 	// Base > 0 means we're in long position
 	// Base < 0  means we're in short position
 	switch td.Side {
@@ -624,3 +830,108 @@ func (p *Position) AddTrade(td Trade) (profit fixedpoint.Value, netProfit fixedp
 
 	return fixedpoint.Zero, fixedpoint.Zero, false
 }
+
+// addTradeFIFO updates the position with the FIFO cost method,
+// the closing quantity is matched against the opened lots from the oldest one.
+// the caller must hold the lock.
+func (p *Position) addTradeFIFO(
+	td Trade, quantity, quoteQuantity, feeInQuote fixedpoint.Value,
+) (profit fixedpoint.Value, netProfit fixedpoint.Value, madeProfit bool) {
+	price := td.Price
+	isBuy := td.Side == SideTypeBuy
+	remaining := quantity
+
+	// the trade is in the opposite direction of the position, close the lots first
+	var closingFee fixedpoint.Value
+	if (isBuy && p.Base.Sign() < 0) || (!isBuy && p.Base.Sign() > 0) {
+		// the position was opened before the lots were tracked, e.g. the cost method was switched,
+		// use the current average cost as the opened lot
+		if len(p.Lots) == 0 {
+			p.Lots = append(p.Lots, PositionLot{
+				Quantity:         p.Base.Abs(),
+				Price:            p.AverageCost,
+				ApproximatePrice: p.ApproximateAverageCost,
+			})
+		}
+
+		for len(p.Lots) > 0 && remaining.Sign() > 0 {
+			lot := &p.Lots[0]
+			closedQuantity := fixedpoint.Min(lot.Quantity, remaining)
+			if isBuy {
+				profit = profit.Add(lot.Price.Sub(price).Mul(closedQuantity))
+				netProfit = netProfit.Add(lot.ApproximatePrice.Sub(price).Mul(closedQuantity))
+			} else {
+				profit = profit.Add(price.Sub(lot.Price).Mul(closedQuantity))
+				netProfit = netProfit.Add(price.Sub(lot.ApproximatePrice).Mul(closedQuantity))
+			}
+
+			lot.Quantity = lot.Quantity.Sub(closedQuantity)
+			remaining = remaining.Sub(closedQuantity)
+			if lot.Quantity.Sign() <= 0 {
+				p.Lots = p.Lots[1:]
+			}
+		}
+
+		// when the trade flips the position, only the fee of the closed quantity is deducted from the profit,
+		// the rest of the fee is carried into the new lot
+		closingFee = feeInQuote
+		if remaining.Sign() > 0 {
+			closingFee = feeInQuote.Mul(quantity.Sub(remaining)).Div(quantity)
+		}
+
+		netProfit = netProfit.Sub(closingFee)
+		p.AccumulatedProfit = p.AccumulatedProfit.Add(profit)
+		madeProfit = true
+	}
+
+	if remaining.Sign() > 0 {
+		if len(p.Lots) == 0 {
+			p.OpenedAt = td.Time.Time()
+		}
+
+		approximatePrice := price
+		if madeProfit {
+			openingFee := feeInQuote.Sub(closingFee)
+			if isBuy {
+				approximatePrice = price.Add(openingFee.Div(remaining))
+			} else {
+				approximatePrice = price.Sub(openingFee.Div(remaining))
+			}
+		} else if quantity.Sign() > 0 {
+			if isBuy {
+				approximatePrice = quoteQuantity.Add(feeInQuote).Div(quantity)
+			} else {
+				approximatePrice = quoteQuantity.Sub(feeInQuote).Div(quantity)
+			}
+		}
+
+		p.Lots = append(p.Lots, PositionLot{
+			Quantity:         remaining,
+			Price:            price,
+			ApproximatePrice: approximatePrice,
+		})
+	}
+
+	if isBuy {
+		p.Base = p.Base.Add(quantity)
+		p.Quote = p.Quote.Sub(quoteQuantity)
+	} else {
+		p.Base = p.Base.Sub(quantity)
+		p.Quote = p.Quote.Add(quoteQuantity)
+	}
+
+	// the average cost reflects the remaining lots
+	if len(p.Lots) > 0 {
+		var totalQuantity, totalCost, totalApproximateCost fixedpoint.Value
+		for _, lot := range p.Lots {
+			totalQuantity = totalQuantity.Add(lot.Quantity)
+			totalCost = totalCost.Add(lot.Price.Mul(lot.Quantity))
+			totalApproximateCost = totalApproximateCost.Add(lot.ApproximatePrice.Mul(lot.Quantity))
+		}
+
+		p.AverageCost = totalCost.Div(totalQuantity)
+		p.ApproximateAverageCost = totalApproximateCost.Div(totalQuantity)
+	}
+
+	return profit, netProfit, madeProfit
+}
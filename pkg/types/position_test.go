@@ -1,6 +1,7 @@
 package types
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -325,3 +326,320 @@ func TestPosition_SetClosing(t *testing.T) {
 	ret = p.SetClosing(false)
 	assert.True(t, ret)
 }
+
+func TestPosition_BreakEvenPrice(t *testing.T) {
+	feeRate := fixedpoint.NewFromFloat(0.001)
+
+	t.Run("long position", func(t *testing.T) {
+		pos := &Position{
+			Symbol:        "BTCUSDT",
+			BaseCurrency:  "BTC",
+			QuoteCurrency: "USDT",
+			Base:          fixedpoint.NewFromFloat(1.0),
+			AverageCost:   fixedpoint.NewFromFloat(10000.0),
+		}
+
+		price := pos.BreakEvenPrice(feeRate)
+		assert.True(t, price.Compare(pos.AverageCost) > 0, "long break-even price should be above the average cost")
+		assert.InDelta(t, 10000.0*1.001/0.999, price.Float64(), 1e-4)
+	})
+
+	t.Run("short position", func(t *testing.T) {
+		pos := &Position{
+			Symbol:        "BTCUSDT",
+			BaseCurrency:  "BTC",
+			QuoteCurrency: "USDT",
+			Base:          fixedpoint.NewFromFloat(-1.0),
+			AverageCost:   fixedpoint.NewFromFloat(10000.0),
+		}
+
+		price := pos.BreakEvenPrice(feeRate)
+		assert.True(t, price.Compare(pos.AverageCost) < 0, "short break-even price should be below the average cost")
+		assert.InDelta(t, 10000.0*0.999/1.001, price.Float64(), 1e-4)
+	})
+
+	t.Run("closed position", func(t *testing.T) {
+		pos := NewPosition("BTCUSDT", "BTC", "USDT")
+		assert.Equal(t, fixedpoint.Zero, pos.BreakEvenPrice(feeRate))
+	})
+}
+
+func TestPosition_CostMethod(t *testing.T) {
+	trades := []Trade{
+		{
+			Side:          SideTypeBuy,
+			Price:         fixedpoint.NewFromInt(100),
+			Quantity:      fixedpoint.One,
+			QuoteQuantity: fixedpoint.NewFromInt(100),
+		},
+		{
+			Side:          SideTypeBuy,
+			Price:         fixedpoint.NewFromInt(200),
+			Quantity:      fixedpoint.One,
+			QuoteQuantity: fixedpoint.NewFromInt(200),
+		},
+		{
+			Side:          SideTypeSell,
+			Price:         fixedpoint.NewFromInt(250),
+			Quantity:      fixedpoint.One,
+			QuoteQuantity: fixedpoint.NewFromInt(250),
+		},
+	}
+
+	t.Run("average cost", func(t *testing.T) {
+		pos := NewPosition("BTCUSDT", "BTC", "USDT")
+		profit, _, madeProfit := pos.AddTrades(trades)
+		assert.True(t, madeProfit)
+		assert.Equal(t, "100", profit.String())
+		assert.Equal(t, "150", pos.AverageCost.String())
+		assert.Equal(t, "1", pos.Base.String())
+	})
+
+	t.Run("fifo", func(t *testing.T) {
+		pos := NewPosition("BTCUSDT", "BTC", "USDT")
+		pos.SetCostMethod(CostMethodFIFO)
+		profit, _, madeProfit := pos.AddTrades(trades)
+		assert.True(t, madeProfit)
+		assert.Equal(t, "150", profit.String())
+		assert.Equal(t, "150", pos.AccumulatedProfit.String())
+		assert.Equal(t, "200", pos.AverageCost.String())
+		assert.Equal(t, "1", pos.Base.String())
+		if assert.Len(t, pos.Lots, 1) {
+			assert.Equal(t, "200", pos.Lots[0].Price.String())
+		}
+
+		// flip the position into short
+		profit, _, madeProfit = pos.AddTrade(Trade{
+			Side:          SideTypeSell,
+			Price:         fixedpoint.NewFromInt(150),
+			Quantity:      fixedpoint.NewFromInt(2),
+			QuoteQuantity: fixedpoint.NewFromInt(300),
+		})
+		assert.True(t, madeProfit)
+		assert.Equal(t, "-50", profit.String())
+		assert.Equal(t, "-1", pos.Base.String())
+		assert.Equal(t, "150", pos.AverageCost.String())
+	})
+
+	t.Run("fifo flip with fee", func(t *testing.T) {
+		pos := NewPosition("BTCUSDT", "BTC", "USDT")
+		pos.SetCostMethod(CostMethodFIFO)
+		pos.SetFeeRate(ExchangeFee{TakerFeeRate: fixedpoint.NewFromFloat(0.01)})
+		pos.AddTrade(Trade{
+			Side:          SideTypeBuy,
+			Price:         fixedpoint.NewFromInt(100),
+			Quantity:      fixedpoint.One,
+			QuoteQuantity: fixedpoint.NewFromInt(100),
+		})
+
+		// the fee of 3 USDT is split into 1.5 USDT for the closed long and 1.5 USDT for the new short lot
+		profit, netProfit, madeProfit := pos.AddTrade(Trade{
+			Side:          SideTypeSell,
+			Price:         fixedpoint.NewFromInt(150),
+			Quantity:      fixedpoint.NewFromInt(2),
+			QuoteQuantity: fixedpoint.NewFromInt(300),
+			Fee:           fixedpoint.NewFromFloat(0.01),
+			FeeCurrency:   "BNB",
+		})
+		assert.True(t, madeProfit)
+		assert.Equal(t, "50", profit.String())
+		assert.Equal(t, "48.5", netProfit.String())
+		assert.Equal(t, "-1", pos.Base.String())
+		assert.Equal(t, "150", pos.AverageCost.String())
+		assert.Equal(t, "148.5", pos.ApproximateAverageCost.String())
+		if assert.Len(t, pos.Lots, 1) {
+			assert.Equal(t, "148.5", pos.Lots[0].ApproximatePrice.String())
+		}
+	})
+
+	t.Run("fifo flip without lots", func(t *testing.T) {
+		// the position was opened with the average cost method
+		pos := NewPosition("BTCUSDT", "BTC", "USDT")
+		pos.AddTrade(Trade{
+			Side:          SideTypeBuy,
+			Price:         fixedpoint.NewFromInt(100),
+			Quantity:      fixedpoint.One,
+			QuoteQuantity: fixedpoint.NewFromInt(100),
+		})
+
+		pos.SetCostMethod(CostMethodFIFO)
+		profit, _, madeProfit := pos.AddTrade(Trade{
+			Side:          SideTypeSell,
+			Price:         fixedpoint.NewFromInt(150),
+			Quantity:      fixedpoint.NewFromInt(2),
+			QuoteQuantity: fixedpoint.NewFromInt(300),
+		})
+		assert.True(t, madeProfit)
+		assert.Equal(t, "50", profit.String())
+		assert.Equal(t, "-1", pos.Base.String())
+		assert.Equal(t, "150", pos.AverageCost.String())
+		if assert.Len(t, pos.Lots, 1) {
+			assert.Equal(t, "1", pos.Lots[0].Quantity.String())
+		}
+	})
+}
+
+func TestPosition_History(t *testing.T) {
+	pos := NewPosition("BTCUSDT", "BTC", "USDT")
+	pos.SetHistorySize(2)
+
+	var deltas []PositionDelta
+	pos.OnUpdate(func(delta PositionDelta) {
+		deltas = append(deltas, delta)
+	})
+
+	trades := []Trade{
+		{
+			ID:            1,
+			Side:          SideTypeBuy,
+			Price:         fixedpoint.NewFromInt(100),
+			Quantity:      fixedpoint.One,
+			QuoteQuantity: fixedpoint.NewFromInt(100),
+		},
+		{
+			ID:            2,
+			Side:          SideTypeBuy,
+			Price:         fixedpoint.NewFromInt(200),
+			Quantity:      fixedpoint.One,
+			QuoteQuantity: fixedpoint.NewFromInt(200),
+		},
+		{
+			ID:            3,
+			Side:          SideTypeSell,
+			Price:         fixedpoint.NewFromInt(250),
+			Quantity:      fixedpoint.One,
+			QuoteQuantity: fixedpoint.NewFromInt(250),
+		},
+	}
+	pos.AddTrades(trades)
+
+	if assert.Len(t, deltas, 3) {
+		assert.Equal(t, uint64(1), deltas[0].Trade.ID)
+		assert.Equal(t, "0", deltas[0].BeforeBase.String())
+		assert.Equal(t, "1", deltas[0].AfterBase.String())
+		assert.Equal(t, "100", deltas[0].AfterAverageCost.String())
+	}
+
+	history := pos.History()
+	if assert.Len(t, history, 2, "the oldest delta should be dropped") {
+		assert.Equal(t, uint64(2), history[0].Trade.ID)
+		assert.Equal(t, "1", history[0].BeforeBase.String())
+		assert.Equal(t, "2", history[0].AfterBase.String())
+		assert.Equal(t, "100", history[0].BeforeAverageCost.String())
+		assert.Equal(t, "150", history[0].AfterAverageCost.String())
+
+		assert.Equal(t, uint64(3), history[1].Trade.ID)
+		assert.Equal(t, "2", history[1].BeforeBase.String())
+		assert.Equal(t, "1", history[1].AfterBase.String())
+		assert.Equal(t, "100", history[1].Profit.String())
+	}
+}
+
+func TestPosition_History_Ring(t *testing.T) {
+	addTrades := func(pos *Position, from, to uint64) {
+		for id := from; id <= to; id++ {
+			pos.AddTrade(Trade{
+				ID:            id,
+				Side:          SideTypeBuy,
+				Price:         fixedpoint.NewFromInt(100),
+				Quantity:      fixedpoint.One,
+				QuoteQuantity: fixedpoint.NewFromInt(100),
+			})
+		}
+	}
+
+	historyIDs := func(pos *Position) (ids []uint64) {
+		for _, delta := range pos.History() {
+			ids = append(ids, delta.Trade.ID)
+		}
+		return ids
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		pos := NewPosition("BTCUSDT", "BTC", "USDT")
+		addTrades(pos, 1, 3)
+		assert.Empty(t, pos.History())
+	})
+
+	t.Run("wraps around", func(t *testing.T) {
+		pos := NewPosition("BTCUSDT", "BTC", "USDT")
+		pos.SetHistorySize(3)
+
+		addTrades(pos, 1, 7)
+		assert.Equal(t, []uint64{5, 6, 7}, historyIDs(pos))
+	})
+
+	t.Run("resize keeps the latest deltas", func(t *testing.T) {
+		pos := NewPosition("BTCUSDT", "BTC", "USDT")
+		pos.SetHistorySize(3)
+		addTrades(pos, 1, 4)
+
+		pos.SetHistorySize(2)
+		assert.Equal(t, []uint64{3, 4}, historyIDs(pos))
+
+		pos.SetHistorySize(4)
+		addTrades(pos, 5, 7)
+		assert.Equal(t, []uint64{4, 5, 6, 7}, historyIDs(pos))
+
+		pos.SetHistorySize(0)
+		addTrades(pos, 8, 8)
+		assert.Empty(t, pos.History())
+	})
+}
+
+func TestPosition_History_Concurrent(t *testing.T) {
+	pos := NewPosition("BTCUSDT", "BTC", "USDT")
+	pos.SetHistorySize(100)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(id uint64) {
+			defer wg.Done()
+			pos.AddTrade(Trade{
+				ID:            id,
+				Side:          SideTypeBuy,
+				Price:         fixedpoint.NewFromInt(100),
+				Quantity:      fixedpoint.One,
+				QuoteQuantity: fixedpoint.NewFromInt(100),
+			})
+		}(uint64(i))
+	}
+	wg.Wait()
+
+	// each delta starts from the base of the previous one
+	history := pos.History()
+	if assert.Len(t, history, 100) {
+		for i, delta := range history {
+			assert.Equal(t, int64(i), delta.BeforeBase.Int64())
+			assert.Equal(t, int64(i+1), delta.AfterBase.Int64())
+		}
+	}
+}
+
+func TestPosition_AverageCostRounded(t *testing.T) {
+	market := Market{
+		Symbol:   "BTCUSDT",
+		TickSize: fixedpoint.MustNewFromString("0.1"),
+	}
+
+	t.Run("long position is rounded down", func(t *testing.T) {
+		pos := &Position{Base: fixedpoint.NewFromFloat(1.0), AverageCost: fixedpoint.MustNewFromString("30000.037")}
+		assert.Equal(t, "30000", pos.AverageCostRounded(market).String())
+	})
+
+	t.Run("short position is rounded up", func(t *testing.T) {
+		pos := &Position{Base: fixedpoint.NewFromFloat(-1.0), AverageCost: fixedpoint.MustNewFromString("30000.037")}
+		assert.Equal(t, "30000.1", pos.AverageCostRounded(market).String())
+	})
+
+	t.Run("non-decimal tick size", func(t *testing.T) {
+		pos := &Position{Base: fixedpoint.NewFromFloat(1.0), AverageCost: fixedpoint.MustNewFromString("30000.37")}
+		assert.Equal(t, "30000.25", pos.AverageCostRounded(Market{TickSize: fixedpoint.MustNewFromString("0.25")}).String())
+	})
+
+	t.Run("without tick size", func(t *testing.T) {
+		pos := &Position{Base: fixedpoint.NewFromFloat(1.0), AverageCost: fixedpoint.MustNewFromString("30000.037")}
+		assert.Equal(t, "30000.037", pos.AverageCostRounded(Market{}).String())
+	})
+}
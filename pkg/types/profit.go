@@ -129,6 +129,11 @@ func (p *Profit) SlackAttachment() slack.Attachment {
 	}
 }
 
+// NotificationKey returns the key for coalescing the profit notifications of the same symbol
+func (p *Profit) NotificationKey() string {
+	return "profit " + p.Symbol
+}
+
 func (p *Profit) PlainText() string {
 	var emoji string
 	if !p.ProfitMargin.IsZero() {
@@ -270,6 +275,11 @@ func (s *ProfitStats) ResetToday(t time.Time) {
 	s.TodaySince = beginningOfTheDay.Unix()
 }
 
+// NotificationKey returns the key for coalescing the profit stats notifications of the same symbol
+func (s *ProfitStats) NotificationKey() string {
+	return "profit stats " + s.Symbol
+}
+
 func (s *ProfitStats) PlainText() string {
 	since := time.Unix(s.AccumulatedSince, 0).Local()
 	return fmt.Sprintf("%s Profit Today\n"+
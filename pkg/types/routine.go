@@ -0,0 +1,36 @@
+package types
+
+import (
+	"context"
+	"sync"
+)
+
+type routineGroupContextKey struct{}
+
+// WithRoutineGroup returns a context carrying the wait group,
+// the goroutines started by GoWithContext with the context (or its children) are added to the wait group,
+// so that the owner of the wait group can wait for them to return.
+func WithRoutineGroup(parent context.Context, wg *sync.WaitGroup) context.Context {
+	return context.WithValue(parent, routineGroupContextKey{}, wg)
+}
+
+// RoutineGroupFromContext returns the wait group carried by the context, or nil if there is none.
+func RoutineGroupFromContext(ctx context.Context) *sync.WaitGroup {
+	wg, _ := ctx.Value(routineGroupContextKey{}).(*sync.WaitGroup)
+	return wg
+}
+
+// GoWithContext runs f in a new goroutine, the goroutine is tracked by the wait group of the context if there is one.
+func GoWithContext(ctx context.Context, f func()) {
+	wg := RoutineGroupFromContext(ctx)
+	if wg == nil {
+		go f()
+		return
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		f()
+	}()
+}
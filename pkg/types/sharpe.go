@@ -2,6 +2,8 @@ package types
 
 import (
 	"math"
+
+	"github.com/c9s/bbgo/pkg/datatype/floats"
 )
 
 // Sharpe: Calcluates the sharpe ratio of access returns
@@ -33,6 +35,30 @@ func Sharpe(returns Series, periods int, annualize bool, smart bool) float64 {
 	return result
 }
 
+// RollingSharpe: Calculates the sharpe ratio over a sliding window of the returns
+//
+// The result is in chronological order and has the same length as the returns,
+// the values are zero until the window is filled with enough samples.
+//
+// @param returns (Series): Series of profit/loss percentage every specific interval
+// @param window (int): the number of samples of the sliding window
+// @param periods (int): Freq. of returns (252/365 for daily, 12 for monthy, 1 for annually)
+// @param annualize (bool): return annualize sharpe?
+func RollingSharpe(returns Series, window int, periods int, annualize bool) floats.Slice {
+	num := returns.Length()
+	result := make(floats.Slice, num)
+	if window <= 0 {
+		return result
+	}
+
+	data := Reverse(returns)
+	for i := window - 1; i < num; i++ {
+		result[i] = Sharpe(data[i-window+1:i+1], periods, annualize, false)
+	}
+
+	return result
+}
+
 func avgReturnRate(returnRate float64, periods int) float64 {
 	return math.Pow(1.+returnRate, 1./float64(periods)) - 1.
 }
@@ -27,3 +27,21 @@ func TestSharpe(t *testing.T) {
 	output = Sharpe(a, 252, true, false)
 	assert.InDelta(t, output, 10.7289, 0.0001)
 }
+
+func TestRollingSharpe(t *testing.T) {
+	var a Series = &floats.Slice{0.01, 0.1, 0.001, -0.02, 0.03}
+	output := RollingSharpe(a, 3, 252, false)
+	if assert.Len(t, output, 5) {
+		assert.Equal(t, 0.0, output[0])
+		assert.Equal(t, 0.0, output[1])
+
+		// the same window as TestSharpe
+		assert.InDelta(t, 0.67586, output[2], 0.0001)
+
+		// window [0.001, -0.02, 0.03]: mean = 0.0036667, stdev(ddof=1) = 0.0251064
+		assert.InDelta(t, 0.0036667/0.0251064, output[4], 0.0001)
+	}
+
+	output = RollingSharpe(a, 3, 252, true)
+	assert.InDelta(t, 10.7289, output[2], 0.0001)
+}
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -17,6 +18,7 @@ import pandas as pd
 print(qx.stats.sortino(pd.Series([0.01, -0.03, 0.1, -0.02, 0.001]), 0.00, 0, False, False))
 print(qx.stats.sortino(pd.Series([0.01, -0.03, 0.1, -0.02, 0.001]), 0.03, 252, False, False))
 print(qx.stats.sortino(pd.Series([0.01, -0.03, 0.1, -0.02, 0.001]), 0.03, 252, True, False))
+print(qx.stats.sortino(pd.Series([0.01, 0.03, 0.1, 0.02, 0.001]), 0.00, 0, False, False))
 */
 func TestSortino(t *testing.T) {
 	var a Series = &floats.Slice{0.01, -0.03, 0.1, -0.02, 0.001}
@@ -26,4 +28,9 @@ func TestSortino(t *testing.T) {
 	assert.InDelta(t, output, 0.74597, 0.0001)
 	output = Sortino(a, 0.03, 252, true, false)
 	assert.InDelta(t, output, 11.84192, 0.0001)
+
+	// without any negative return, the downside deviation is zero
+	var b Series = &floats.Slice{0.01, 0.03, 0.1, 0.02, 0.001}
+	output = Sortino(b, 0, 0, false, false)
+	assert.True(t, math.IsInf(output, 1))
 }
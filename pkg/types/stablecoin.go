@@ -0,0 +1,129 @@
+package types
+
+import (
+	"sync"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+// StableCoinEquivalence maps the USD stable coins to their USD peg ratio,
+// so that the balances quoted in different stable coins can be accounted as one USD figure.
+type StableCoinEquivalence struct {
+	// Pegs is the peg ratio of the stable coin to USD, e.g. USDT: 1.0
+	Pegs map[string]fixedpoint.Value `json:"pegs,omitempty" yaml:"pegs,omitempty"`
+
+	// DepegTolerance is the maximum deviation of the market price from the peg ratio,
+	// when the market price deviates more than the tolerance, the market price is used instead of the peg ratio.
+	// Zero means the peg ratio is always used.
+	DepegTolerance fixedpoint.Value `json:"depegTolerance,omitempty" yaml:"depegTolerance,omitempty"`
+}
+
+// stableCoinEquivalence is the stable coin equivalence used by the USD conversions,
+// it's never mutated after it's set, so it's safe to read it concurrently once it's loaded under the lock.
+var stableCoinEquivalence = NewStableCoinEquivalence()
+var stableCoinEquivalenceMutex sync.RWMutex
+
+// SetStableCoinEquivalence replaces the stable coin equivalence used by the USD conversions,
+// the given equivalence is copied, so changing it afterward does not affect the conversions.
+func SetStableCoinEquivalence(e *StableCoinEquivalence) {
+	e = e.Clone()
+
+	stableCoinEquivalenceMutex.Lock()
+	stableCoinEquivalence = e
+	stableCoinEquivalenceMutex.Unlock()
+}
+
+// GetStableCoinEquivalence returns a copy of the stable coin equivalence used by the USD conversions
+func GetStableCoinEquivalence() *StableCoinEquivalence {
+	return loadStableCoinEquivalence().Clone()
+}
+
+func loadStableCoinEquivalence() *StableCoinEquivalence {
+	stableCoinEquivalenceMutex.RLock()
+	defer stableCoinEquivalenceMutex.RUnlock()
+	return stableCoinEquivalence
+}
+
+// StableCoinPriceInUSD returns the USD price of the given stable coin with the stable coin equivalence in use,
+// ok is false if the currency is not a known stable coin
+func StableCoinPriceInUSD(currency string, prices PriceMap) (fixedpoint.Value, bool) {
+	return loadStableCoinEquivalence().PriceInUSD(currency, prices)
+}
+
+func NewStableCoinEquivalence() *StableCoinEquivalence {
+	pegs := make(map[string]fixedpoint.Value, len(USDFiatCurrencies))
+	for _, currency := range USDFiatCurrencies {
+		pegs[currency] = fixedpoint.One
+	}
+
+	return &StableCoinEquivalence{Pegs: pegs}
+}
+
+// Clone returns a deep copy of the equivalence
+func (e *StableCoinEquivalence) Clone() *StableCoinEquivalence {
+	pegs := make(map[string]fixedpoint.Value, len(e.Pegs))
+	for currency, ratio := range e.Pegs {
+		pegs[currency] = ratio
+	}
+
+	return &StableCoinEquivalence{
+		Pegs:           pegs,
+		DepegTolerance: e.DepegTolerance,
+	}
+}
+
+// SetPeg overrides the peg ratio of the given stable coin
+func (e *StableCoinEquivalence) SetPeg(currency string, ratio fixedpoint.Value) {
+	if e.Pegs == nil {
+		e.Pegs = make(map[string]fixedpoint.Value)
+	}
+
+	e.Pegs[currency] = ratio
+}
+
+// Peg returns the peg ratio of the given currency, ok is false if the currency is not a known stable coin
+func (e *StableCoinEquivalence) Peg(currency string) (fixedpoint.Value, bool) {
+	ratio, ok := e.Pegs[currency]
+	return ratio, ok
+}
+
+// PriceInUSD returns the USD price of the given stable coin.
+// The peg ratio is used unless the depeg tolerance is set and the market price
+// found in the given prices deviates from the peg ratio more than the tolerance.
+func (e *StableCoinEquivalence) PriceInUSD(currency string, prices PriceMap) (fixedpoint.Value, bool) {
+	ratio, ok := e.Peg(currency)
+	if !ok {
+		return fixedpoint.Zero, false
+	}
+
+	if e.DepegTolerance.IsZero() || currency == "USD" {
+		return ratio, true
+	}
+
+	// the stable coin market price is quoted in other stable coins, e.g. USDCUSDT
+	for _, quote := range USDFiatCurrencies {
+		if quote == currency {
+			continue
+		}
+
+		if price, ok := prices[currency+quote]; ok && price.Sign() > 0 {
+			if price.Sub(ratio).Abs().Compare(e.DepegTolerance) > 0 {
+				return price, true
+			}
+
+			return ratio, true
+		}
+	}
+
+	return ratio, true
+}
+
+// InUSD converts the amount of the given stable coin into USD
+func (e *StableCoinEquivalence) InUSD(currency string, amount fixedpoint.Value, prices PriceMap) (fixedpoint.Value, bool) {
+	price, ok := e.PriceInUSD(currency, prices)
+	if !ok {
+		return fixedpoint.Zero, false
+	}
+
+	return amount.Mul(price), true
+}
@@ -0,0 +1,106 @@
+package types
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+func TestBalanceMap_Assets_StableCoinEquivalence(t *testing.T) {
+	balances := BalanceMap{
+		"USDT": Balance{Currency: "USDT", Available: number(100.0)},
+		"USDC": Balance{Currency: "USDC", Available: number(50.0)},
+	}
+
+	t.Run("default pegs", func(t *testing.T) {
+		assets := balances.Assets(PriceMap{}, time.Time{})
+		assert.Equal(t, number(150.0), assets.InUSD())
+	})
+
+	t.Run("within depeg tolerance", func(t *testing.T) {
+		defer SetStableCoinEquivalence(GetStableCoinEquivalence())
+
+		e := NewStableCoinEquivalence()
+		e.DepegTolerance = fixedpoint.MustNewFromString("0.005")
+		SetStableCoinEquivalence(e)
+
+		assets := balances.Assets(PriceMap{"USDCUSDT": fixedpoint.MustNewFromString("0.999")}, time.Time{})
+		assert.Equal(t, number(150.0), assets.InUSD())
+	})
+
+	t.Run("depegged", func(t *testing.T) {
+		defer SetStableCoinEquivalence(GetStableCoinEquivalence())
+
+		e := NewStableCoinEquivalence()
+		e.DepegTolerance = fixedpoint.MustNewFromString("0.005")
+		SetStableCoinEquivalence(e)
+
+		assets := balances.Assets(PriceMap{"USDCUSDT": fixedpoint.MustNewFromString("0.95")}, time.Time{})
+		assert.Equal(t, fixedpoint.MustNewFromString("0.95"), assets["USDC"].PriceInUSD)
+		assert.Equal(t, fixedpoint.MustNewFromString("147.5"), assets.InUSD())
+	})
+
+	t.Run("overridden peg", func(t *testing.T) {
+		defer SetStableCoinEquivalence(GetStableCoinEquivalence())
+
+		e := NewStableCoinEquivalence()
+		e.SetPeg("USDC", fixedpoint.MustNewFromString("0.9"))
+		SetStableCoinEquivalence(e)
+
+		// the equivalence in use is a copy, changing the given one does not affect the conversion
+		e.SetPeg("USDC", fixedpoint.MustNewFromString("0.5"))
+
+		assets := balances.Assets(PriceMap{}, time.Time{})
+		assert.Equal(t, number(145.0), assets.InUSD())
+	})
+}
+
+func TestStableCoinEquivalence_InUSD(t *testing.T) {
+	e := NewStableCoinEquivalence()
+
+	v, ok := e.InUSD("USDC", number(10.0), nil)
+	assert.True(t, ok)
+	assert.Equal(t, number(10.0), v)
+
+	_, ok = e.InUSD("BTC", number(1.0), nil)
+	assert.False(t, ok)
+}
+
+func TestSetStableCoinEquivalence_Concurrent(t *testing.T) {
+	defer SetStableCoinEquivalence(GetStableCoinEquivalence())
+
+	balances := BalanceMap{
+		"USDT": Balance{Currency: "USDT", Available: number(100.0)},
+		"USDC": Balance{Currency: "USDC", Available: number(50.0)},
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			e := NewStableCoinEquivalence()
+			e.SetPeg("USDC", fixedpoint.MustNewFromString("0.99"))
+			SetStableCoinEquivalence(e)
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			_ = balances.Assets(PriceMap{}, time.Time{})
+			GetStableCoinEquivalence().SetPeg("USDT", fixedpoint.Zero)
+		}
+	}()
+
+	wg.Wait()
+
+	// the copies returned by GetStableCoinEquivalence are not in use
+	price, ok := StableCoinPriceInUSD("USDT", nil)
+	assert.True(t, ok)
+	assert.Equal(t, fixedpoint.One, price)
+}
@@ -34,6 +34,16 @@ func (s *StandardStream) EmitDisconnect() {
 	}
 }
 
+func (s *StandardStream) OnReconnect(cb func()) {
+	s.reconnectCallbacks = append(s.reconnectCallbacks, cb)
+}
+
+func (s *StandardStream) EmitReconnect() {
+	for _, cb := range s.reconnectCallbacks {
+		cb()
+	}
+}
+
 func (s *StandardStream) OnAuth(cb func()) {
 	s.authCallbacks = append(s.authCallbacks, cb)
 }
@@ -201,6 +211,8 @@ type StandardStreamEventHub interface {
 
 	OnDisconnect(cb func())
 
+	OnReconnect(cb func())
+
 	OnAuth(cb func())
 
 	OnRawMessage(cb func(raw []byte))
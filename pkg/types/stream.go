@@ -2,9 +2,11 @@ package types
 
 import (
 	"context"
+	"math/rand"
 	"net"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -55,6 +57,12 @@ type Unsubscriber interface {
 	Unsubscribe()
 }
 
+type ChannelUnsubscriber interface {
+	// UnsubscribeChannel sends the unsubscribe request of the channel and removes it from the subscriptions,
+	// the connection is not re-connected.
+	UnsubscribeChannel(channel Channel, symbol string, options SubscribeOptions) error
+}
+
 type EndpointCreator func(ctx context.Context) (string, error)
 
 type Parser func(message []byte) (interface{}, error)
@@ -74,6 +82,24 @@ type StandardStream struct {
 	dispatcher   Dispatcher
 	pingInterval time.Duration
 
+	// staleTimeout is the max duration without any message from the server,
+	// the connection is considered stale and is re-connected once the timeout is exceeded.
+	// pongs are not counted since they keep the connection alive even when the stream stalls.
+	// zero disables the stale connection detection.
+	staleTimeout time.Duration
+
+	// lastMessageTime is the unix nano time of the last message received from the server
+	lastMessageTime int64
+
+	// reconnectCoolDown is the delay before re-connecting, it's the initial delay when the backoff is enabled
+	reconnectCoolDown time.Duration
+
+	// reconnectMaxCoolDown enables the exponential backoff of the re-connect delay, it's the cap of the delay
+	reconnectMaxCoolDown time.Duration
+
+	// reconnectMaxRetries is the max number of the consecutive re-connect failures, zero means unlimited
+	reconnectMaxRetries int
+
 	endpointCreator EndpointCreator
 
 	// Conn is the websocket connection
@@ -114,6 +140,8 @@ type StandardStream struct {
 
 	disconnectCallbacks []func()
 
+	reconnectCallbacks []func()
+
 	authCallbacks []func()
 
 	rawMessageCallbacks []func(raw []byte)
@@ -160,6 +188,7 @@ type StandardStreamEmitter interface {
 	EmitStart()
 	EmitConnect()
 	EmitDisconnect()
+	EmitReconnect()
 	EmitAuth()
 	EmitTradeUpdate(Trade)
 	EmitOrderUpdate(Order)
@@ -292,6 +321,7 @@ func (s *StandardStream) Read(ctx context.Context, conn *websocket.Conn, cancel
 			}
 
 			if !hasParser {
+				s.touch()
 				s.EmitRawMessage(message)
 				continue
 			}
@@ -300,13 +330,16 @@ func (s *StandardStream) Read(ctx context.Context, conn *websocket.Conn, cancel
 			e, err = s.parser(message)
 			if err != nil {
 				log.WithError(err).Errorf("websocket event parse error, message: %s", message)
+				s.touch()
 				// emit raw message even if occurs error, because we want anything can be detected
 				s.EmitRawMessage(message)
 				continue
 			}
 
-			// skip pong event to avoid the message like spam
+			// skip pong event to avoid the message like spam,
+			// the pong event does not count as a message for the stale connection detection
 			if _, ok := e.(*WebsocketPongEvent); !ok {
+				s.touch()
 				s.EmitRawMessage(message)
 			}
 
@@ -321,6 +354,62 @@ func (s *StandardStream) SetPingInterval(interval time.Duration) {
 	s.pingInterval = interval
 }
 
+// SetStaleTimeout sets the max duration without any message from the server,
+// the stale connection is closed by the watchdog and re-connected by the reader.
+// Note that pongs do not count, so the subscribed channels should push messages within the timeout.
+// The read deadline still covers the connection that does not even reply pongs.
+func (s *StandardStream) SetStaleTimeout(timeout time.Duration) {
+	s.staleTimeout = timeout
+}
+
+// touch updates the last message time
+func (s *StandardStream) touch() {
+	atomic.StoreInt64(&s.lastMessageTime, time.Now().UnixNano())
+}
+
+// LastMessageTime returns the time of the last message received from the server
+func (s *StandardStream) LastMessageTime() time.Time {
+	return time.Unix(0, atomic.LoadInt64(&s.lastMessageTime))
+}
+
+// watchdog closes the connection when no message arrives within the stale timeout,
+// which is the case that the read deadline can not cover since the pongs keep extending the deadline.
+// Closing the connection fails the pending read, and the reader triggers the re-connect.
+func (s *StandardStream) watchdog(
+	ctx context.Context, conn *websocket.Conn, cancel context.CancelFunc,
+) {
+	defer func() {
+		cancel()
+		log.Debug("[websocket] watchdog stopped")
+	}()
+
+	checkInterval := s.staleTimeout / 4
+	if checkInterval <= 0 {
+		checkInterval = time.Millisecond
+	}
+
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+
+		case <-ctx.Done():
+			return
+
+		case <-s.CloseC:
+			return
+
+		case <-ticker.C:
+			if elapsed := time.Since(s.LastMessageTime()); elapsed > s.staleTimeout {
+				log.Warnf("[websocket] no message received in %s, the connection is stale, closing...", elapsed)
+				_ = conn.Close()
+				return
+			}
+		}
+	}
+}
+
 func (s *StandardStream) ping(
 	ctx context.Context, conn *websocket.Conn, cancel context.CancelFunc,
 ) {
@@ -384,6 +473,27 @@ func (s *StandardStream) Resubscribe(fn func(old []Subscription) (new []Subscrip
 	return nil
 }
 
+// RemoveSubscription removes the subscription without re-connecting, it returns false if the subscription does not exist.
+// This method is thread-safe.
+func (s *StandardStream) RemoveSubscription(sub Subscription) bool {
+	s.subLock.Lock()
+	defer s.subLock.Unlock()
+
+	removed := false
+	subs := make([]Subscription, 0, len(s.Subscriptions))
+	for _, existing := range s.Subscriptions {
+		if existing == sub {
+			removed = true
+			continue
+		}
+
+		subs = append(subs, existing)
+	}
+
+	s.Subscriptions = subs
+	return removed
+}
+
 func (s *StandardStream) Subscribe(channel Channel, symbol string, options SubscribeOptions) {
 	s.subLock.Lock()
 	defer s.subLock.Unlock()
@@ -402,6 +512,38 @@ func (s *StandardStream) Reconnect() {
 	}
 }
 
+// SetReconnectBackoff enables the exponential backoff with jitter for re-connecting,
+// the delay starts from initial and doubles on each consecutive failure up to max.
+// The re-connector gives up after maxRetries consecutive failures, zero means unlimited.
+func (s *StandardStream) SetReconnectBackoff(initial, max time.Duration, maxRetries int) {
+	s.reconnectCoolDown = initial
+	s.reconnectMaxCoolDown = max
+	s.reconnectMaxRetries = maxRetries
+}
+
+// reconnectDelay returns the delay before the re-connect attempt, attempt is the number of the consecutive failures
+func (s *StandardStream) reconnectDelay(attempt int) time.Duration {
+	delay := s.reconnectCoolDown
+	if delay <= 0 {
+		delay = reconnectCoolDownPeriod
+	}
+
+	if s.reconnectMaxCoolDown <= 0 {
+		return delay
+	}
+
+	for i := 0; i < attempt && delay < s.reconnectMaxCoolDown; i++ {
+		delay *= 2
+	}
+
+	if delay > s.reconnectMaxCoolDown {
+		delay = s.reconnectMaxCoolDown
+	}
+
+	// add up to 20% jitter to avoid re-connecting all the streams at the same time
+	return delay + time.Duration(rand.Int63n(int64(delay)/5+1))
+}
+
 // Connect starts the stream and create the websocket connection
 func (s *StandardStream) Connect(ctx context.Context) error {
 	if s.beforeConnect != nil {
@@ -416,13 +558,17 @@ func (s *StandardStream) Connect(ctx context.Context) error {
 
 	// start one re-connector goroutine with the base context
 	// reconnector goroutine does not exit when the connection is closed
-	go s.reconnector(ctx)
+	GoWithContext(ctx, func() {
+		s.reconnector(ctx)
+	})
 
 	s.EmitStart()
 	return nil
 }
 
 func (s *StandardStream) reconnector(ctx context.Context) {
+	// failures is the number of the consecutive re-connect failures
+	failures := 0
 	for {
 		select {
 
@@ -433,16 +579,33 @@ func (s *StandardStream) reconnector(ctx context.Context) {
 			return
 
 		case <-s.ReconnectC:
-			log.Warnf("received reconnect signal, cooling for %s...", reconnectCoolDownPeriod)
-			time.Sleep(reconnectCoolDownPeriod)
+			delay := s.reconnectDelay(failures)
+			log.Warnf("received reconnect signal, cooling for %s...", delay)
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.CloseC:
+				return
+			case <-time.After(delay):
+			}
 
 			log.Warnf("re-connecting...")
 			if err := s.DialAndConnect(ctx); err != nil {
+				failures++
+				if s.reconnectMaxRetries > 0 && failures >= s.reconnectMaxRetries {
+					log.WithError(err).Errorf("re-connect failed %d times, give up re-connecting", failures)
+					return
+				}
+
 				log.WithError(err).Errorf("re-connect error, try to reconnect later")
 
 				// re-emit the re-connect signal if error
 				s.Reconnect()
+				continue
 			}
+
+			failures = 0
+			s.EmitReconnect()
 		}
 	}
 }
@@ -454,6 +617,7 @@ func (s *StandardStream) DialAndConnect(ctx context.Context) error {
 	}
 
 	connCtx, connCancel := s.SetConn(ctx, conn)
+	s.touch()
 	s.EmitConnect()
 
 	s.sg.Add(func() {
@@ -462,7 +626,12 @@ func (s *StandardStream) DialAndConnect(ctx context.Context) error {
 	s.sg.Add(func() {
 		s.ping(connCtx, conn, connCancel)
 	})
-	s.sg.Run()
+	if s.staleTimeout > 0 {
+		s.sg.Add(func() {
+			s.watchdog(connCtx, conn, connCancel)
+		})
+	}
+	s.sg.RunWithContext(ctx)
 	return nil
 }
 
@@ -527,8 +696,10 @@ func (s *StandardStream) Close() error {
 
 	log.Debugf("[websocket] stream closed")
 
-	// let the reader close the connection
+	// let the reader close the connection, and close it ourselves if the server does not reply the close message,
+	// otherwise the reader is blocked until the read deadline
 	<-time.After(time.Second)
+	_ = conn.Close()
 	return nil
 }
 
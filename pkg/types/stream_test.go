@@ -0,0 +1,137 @@
+package types
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+// newSilentWebsocketServer accepts the connection, pushes one message and then goes silent,
+// it never reads from the connection so the pings are not replied.
+func newSilentWebsocketServer(t *testing.T) *httptest.Server {
+	upgrader := websocket.Upgrader{}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade error: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"event":"hello"}`))
+		<-r.Context().Done()
+	}))
+}
+
+func TestStandardStream_StaleConnection(t *testing.T) {
+	server := newSilentWebsocketServer(t)
+	defer server.Close()
+
+	stream := NewStandardStream()
+	stream.SetPingInterval(time.Hour)
+	stream.SetStaleTimeout(200 * time.Millisecond)
+	stream.SetEndpointCreator(func(ctx context.Context) (string, error) {
+		return "ws" + strings.TrimPrefix(server.URL, "http"), nil
+	})
+
+	var mu sync.Mutex
+	var messages [][]byte
+	stream.OnRawMessage(func(raw []byte) {
+		mu.Lock()
+		messages = append(messages, raw)
+		mu.Unlock()
+	})
+
+	disconnectC := make(chan struct{}, 1)
+	stream.OnDisconnect(func() {
+		disconnectC <- struct{}{}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// dial without the re-connector goroutine so that the re-connect signal can be observed
+	if !assert.NoError(t, stream.DialAndConnect(ctx)) {
+		return
+	}
+	defer stream.Close()
+
+	select {
+	case <-stream.ReconnectC:
+	case <-time.After(2 * time.Second):
+		t.Fatal("re-connect is not triggered for the stale connection")
+	}
+
+	select {
+	case <-disconnectC:
+	case <-time.After(2 * time.Second):
+		t.Fatal("disconnect is not emitted for the stale connection")
+	}
+
+	mu.Lock()
+	assert.Len(t, messages, 1)
+	mu.Unlock()
+	assert.GreaterOrEqual(t, time.Since(stream.LastMessageTime()), 200*time.Millisecond)
+}
+
+func TestStandardStream_reconnectDelay(t *testing.T) {
+	stream := NewStandardStream()
+	assert.Equal(t, reconnectCoolDownPeriod, stream.reconnectDelay(0))
+	assert.Equal(t, reconnectCoolDownPeriod, stream.reconnectDelay(5))
+
+	stream.SetReconnectBackoff(time.Second, 10*time.Second, 0)
+
+	// the delay doubles on each failure with up to 20% jitter, and it's capped by the max delay
+	for attempt, expected := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second} {
+		delay := stream.reconnectDelay(attempt)
+		assert.GreaterOrEqual(t, delay, expected)
+		assert.LessOrEqual(t, delay, expected+expected/5)
+	}
+}
+
+func TestStandardStream_Connect_RoutineGroup(t *testing.T) {
+	server := newSilentWebsocketServer(t)
+	defer server.Close()
+
+	stream := NewStandardStream()
+	stream.SetEndpointCreator(func(ctx context.Context) (string, error) {
+		return "ws" + strings.TrimPrefix(server.URL, "http"), nil
+	})
+
+	var wg sync.WaitGroup
+	ctx, cancel := context.WithCancel(WithRoutineGroup(context.Background(), &wg))
+	defer cancel()
+
+	if !assert.NoError(t, stream.Connect(ctx)) {
+		return
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	// the reader, the pinger and the re-connector are tracked by the wait group
+	select {
+	case <-done:
+		t.Fatal("the stream goroutines are not tracked by the routine group")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	cancel()
+	assert.NoError(t, stream.Close())
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("the stream goroutines are not stopped after close")
+	}
+}
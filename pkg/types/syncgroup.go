@@ -1,6 +1,7 @@
 package types
 
 import (
+	"context"
 	"sync"
 )
 
@@ -51,3 +52,19 @@ func (w *SyncGroup) Run() {
 		}(fn)
 	}
 }
+
+// RunWithContext runs the routines like Run does, and the routines are also tracked by the wait group of the context,
+// see WithRoutineGroup.
+func (w *SyncGroup) RunWithContext(ctx context.Context) {
+	w.sgFuncsMu.Lock()
+	fns := w.sgFuncs
+	w.sgFuncsMu.Unlock()
+
+	for _, fn := range fns {
+		doFunc := fn
+		GoWithContext(ctx, func() {
+			defer w.wg.Done()
+			doFunc()
+		})
+	}
+}
@@ -21,3 +21,13 @@ type Ticker struct {
 func (t *Ticker) String() string {
 	return fmt.Sprintf("O:%s H:%s L:%s LAST:%s BID/ASK:%s/%s TIME:%s", t.Open, t.High, t.Low, t.Last, t.Buy, t.Sell, t.Time.String())
 }
+
+// MidPrice returns the middle price of the best bid and the best ask,
+// if one of the book sides is missing, the last price is returned.
+func (t *Ticker) MidPrice() fixedpoint.Value {
+	if t.Buy.Sign() <= 0 || t.Sell.Sign() <= 0 {
+		return t.Last
+	}
+
+	return t.Buy.Add(t.Sell).Div(fixedpoint.Two)
+}
@@ -0,0 +1,36 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+func TestTicker_MidPrice(t *testing.T) {
+	t.Run("asymmetric book", func(t *testing.T) {
+		ticker := Ticker{
+			Last: fixedpoint.NewFromFloat(101.0),
+			Buy:  fixedpoint.NewFromFloat(99.0),
+			Sell: fixedpoint.NewFromFloat(102.0),
+		}
+		assert.Equal(t, "100.5", ticker.MidPrice().String())
+	})
+
+	t.Run("missing bid", func(t *testing.T) {
+		ticker := Ticker{
+			Last: fixedpoint.NewFromFloat(101.0),
+			Sell: fixedpoint.NewFromFloat(102.0),
+		}
+		assert.Equal(t, "101", ticker.MidPrice().String())
+	})
+
+	t.Run("missing ask", func(t *testing.T) {
+		ticker := Ticker{
+			Last: fixedpoint.NewFromFloat(101.0),
+			Buy:  fixedpoint.NewFromFloat(99.0),
+		}
+		assert.Equal(t, "101", ticker.MidPrice().String())
+	})
+}
@@ -180,6 +180,11 @@ func (trade Trade) String() string {
 	)
 }
 
+// NotificationKey returns the key for coalescing the trade notifications of the same symbol
+func (trade Trade) NotificationKey() string {
+	return "trade " + trade.Symbol
+}
+
 // PlainText is used for telegram-styled messages
 func (trade Trade) PlainText() string {
 	return fmt.Sprintf("Trade %s %s %s %s @ %s, amount %s, fee %s %s",
@@ -0,0 +1,39 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// WriteTradesJSONL writes the trades in the JSON lines format, one trade per line.
+// The fixedpoint values are encoded as JSON numbers with 8 decimals, which is the precision of the trade table columns.
+func WriteTradesJSONL(w io.Writer, trades []Trade) error {
+	encoder := json.NewEncoder(w)
+	for _, trade := range trades {
+		if err := encoder.Encode(trade); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReadTradesJSONL reads the trades written by WriteTradesJSONL
+func ReadTradesJSONL(r io.Reader) ([]Trade, error) {
+	decoder := json.NewDecoder(r)
+
+	var trades []Trade
+	for {
+		var trade Trade
+		if err := decoder.Decode(&trade); err != nil {
+			if err == io.EOF {
+				return trades, nil
+			}
+
+			return nil, fmt.Errorf("trade jsonl record #%d error: %w", len(trades)+1, err)
+		}
+
+		trades = append(trades, trade)
+	}
+}
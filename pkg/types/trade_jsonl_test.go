@@ -0,0 +1,77 @@
+package types
+
+import (
+	"bytes"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/c9s/bbgo/pkg/fixedpoint"
+)
+
+func TestTradesJSONL(t *testing.T) {
+	tradeTime := time.Date(2024, 1, 2, 3, 4, 5, 678000000, time.UTC)
+	trades := []Trade{
+		{
+			GID:           1,
+			ID:            1001,
+			OrderID:       2001,
+			Exchange:      ExchangeBinance,
+			Symbol:        "BTCUSDT",
+			Price:         fixedpoint.MustNewFromString("42123.45678901"),
+			Quantity:      fixedpoint.MustNewFromString("0.00012345"),
+			QuoteQuantity: fixedpoint.MustNewFromString("5.20014078"),
+			Side:          SideTypeBuy,
+			IsBuyer:       true,
+			Time:          Time(tradeTime),
+			Fee:           fixedpoint.MustNewFromString("0.00000012"),
+			FeeCurrency:   "BTC",
+			StrategyID:    sql.NullString{String: "grid", Valid: true},
+		},
+		{
+			GID:           2,
+			ID:            1002,
+			OrderID:       2002,
+			Exchange:      ExchangeBinance,
+			Symbol:        "BTCUSDT",
+			Price:         fixedpoint.MustNewFromString("42200"),
+			Quantity:      fixedpoint.MustNewFromString("1.5"),
+			QuoteQuantity: fixedpoint.MustNewFromString("63300"),
+			Side:          SideTypeSell,
+			IsMaker:       true,
+			Time:          Time(tradeTime.Add(time.Minute)),
+			Fee:           fixedpoint.MustNewFromString("63.3"),
+			FeeCurrency:   "USDT",
+		},
+	}
+
+	var buf bytes.Buffer
+	if !assert.NoError(t, WriteTradesJSONL(&buf, trades)) {
+		return
+	}
+
+	assert.Len(t, strings.Split(strings.TrimSpace(buf.String()), "\n"), len(trades))
+
+	readTrades, err := ReadTradesJSONL(&buf)
+	if assert.NoError(t, err) && assert.Len(t, readTrades, len(trades)) {
+		for i, trade := range readTrades {
+			assert.Equal(t, trades[i].ID, trade.ID)
+			assert.Equal(t, trades[i].Exchange, trade.Exchange)
+			assert.Equal(t, trades[i].Side, trade.Side)
+			assert.True(t, trades[i].Time.Time().Equal(trade.Time.Time()))
+			assert.Equal(t, trades[i].StrategyID, trade.StrategyID)
+			assert.Equal(t, trades[i].Price.String(), trade.Price.String())
+			assert.Equal(t, trades[i].Quantity.String(), trade.Quantity.String())
+			assert.Equal(t, trades[i].QuoteQuantity.String(), trade.QuoteQuantity.String())
+			assert.Equal(t, trades[i].Fee.String(), trade.Fee.String())
+			assert.Zero(t, trades[i].Price.Compare(trade.Price))
+			assert.Zero(t, trades[i].Quantity.Compare(trade.Quantity))
+		}
+	}
+
+	_, err = ReadTradesJSONL(strings.NewReader("{\"id\":1}\n{invalid\n"))
+	assert.Error(t, err)
+}